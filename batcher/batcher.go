@@ -17,6 +17,7 @@ limitations under the License.
 package batcher
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -38,6 +39,17 @@ func (m *mutex) Lock() {
 	m.lock <- struct{}{}
 }
 
+// LockContext locks the mutex unless the context is done first, in which
+// case it returns the context's error.
+func (m *mutex) LockContext(ctx context.Context) error {
+	select {
+	case m.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (m *mutex) Unlock() {
 	<-m.lock
 }
@@ -56,13 +68,27 @@ type Batcher interface {
 	// Put adds items to the batcher.
 	Put(interface{}) error
 
+	// PutContext adds items to the batcher, returning the context's error
+	// if it is done before there is room for them.
+	PutContext(context.Context, interface{}) error
+
 	// Get retrieves a batch from the batcher. This call will block until
 	// one of the conditions for a "complete" batch is reached.
 	Get() ([]interface{}, error)
 
+	// GetContext retrieves a batch from the batcher like Get, returning the
+	// context's error if it is done before a batch is complete.
+	GetContext(context.Context) ([]interface{}, error)
+
 	// Flush forcibly completes the batch currently being built
 	Flush() error
 
+	// Close stops the batcher accepting items and completes the batch
+	// currently being built. Any calls to Put or Flush will return
+	// ErrDisposed, calls to Get will return the remaining batches and then
+	// ErrDisposed.
+	Close() error
+
 	// Dispose will dispose of the batcher. Any calls to Put or Flush
 	// will return ErrDisposed, calls to Get will return an error iff
 	// there are no more ready batches.
@@ -119,22 +145,39 @@ func New(maxTime time.Duration, maxItems, maxBytes, queueLen uint, calculate Cal
 
 // Put adds items to the batcher.
 func (b *basicBatcher) Put(item interface{}) error {
-	b.lock.Lock()
+	return b.PutContext(context.Background(), item)
+}
+
+// PutContext adds items to the batcher, returning the context's error if it
+// is done before there is room for them.
+func (b *basicBatcher) PutContext(ctx context.Context, item interface{}) error {
+	if err := b.lock.LockContext(ctx); err != nil {
+		return err
+	}
 	if b.disposed {
 		b.lock.Unlock()
 		return ErrDisposed
 	}
 
-	b.items = append(b.items, item)
+	var bytes uint
 	if b.calculateBytes != nil {
-		b.availableBytes += b.calculateBytes(item)
+		bytes = b.calculateBytes(item)
 	}
+	b.items = append(b.items, item)
+	b.availableBytes += bytes
 	if b.ready() {
 		// To guarantee ordering this MUST be in the lock, otherwise multiple
 		// flush calls could be blocked at the same time, in which case
 		// there's no guarantee each batch is placed into the channel in
 		// the proper order
-		b.flush()
+		if err := b.flushContext(ctx); err != nil {
+			// Leave the batch as it was before this item
+			b.items[len(b.items)-1] = nil
+			b.items = b.items[:len(b.items)-1]
+			b.availableBytes -= bytes
+			b.lock.Unlock()
+			return err
+		}
 	}
 
 	b.lock.Unlock()
@@ -144,6 +187,12 @@ func (b *basicBatcher) Put(item interface{}) error {
 // Get retrieves a batch from the batcher. This call will block until
 // one of the conditions for a "complete" batch is reached.
 func (b *basicBatcher) Get() ([]interface{}, error) {
+	return b.GetContext(context.Background())
+}
+
+// GetContext retrieves a batch from the batcher like Get, returning the
+// context's error if it is done before a batch is complete.
+func (b *basicBatcher) GetContext(ctx context.Context) ([]interface{}, error) {
 	// Don't check disposed yet so any items remaining in the queue
 	// will be returned properly.
 
@@ -159,6 +208,8 @@ func (b *basicBatcher) Get() ([]interface{}, error) {
 			return nil, ErrDisposed
 		}
 		return items, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	case <-timeout:
 		// It's possible something was added to the channel after something
 		// was received on the timeout channel, in which case that must
@@ -202,6 +253,8 @@ func (b *basicBatcher) Get() ([]interface{}, error) {
 						return nil, ErrDisposed
 					}
 					return items, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
 				default:
 				}
 			}
@@ -222,6 +275,27 @@ func (b *basicBatcher) Flush() error {
 	return nil
 }
 
+// Close stops the batcher accepting items and completes the batch currently
+// being built. Any calls to Put or Flush will return ErrDisposed, calls to
+// Get will return the remaining batches and then ErrDisposed. Close blocks
+// until there is room for the last batch.
+func (b *basicBatcher) Close() error {
+	b.lock.Lock()
+	if b.disposed {
+		b.lock.Unlock()
+		return ErrDisposed
+	}
+
+	b.disposed = true
+	if len(b.items) > 0 {
+		b.flush()
+	}
+	b.items = nil
+	close(b.batchChan)
+	b.lock.Unlock()
+	return nil
+}
+
 // Dispose will dispose of the batcher. Any calls to Put or Flush
 // will return ErrDisposed, calls to Get will return an error iff
 // there are no more ready batches. Any items not flushed and retrieved
@@ -264,9 +338,20 @@ func (b *basicBatcher) IsDisposed() bool {
 // flush adds the batch currently being built to the queue of completed batches.
 // flush is not threadsafe, so should be synchronized externally.
 func (b *basicBatcher) flush() {
-	b.batchChan <- b.items
+	b.flushContext(context.Background())
+}
+
+// flushContext is flush, returning the context's error and leaving the batch
+// being built alone if the context is done before there is room in the queue.
+func (b *basicBatcher) flushContext(ctx context.Context) error {
+	select {
+	case b.batchChan <- b.items:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	b.items = make([]interface{}, 0, b.maxItems)
 	b.availableBytes = 0
+	return nil
 }
 
 func (b *basicBatcher) ready() bool {
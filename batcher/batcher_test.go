@@ -17,6 +17,7 @@ limitations under the License.
 package batcher
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -167,6 +168,78 @@ func TestDispose(t *testing.T) {
 
 }
 
+func TestPutContext(t *testing.T) {
+	assert := assert.New(t)
+	b, err := New(0, 2, 0, 1, nil)
+	assert.Nil(err)
+	assert.Nil(b.Put("a"))
+	assert.Nil(b.Put("b"))
+	assert.Nil(b.Put("c"))
+
+	// the queue is full so completing another batch has to wait
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, b.PutContext(ctx, "d"))
+
+	batch, err := b.Get()
+	assert.Equal([]interface{}{"a", "b"}, batch)
+	assert.Nil(err)
+
+	// the item that timed out was not added
+	assert.Nil(b.PutContext(context.Background(), "e"))
+	batch, err = b.Get()
+	assert.Equal([]interface{}{"c", "e"}, batch)
+	assert.Nil(err)
+}
+
+func TestGetContext(t *testing.T) {
+	assert := assert.New(t)
+	b, err := New(0, 2, 0, 1, nil)
+	assert.Nil(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	batch, err := b.GetContext(ctx)
+	assert.Nil(batch)
+	assert.Equal(context.Canceled, err)
+
+	b.Put("a")
+	b.Put("b")
+	batch, err = b.GetContext(context.Background())
+	assert.Equal([]interface{}{"a", "b"}, batch)
+	assert.Nil(err)
+}
+
+func TestClose(t *testing.T) {
+	assert := assert.New(t)
+	b, err := New(0, 2, 0, 2, nil)
+	assert.Nil(err)
+	b.Put("a")
+	b.Put("b")
+	b.Put("c")
+
+	assert.Nil(b.Close())
+	assert.True(b.IsDisposed())
+	assert.Equal(ErrDisposed, b.Put("d"))
+	assert.Equal(ErrDisposed, b.Flush())
+	assert.Equal(ErrDisposed, b.Close())
+
+	// the remaining batches can still be retrieved
+	batch, err := b.Get()
+	assert.Equal([]interface{}{"a", "b"}, batch)
+	assert.Nil(err)
+	batch, err = b.Get()
+	assert.Equal([]interface{}{"c"}, batch)
+	assert.Nil(err)
+	_, err = b.Get()
+	assert.Equal(ErrDisposed, err)
+
+	b.Dispose()
+}
+
 func TestIsDisposed(t *testing.T) {
 	assert := assert.New(t)
 	b, err := New(0, 10, 10, 10, func(str interface{}) uint {
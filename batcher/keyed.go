@@ -0,0 +1,361 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// KeyFunc returns the key of the batch an item added to a KeyedBatcher
+// belongs in.
+type KeyFunc func(interface{}) string
+
+// KeyedBatcher provides an API for accumulating items into separate batches
+// by key for processing.
+type KeyedBatcher interface {
+	// Put adds items to the batch for their key.
+	Put(interface{}) error
+
+	// PutContext adds items to the batch for their key, returning the
+	// context's error if it is done before there is room for them.
+	PutContext(context.Context, interface{}) error
+
+	// Get retrieves a batch and its key from the batcher. This call will
+	// block until one of the conditions for a "complete" batch is reached
+	// for any key.
+	Get() (string, []interface{}, error)
+
+	// GetContext retrieves a batch and its key from the batcher like Get,
+	// returning the context's error if it is done before a batch is
+	// complete.
+	GetContext(context.Context) (string, []interface{}, error)
+
+	// Flush forcibly completes the batches currently being built
+	Flush() error
+
+	// Close stops the batcher accepting items and completes the batches
+	// currently being built. Any calls to Put or Flush will return
+	// ErrDisposed, calls to Get will return the remaining batches and then
+	// ErrDisposed.
+	Close() error
+
+	// Dispose will dispose of the batcher. Any calls to Put, Flush or Get
+	// will return ErrDisposed and batches not yet retrieved are dropped.
+	Dispose()
+
+	// IsDisposed will determine if the batcher is disposed
+	IsDisposed() bool
+}
+
+// A batch of items with the same key
+type keyedBatch struct {
+	key     string
+	items   []interface{}
+	bytes   uint
+	started time.Time     // When the first item was added
+	element *list.Element // Node in the list of batches being built
+}
+
+type keyedBatcher struct {
+	lock           sync.Mutex
+	keyOf          KeyFunc
+	maxTime        time.Duration
+	maxItems       uint
+	maxBytes       uint
+	maxTotalBytes  uint
+	calculateBytes CalculateBytes
+	disposed       bool
+	building       map[string]*keyedBatch   // Batches being built by key
+	buildingOrder  *list.List               // Batches being built, oldest first
+	ready          map[string][]*keyedBatch // Completed batches by key, oldest first
+	readyKeys      *list.List               // Keys with completed batches, in the order they take turns
+	totalBytes     uint                     // Bytes in all batches not yet retrieved
+	changed        chan struct{}            // Closed when batches are completed or retrieved
+}
+
+// NewKeyed creates a new KeyedBatcher that keeps a batch for every key
+// returned by the provided KeyFunc.
+// The batch for each key is complete in the same ways as for New:
+//   - Maximum number of bytes per batch
+//   - Maximum number of items per batch
+//   - Maximum amount of time since the first item was added to a batch
+//
+// Values of zero for one of these fields indicate they should not be
+// taken into account when evaluating the readiness of a batch.
+// maxTotalBytes bounds the bytes in all batches not yet retrieved, with zero
+// meaning no bound.  Once it is reached Put blocks until Get makes room,
+// completing the oldest batch being built so there is a batch to Get.
+// Get takes completed batches from each key in turn, so a key completing
+// many batches cannot hold back the others.
+// Items put by any given thread with the same key are returned in the order
+// they were put.
+func NewKeyed(keyOf KeyFunc, maxTime time.Duration, maxItems, maxBytes, maxTotalBytes uint, calculate CalculateBytes) (KeyedBatcher, error) {
+	if keyOf == nil {
+		return nil, errors.New("batcher: must provide KeyFunc function")
+	}
+	if (maxBytes > 0 || maxTotalBytes > 0) && calculate == nil {
+		return nil, errors.New("batcher: must provide CalculateBytes function")
+	}
+
+	return &keyedBatcher{
+		keyOf:          keyOf,
+		maxTime:        maxTime,
+		maxItems:       maxItems,
+		maxBytes:       maxBytes,
+		maxTotalBytes:  maxTotalBytes,
+		calculateBytes: calculate,
+		building:       map[string]*keyedBatch{},
+		buildingOrder:  list.New(),
+		ready:          map[string][]*keyedBatch{},
+		readyKeys:      list.New(),
+		changed:        make(chan struct{}),
+	}, nil
+}
+
+// Put adds items to the batch for their key.
+func (b *keyedBatcher) Put(item interface{}) error {
+	return b.PutContext(context.Background(), item)
+}
+
+// PutContext adds items to the batch for their key, returning the context's
+// error if it is done before there is room for them.
+func (b *keyedBatcher) PutContext(ctx context.Context, item interface{}) error {
+	key := b.keyOf(item)
+	var bytes uint
+	if b.calculateBytes != nil {
+		bytes = b.calculateBytes(item)
+	}
+
+	b.lock.Lock()
+	for {
+		if b.disposed {
+			b.lock.Unlock()
+			return ErrDisposed
+		}
+		// An item larger than the bound is let in once everything else
+		// has been retrieved
+		if b.maxTotalBytes == 0 || b.totalBytes == 0 || b.totalBytes+bytes <= b.maxTotalBytes {
+			break
+		}
+
+		if front := b.buildingOrder.Front(); front != nil {
+			b.complete(front.Value.(*keyedBatch))
+		}
+		if err := b.wait(ctx, nil); err != nil {
+			b.lock.Unlock()
+			return err
+		}
+	}
+
+	batch, ok := b.building[key]
+	if !ok {
+		batch = &keyedBatch{key: key, started: time.Now()}
+		batch.element = b.buildingOrder.PushBack(batch)
+		b.building[key] = batch
+		// Get may be waiting without a timeout for a batch to time
+		b.notify()
+	}
+	batch.items = append(batch.items, item)
+	batch.bytes += bytes
+	b.totalBytes += bytes
+	if b.full(batch) {
+		b.complete(batch)
+	}
+
+	b.lock.Unlock()
+	return nil
+}
+
+// Get retrieves a batch and its key from the batcher. This call will block
+// until one of the conditions for a "complete" batch is reached for any key.
+func (b *keyedBatcher) Get() (string, []interface{}, error) {
+	return b.GetContext(context.Background())
+}
+
+// GetContext retrieves a batch and its key from the batcher like Get,
+// returning the context's error if it is done before a batch is complete.
+func (b *keyedBatcher) GetContext(ctx context.Context) (string, []interface{}, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for {
+		if front := b.readyKeys.Front(); front != nil {
+			batch := b.take(front)
+			return batch.key, batch.items, nil
+		}
+		if b.disposed {
+			return "", nil, ErrDisposed
+		}
+
+		// Wait until the oldest batch being built is complete for time
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if front := b.buildingOrder.Front(); front != nil && b.maxTime > 0 {
+			oldest := front.Value.(*keyedBatch)
+			wait := time.Until(oldest.started.Add(b.maxTime))
+			if wait <= 0 {
+				b.complete(oldest)
+				continue
+			}
+
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+
+		err := b.wait(ctx, timeout)
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+// Flush forcibly completes the batches currently being built
+func (b *keyedBatcher) Flush() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
+	}
+	b.completeAll()
+	return nil
+}
+
+// Close stops the batcher accepting items and completes the batches currently
+// being built. Any calls to Put or Flush will return ErrDisposed, calls to
+// Get will return the remaining batches and then ErrDisposed.
+func (b *keyedBatcher) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
+	}
+	b.disposed = true
+	b.completeAll()
+	b.notify()
+	return nil
+}
+
+// Dispose will dispose of the batcher. Any calls to Put, Flush or Get will
+// return ErrDisposed and batches not yet retrieved are dropped.
+func (b *keyedBatcher) Dispose() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.disposed = true
+	b.building = map[string]*keyedBatch{}
+	b.buildingOrder.Init()
+	b.ready = map[string][]*keyedBatch{}
+	b.readyKeys.Init()
+	b.totalBytes = 0
+	b.notify()
+}
+
+// IsDisposed will determine if the batcher is disposed
+func (b *keyedBatcher) IsDisposed() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.disposed
+}
+
+// full determines whether the batch is complete by size.
+func (b *keyedBatcher) full(batch *keyedBatch) bool {
+	if b.maxItems != 0 && uint(len(batch.items)) >= b.maxItems {
+		return true
+	}
+	if b.maxBytes != 0 && batch.bytes >= b.maxBytes {
+		return true
+	}
+	return false
+}
+
+// complete moves a batch being built to the completed batches for its key,
+// giving the key a turn if it has no other completed batches.
+// complete is not threadsafe, so should be synchronized externally.
+func (b *keyedBatcher) complete(batch *keyedBatch) {
+	b.buildingOrder.Remove(batch.element)
+	batch.element = nil
+	delete(b.building, batch.key)
+
+	if len(b.ready[batch.key]) == 0 {
+		b.readyKeys.PushBack(batch.key)
+	}
+	b.ready[batch.key] = append(b.ready[batch.key], batch)
+	b.notify()
+}
+
+// completeAll completes every batch being built, oldest first.
+// completeAll is not threadsafe, so should be synchronized externally.
+func (b *keyedBatcher) completeAll() {
+	for b.buildingOrder.Len() > 0 {
+		b.complete(b.buildingOrder.Front().Value.(*keyedBatch))
+	}
+}
+
+// take removes the oldest completed batch of the key whose turn it is, in the
+// provided node of the list of keys, and moves the key to the back of the
+// list if it has more.
+// take is not threadsafe, so should be synchronized externally.
+func (b *keyedBatcher) take(element *list.Element) *keyedBatch {
+	key := b.readyKeys.Remove(element).(string)
+	batches := b.ready[key]
+	batch := batches[0]
+	batches[0] = nil
+	if len(batches) > 1 {
+		b.ready[key] = batches[1:]
+		b.readyKeys.PushBack(key)
+	} else {
+		delete(b.ready, key)
+	}
+
+	b.totalBytes -= batch.bytes
+	b.notify()
+	return batch
+}
+
+// notify wakes everything waiting for the batches to change.
+// notify is not threadsafe, so should be synchronized externally.
+func (b *keyedBatcher) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// wait releases the lock until the batches change, the timeout passes or the
+// context is done, in which case it returns the context's error.
+// wait should be called with the lock held and returns with it held.
+func (b *keyedBatcher) wait(ctx context.Context, timeout <-chan time.Time) error {
+	changed := b.changed
+	b.lock.Unlock()
+	defer b.lock.Lock()
+
+	select {
+	case <-changed:
+	case <-timeout:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// keyOfFirst keys string items by their first letter.
+func keyOfFirst(item interface{}) string {
+	return item.(string)[:1]
+}
+
+// getKeyed gets a batch, failing the test if there is none.
+func getKeyed(t *testing.T, b KeyedBatcher) (string, []interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	key, batch, err := b.GetContext(ctx)
+	assert.Nil(t, err)
+	return key, batch
+}
+
+func TestKeyedArguments(t *testing.T) {
+	_, err := NewKeyed(nil, 0, 10, 0, 0, nil)
+	assert.Error(t, err)
+	_, err = NewKeyed(keyOfFirst, 0, 0, 100, 0, nil)
+	assert.Error(t, err)
+	_, err = NewKeyed(keyOfFirst, 0, 0, 0, 100, nil)
+	assert.Error(t, err)
+}
+
+func TestKeyedMaxItems(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 2, 0, 0, nil)
+	assert.Nil(err)
+
+	for _, item := range []string{"a1", "b1", "a2", "b2", "a3"} {
+		assert.Nil(b.Put(item))
+	}
+
+	key, batch := getKeyed(t, b)
+	assert.Equal("a", key)
+	assert.Equal([]interface{}{"a1", "a2"}, batch)
+	key, batch = getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"b1", "b2"}, batch)
+
+	// a3 is not complete yet
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = b.GetContext(ctx)
+	assert.Equal(context.DeadlineExceeded, err)
+}
+
+func TestKeyedMaxBytes(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 0, 5, 0, func(item interface{}) uint {
+		return uint(len(item.(string)))
+	})
+	assert.Nil(err)
+
+	for _, item := range []string{"a1", "bbb1", "a2", "bb2", "a3"} {
+		assert.Nil(b.Put(item))
+	}
+
+	key, batch := getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"bbb1", "bb2"}, batch)
+	key, batch = getKeyed(t, b)
+	assert.Equal("a", key)
+	assert.Equal([]interface{}{"a1", "a2", "a3"}, batch)
+}
+
+func TestKeyedMaxTime(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 50*time.Millisecond, 100, 0, 0, nil)
+	assert.Nil(err)
+
+	start := time.Now()
+	assert.Nil(b.Put("a1"))
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(b.Put("b1"))
+	assert.Nil(b.Put("a2"))
+
+	// each key's time starts with its first item
+	key, batch := getKeyed(t, b)
+	assert.Equal("a", key)
+	assert.Equal([]interface{}{"a1", "a2"}, batch)
+	assert.True(time.Since(start) >= 50*time.Millisecond)
+
+	key, batch = getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"b1"}, batch)
+	assert.True(time.Since(start) >= 70*time.Millisecond)
+}
+
+func TestKeyedMaxTimeWaitingGet(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 50*time.Millisecond, 100, 0, 0, nil)
+	assert.Nil(err)
+
+	// Get is waiting before there is any batch to time
+	result := make(chan []interface{}, 1)
+	go func() {
+		_, batch := getKeyed(t, b)
+		result <- batch
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(b.Put("a1"))
+
+	select {
+	case batch := <-result:
+		assert.Equal([]interface{}{"a1"}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not completed for time")
+	}
+}
+
+func TestKeyedFairness(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 1, 0, 0, nil)
+	assert.Nil(err)
+
+	for _, item := range []string{"a1", "a2", "a3", "a4", "b1", "c1", "b2"} {
+		assert.Nil(b.Put(item))
+	}
+
+	// keys take turns rather than a draining first
+	var items []interface{}
+	for i := 0; i < 7; i++ {
+		_, batch := getKeyed(t, b)
+		items = append(items, batch...)
+	}
+	assert.Equal([]interface{}{"a1", "b1", "c1", "a2", "b2", "a3", "a4"}, items)
+}
+
+func TestKeyedMaxTotalBytes(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 0, 0, 4, func(item interface{}) uint {
+		return uint(len(item.(string)))
+	})
+	assert.Nil(err)
+
+	assert.Nil(b.Put("a1"))
+	assert.Nil(b.Put("b1"))
+
+	// there is no room, so the oldest batch is completed for Get
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, b.PutContext(ctx, "b2"))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.Put("b2")
+	}()
+	select {
+	case <-done:
+		t.Fatal("put did not block")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	key, batch := getKeyed(t, b)
+	assert.Equal("a", key)
+	assert.Equal([]interface{}{"a1"}, batch)
+	assert.Nil(<-done)
+
+	// the blocked put completed b1 for Get too
+	assert.Nil(b.Flush())
+	key, batch = getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"b1"}, batch)
+	key, batch = getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"b2"}, batch)
+
+	// an item over the bound is let in once the batcher is empty
+	assert.Nil(b.Put("cccccc"))
+}
+
+func TestKeyedFlush(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 10, 0, 0, nil)
+	assert.Nil(err)
+
+	assert.Nil(b.Put("a1"))
+	assert.Nil(b.Put("b1"))
+	assert.Nil(b.Flush())
+
+	key, batch := getKeyed(t, b)
+	assert.Equal("a", key)
+	assert.Equal([]interface{}{"a1"}, batch)
+	key, batch = getKeyed(t, b)
+	assert.Equal("b", key)
+	assert.Equal([]interface{}{"b1"}, batch)
+}
+
+func TestKeyedClose(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 2, 0, 0, nil)
+	assert.Nil(err)
+
+	for _, item := range []string{"a1", "a2", "b1"} {
+		assert.Nil(b.Put(item))
+	}
+	assert.Nil(b.Close())
+	assert.True(b.IsDisposed())
+	assert.Equal(ErrDisposed, b.Put("a3"))
+	assert.Equal(ErrDisposed, b.Flush())
+	assert.Equal(ErrDisposed, b.Close())
+
+	// remaining batches are returned before the error
+	_, batch := getKeyed(t, b)
+	assert.Equal([]interface{}{"a1", "a2"}, batch)
+	_, batch = getKeyed(t, b)
+	assert.Equal([]interface{}{"b1"}, batch)
+	_, _, err = b.Get()
+	assert.Equal(ErrDisposed, err)
+}
+
+func TestKeyedDispose(t *testing.T) {
+	assert := assert.New(t)
+	b, err := NewKeyed(keyOfFirst, 0, 1, 0, 0, nil)
+	assert.Nil(err)
+
+	assert.Nil(b.Put("a1"))
+	done := make(chan error, 1)
+	go func() {
+		b.Get()
+		_, _, err := b.Get()
+		done <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	assert.False(b.IsDisposed())
+	b.Dispose()
+	assert.True(b.IsDisposed())
+	assert.Equal(ErrDisposed, <-done)
+	assert.Equal(ErrDisposed, b.Put("a2"))
+	assert.Equal(ErrDisposed, b.Flush())
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Handler processes a batch of items.
+type Handler func(batch []interface{})
+
+// PushBatcher accumulates items into batches like a Batcher, but passes each
+// batch to a Handler instead of waiting for a consumer to Get it.
+type PushBatcher interface {
+	// Put adds items to the batcher.
+	Put(interface{}) error
+
+	// PutContext adds items to the batcher, returning the context's error
+	// if it is done before there is room for them.
+	PutContext(context.Context, interface{}) error
+
+	// Flush forcibly completes the batch currently being built
+	Flush() error
+
+	// Close stops the batcher accepting items, completes the batch
+	// currently being built and waits for every batch to be handled. Any
+	// calls to Put or Flush will return ErrDisposed.
+	Close() error
+}
+
+type pushBatcher struct {
+	batcher *basicBatcher
+	handler Handler
+	batches chan []interface{}
+	wg      sync.WaitGroup
+}
+
+// NewPush creates a new PushBatcher that completes batches like a Batcher
+// created by New with the same arguments, and passes them to the handler in
+// up to the given number of goroutines at once, at least one.  With a single
+// goroutine batches are handled in the order they are completed.  When all
+// goroutines are busy completed batches queue up to queueLen, after which Put
+// blocks.
+func NewPush(handler Handler, concurrency uint, maxTime time.Duration, maxItems, maxBytes, queueLen uint, calculate CalculateBytes) (PushBatcher, error) {
+	batcher, err := New(maxTime, maxItems, maxBytes, queueLen, calculate)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency = max(concurrency, 1)
+	b := &pushBatcher{
+		batcher: batcher.(*basicBatcher),
+		handler: handler,
+		batches: make(chan []interface{}),
+	}
+
+	// A single goroutine gets batches so they are completed on time no
+	// matter how many handle them
+	b.wg.Add(1 + int(concurrency))
+	go b.dispatch()
+	for i := uint(0); i < concurrency; i++ {
+		go b.handle()
+	}
+
+	return b, nil
+}
+
+// dispatch passes completed batches to the handling goroutines until the
+// batcher is closed.
+func (b *pushBatcher) dispatch() {
+	defer b.wg.Done()
+	defer close(b.batches)
+
+	for {
+		batch, err := b.batcher.Get()
+		if err != nil {
+			return
+		}
+		// Batches completed for time alone may be empty
+		if len(batch) > 0 {
+			b.batches <- batch
+		}
+	}
+}
+
+// handle calls the handler with batches until there are no more.
+func (b *pushBatcher) handle() {
+	defer b.wg.Done()
+
+	for batch := range b.batches {
+		b.handler(batch)
+	}
+}
+
+// Put adds items to the batcher.
+func (b *pushBatcher) Put(item interface{}) error {
+	return b.batcher.Put(item)
+}
+
+// PutContext adds items to the batcher, returning the context's error if it
+// is done before there is room for them.
+func (b *pushBatcher) PutContext(ctx context.Context, item interface{}) error {
+	return b.batcher.PutContext(ctx, item)
+}
+
+// Flush forcibly completes the batch currently being built
+func (b *pushBatcher) Flush() error {
+	return b.batcher.Flush()
+}
+
+// Close stops the batcher accepting items, completes the batch currently
+// being built and waits for every batch to be handled. Any calls to Put or
+// Flush will return ErrDisposed.
+func (b *pushBatcher) Close() error {
+	if err := b.batcher.Close(); err != nil {
+		return err
+	}
+
+	b.wg.Wait()
+	return nil
+}
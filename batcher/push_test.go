@@ -0,0 +1,153 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchLog records the batches passed to its handler.
+type batchLog struct {
+	sync.Mutex
+	batches [][]interface{}
+}
+
+func (l *batchLog) handle(batch []interface{}) {
+	l.Lock()
+	defer l.Unlock()
+	l.batches = append(l.batches, batch)
+}
+
+func (l *batchLog) get() [][]interface{} {
+	l.Lock()
+	defer l.Unlock()
+	return append([][]interface{}(nil), l.batches...)
+}
+
+func TestPushNoCalculateBytes(t *testing.T) {
+	_, err := NewPush(func([]interface{}) {}, 1, 0, 0, 100, 5, nil)
+	assert.Error(t, err)
+}
+
+func TestPush(t *testing.T) {
+	assert := assert.New(t)
+	log := &batchLog{}
+	b, err := NewPush(log.handle, 1, 0, 2, 0, 10, nil)
+	assert.Nil(err)
+
+	for _, item := range []string{"a", "b", "c", "d", "e"} {
+		assert.Nil(b.Put(item))
+	}
+	assert.Eventually(func() bool {
+		return len(log.get()) == 2
+	}, time.Second, time.Millisecond)
+
+	// closing handles what remains
+	assert.Nil(b.Close())
+	assert.Equal([][]interface{}{{"a", "b"}, {"c", "d"}, {"e"}}, log.get())
+
+	assert.Equal(ErrDisposed, b.Put("f"))
+	assert.Equal(ErrDisposed, b.Flush())
+	assert.Equal(ErrDisposed, b.Close())
+}
+
+func TestPushMaxTime(t *testing.T) {
+	assert := assert.New(t)
+	log := &batchLog{}
+	b, err := NewPush(log.handle, 4, 10*time.Millisecond, 100, 0, 10, nil)
+	assert.Nil(err)
+	defer b.Close()
+
+	b.Put("a")
+	assert.Eventually(func() bool {
+		return len(log.get()) == 1
+	}, time.Second, time.Millisecond)
+
+	// batches completed for time alone are not handled when empty
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal([][]interface{}{{"a"}}, log.get())
+}
+
+func TestPushFlush(t *testing.T) {
+	assert := assert.New(t)
+	log := &batchLog{}
+	b, err := NewPush(log.handle, 1, 0, 100, 0, 10, nil)
+	assert.Nil(err)
+	defer b.Close()
+
+	b.Put("a")
+	assert.Nil(b.Flush())
+	assert.Eventually(func() bool {
+		return len(log.get()) == 1
+	}, time.Second, time.Millisecond)
+}
+
+func TestPushConcurrency(t *testing.T) {
+	assert := assert.New(t)
+	var running, most int32
+	release := make(chan struct{})
+	b, err := NewPush(func([]interface{}) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+	}, 3, 0, 1, 0, 10, nil)
+	assert.Nil(err)
+
+	for i := 0; i < 6; i++ {
+		assert.Nil(b.Put(i))
+	}
+	assert.Eventually(func() bool {
+		return atomic.LoadInt32(&running) == 3
+	}, time.Second, time.Millisecond)
+
+	close(release)
+	assert.Nil(b.Close())
+	assert.Equal(int32(3), atomic.LoadInt32(&most))
+}
+
+func TestPushPutContext(t *testing.T) {
+	assert := assert.New(t)
+	release := make(chan struct{})
+	b, err := NewPush(func([]interface{}) {
+		<-release
+	}, 1, 0, 1, 0, 1, nil)
+	assert.Nil(err)
+
+	// one batch is being handled, one waits to be and one is queued
+	for i := 0; i < 3; i++ {
+		assert.Nil(b.Put(i))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(context.DeadlineExceeded, b.PutContext(ctx, 3))
+
+	close(release)
+	assert.Nil(b.Close())
+}
@@ -73,6 +73,10 @@ func (c *cacher) loadNode(t *Tr, key ID) (*Node, error) {
 		return nil, err
 	}
 
+	if len(items) == 0 || items[0] == nil {
+		return nil, ErrNodeNotFound
+	}
+
 	n, err := nodeFromBytes(t, items[0].Payload)
 	if err != nil {
 		return nil, err
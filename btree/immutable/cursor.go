@@ -0,0 +1,300 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package btree
+
+/*
+This file contains the logic for ordered iteration through the tree.  A
+cursor keeps a stack of the nodes visited from the root to the current
+leaf so it only ever holds a single path in memory.  Nodes are fetched
+through the tree's normal lookup so persisted nodes are loaded from the
+Persister as the cursor moves through them.
+*/
+
+type cursorPosition uint8
+
+const (
+	beforeFirst cursorPosition = iota
+	positioned
+	afterLast
+)
+
+// cursorFrame is a single node in the cursor's path along with the index
+// of the child (internal node) or item (leaf node) currently visited.
+type cursorFrame struct {
+	n *Node
+	i int
+}
+
+type cursor struct {
+	tree     *Tr
+	stack    []cursorFrame
+	position cursorPosition
+	err      error
+}
+
+func (c *cursor) load(id ID) (*Node, error) {
+	return c.tree.contextOrCachedNode(id, c.tree.mutable)
+}
+
+func (c *cursor) top() *cursorFrame {
+	return &c.stack[len(c.stack)-1]
+}
+
+// fail records the error and invalidates the cursor.
+func (c *cursor) fail(err error) bool {
+	c.err = err
+	c.stack = c.stack[:0]
+	c.position = afterLast
+	return false
+}
+
+// descend walks from the provided node ID down to a leaf, always taking
+// the first child if first is true, otherwise the last.
+func (c *cursor) descend(id ID, first bool) error {
+	for {
+		n, err := c.load(id)
+		if err != nil {
+			return err
+		}
+
+		i := 0
+		if !first {
+			if n.IsLeaf {
+				i = n.lenValues() - 1
+			} else {
+				i = n.lenKeys() - 1
+			}
+		}
+
+		c.stack = append(c.stack, cursorFrame{n: n, i: i})
+		if n.IsLeaf {
+			return nil
+		}
+
+		id = n.keyAt(i).ID()
+	}
+}
+
+// valid returns a bool indicating if the cursor currently points at an
+// item in a leaf.
+func (c *cursor) valid() bool {
+	if len(c.stack) == 0 {
+		return false
+	}
+
+	f := c.top()
+	return f.n.IsLeaf && f.i >= 0 && f.i < f.n.lenValues()
+}
+
+// reset clears the stack and positions the cursor at the boundary.
+func (c *cursor) reset(position cursorPosition) bool {
+	c.stack = c.stack[:0]
+	c.position = position
+	return false
+}
+
+func (c *cursor) First() bool {
+	c.err = nil
+	c.stack = c.stack[:0]
+	if len(c.tree.Root) == 0 {
+		return c.reset(afterLast)
+	}
+
+	if err := c.descend(c.tree.Root, true); err != nil {
+		return c.fail(err)
+	}
+
+	c.position = positioned
+	if c.valid() {
+		return true
+	}
+
+	return c.forward()
+}
+
+func (c *cursor) Last() bool {
+	c.err = nil
+	c.stack = c.stack[:0]
+	if len(c.tree.Root) == 0 {
+		return c.reset(beforeFirst)
+	}
+
+	if err := c.descend(c.tree.Root, false); err != nil {
+		return c.fail(err)
+	}
+
+	c.position = positioned
+	if c.valid() {
+		return true
+	}
+
+	return c.backward()
+}
+
+func (c *cursor) Seek(value interface{}) bool {
+	c.err = nil
+	c.stack = c.stack[:0]
+	if len(c.tree.Root) == 0 {
+		return c.reset(afterLast)
+	}
+
+	id := c.tree.Root
+	for {
+		n, err := c.load(id)
+		if err != nil {
+			return c.fail(err)
+		}
+
+		if n.IsLeaf {
+			// position just before the first value >= the seek value
+			// and step forward as this might require moving to the
+			// next leaf.
+			i := n.search(c.tree.config.Comparator, value)
+			c.stack = append(c.stack, cursorFrame{n: n, i: i - 1})
+			c.position = positioned
+			return c.forward()
+		}
+
+		key, i := n.searchKey(c.tree.config.Comparator, value)
+		c.stack = append(c.stack, cursorFrame{n: n, i: i})
+		id = key.ID()
+	}
+}
+
+func (c *cursor) Next() bool {
+	switch c.position {
+	case beforeFirst:
+		return c.First()
+	case afterLast:
+		return false
+	}
+
+	return c.forward()
+}
+
+func (c *cursor) Prev() bool {
+	switch c.position {
+	case afterLast:
+		return c.Last()
+	case beforeFirst:
+		return false
+	}
+
+	return c.backward()
+}
+
+// forward moves the cursor to the next item in the tree, moving up
+// the path as far as required to find the next subtree.
+func (c *cursor) forward() bool {
+	for len(c.stack) > 0 {
+		f := c.top()
+		f.i++
+		if f.n.IsLeaf {
+			if f.i < f.n.lenValues() {
+				return true
+			}
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+
+		if f.i >= f.n.lenKeys() {
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+
+		if err := c.descend(f.n.keyAt(f.i).ID(), true); err != nil {
+			return c.fail(err)
+		}
+		if c.valid() {
+			return true
+		}
+	}
+
+	return c.reset(afterLast)
+}
+
+// backward moves the cursor to the previous item in the tree, moving up
+// the path as far as required to find the previous subtree.
+func (c *cursor) backward() bool {
+	for len(c.stack) > 0 {
+		f := c.top()
+		f.i--
+		if f.n.IsLeaf {
+			if f.i >= 0 && f.i < f.n.lenValues() {
+				return true
+			}
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+
+		if f.i < 0 {
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+
+		if err := c.descend(f.n.keyAt(f.i).ID(), false); err != nil {
+			return c.fail(err)
+		}
+		if c.valid() {
+			return true
+		}
+	}
+
+	return c.reset(beforeFirst)
+}
+
+func (c *cursor) Item() *Item {
+	if c.position != positioned || !c.valid() {
+		return nil
+	}
+
+	f := c.top()
+	return f.n.keyAt(f.i).ToItem()
+}
+
+func (c *cursor) Err() error {
+	return c.err
+}
+
+// Cursor returns a new cursor positioned before the first item in
+// this tree.
+func (t *Tr) Cursor() Cursor {
+	return &cursor{
+		tree:  t,
+		stack: make([]cursorFrame, 0, 8),
+	}
+}
+
+// ApplyRange calls the provided function with every item whose value
+// falls within [start, stop) in ascending order.  Iteration halts if the
+// function returns false.  An error is returned if a node could not be
+// loaded from persistence.
+func (t *Tr) ApplyRange(start, stop interface{}, fn func(item *Item) bool) error {
+	c := t.Cursor()
+	for ok := c.Seek(start); ok; ok = c.Next() {
+		item := c.Item()
+		if t.config.Comparator(item.Value, stop) >= 0 {
+			break
+		}
+
+		if !fn(item) {
+			break
+		}
+	}
+
+	return c.Err()
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package btree
+
+/*
+This file contains the logic for comparing two versions of a tree.
+Each tree is walked lazily as a sorted stream of heads, where a head is
+either an item or a subtree that has not yet been loaded along with the
+bounds of the values it can hold.  The two streams are merged and when
+both streams are positioned at a subtree with the same ID the subtree is
+skipped on both sides without being loaded, as nodes are never modified
+once committed.  Subtrees are only expanded when required to keep the
+merge in order, preferring the taller subtree so shared children line
+up with each other.
+*/
+
+import "bytes"
+
+// ChangeType describes how an item differs between two trees.
+type ChangeType uint8
+
+const (
+	// Added indicates the item only exists in the newer tree.
+	Added ChangeType = iota
+	// Removed indicates the item only exists in the older tree.
+	Removed
+	// Changed indicates the item exists in both trees with different
+	// payloads.
+	Changed
+)
+
+// Change describes a single difference between two trees.  Old is nil
+// for added items and New is nil for removed items.
+type Change struct {
+	Type ChangeType
+	Old  *Item
+	New  *Item
+}
+
+// diffHead is either an item or an unexpanded subtree.  A subtree holds
+// values in (lo, hi] where a nil bound is unbounded.
+type diffHead struct {
+	item   *Item
+	id     ID
+	height int
+	lo, hi interface{}
+}
+
+func (h *diffHead) isItem() bool {
+	return h.item != nil
+}
+
+type diffWalker struct {
+	tree  *Tr
+	stack []*diffHead
+}
+
+func (w *diffWalker) load(id ID) (*Node, error) {
+	return w.tree.contextOrCachedNode(id, w.tree.mutable)
+}
+
+// init pushes the root as the only head, computing the height of the
+// tree by descending the leftmost path.
+func (w *diffWalker) init() error {
+	if len(w.tree.Root) == 0 {
+		return nil
+	}
+
+	height := 0
+	id := w.tree.Root
+	for {
+		n, err := w.load(id)
+		if err != nil {
+			return err
+		}
+
+		if n.IsLeaf {
+			break
+		}
+
+		height++
+		id = n.keyAt(0).ID()
+	}
+
+	w.stack = append(w.stack, &diffHead{id: w.tree.Root, height: height})
+	return nil
+}
+
+func (w *diffWalker) peek() *diffHead {
+	if len(w.stack) == 0 {
+		return nil
+	}
+
+	return w.stack[len(w.stack)-1]
+}
+
+func (w *diffWalker) pop() {
+	w.stack[len(w.stack)-1] = nil
+	w.stack = w.stack[:len(w.stack)-1]
+}
+
+// expand replaces the subtree at the top of the stack with its children.
+func (w *diffWalker) expand() error {
+	h := w.peek()
+	w.pop()
+
+	n, err := w.load(h.id)
+	if err != nil {
+		return err
+	}
+
+	if n.IsLeaf {
+		for i := n.lenValues() - 1; i >= 0; i-- {
+			w.stack = append(w.stack, &diffHead{item: n.keyAt(i).ToItem()})
+		}
+		return nil
+	}
+
+	for i := n.lenKeys() - 1; i >= 0; i-- {
+		child := &diffHead{
+			id:     n.keyAt(i).ID(),
+			height: h.height - 1,
+			lo:     h.lo,
+			hi:     h.hi,
+		}
+		if i > 0 {
+			child.lo = n.valueAt(i - 1)
+		}
+		if i < n.lenValues() {
+			child.hi = n.valueAt(i)
+		}
+		w.stack = append(w.stack, child)
+	}
+
+	return nil
+}
+
+// before returns a bool indicating if every value held by a is known
+// to be less than every value held by b.
+func before(comparator Comparator, a, b *diffHead) bool {
+	var hi interface{}
+	if a.isItem() {
+		hi = a.item.Value
+	} else {
+		hi = a.hi
+	}
+
+	if b.isItem() {
+		if a.isItem() {
+			return comparator(hi, b.item.Value) < 0
+		}
+		return hi != nil && comparator(hi, b.item.Value) < 0
+	}
+
+	return hi != nil && b.lo != nil && comparator(hi, b.lo) <= 0
+}
+
+// Diff calls the provided function with every difference between the
+// from and to trees in ascending order of value.  Subtrees shared by
+// both trees are skipped without being loaded, so the cost of a diff is
+// proportional to the size of the change rather than the size of the
+// trees.  Iteration halts if the function returns false.  An error is
+// returned if a node could not be loaded from persistence.
+func Diff(from, to ReadableTree, fn func(change *Change) bool) error {
+	left := &diffWalker{tree: from.(*Tr)}
+	right := &diffWalker{tree: to.(*Tr)}
+	comparator := right.tree.config.Comparator
+
+	if bytes.Equal(left.tree.Root, right.tree.Root) {
+		return nil
+	}
+
+	if err := left.init(); err != nil {
+		return err
+	}
+	if err := right.init(); err != nil {
+		return err
+	}
+
+	for {
+		l, r := left.peek(), right.peek()
+		var change *Change
+
+		switch {
+		case l == nil && r == nil:
+			return nil
+		case l == nil || (r != nil && before(comparator, r, l)):
+			if !r.isItem() {
+				if err := right.expand(); err != nil {
+					return err
+				}
+				continue
+			}
+			change = &Change{Type: Added, New: r.item}
+			right.pop()
+		case r == nil || before(comparator, l, r):
+			if !l.isItem() {
+				if err := left.expand(); err != nil {
+					return err
+				}
+				continue
+			}
+			change = &Change{Type: Removed, Old: l.item}
+			left.pop()
+		case l.isItem() && r.isItem():
+			left.pop()
+			right.pop()
+			if bytes.Equal(l.item.Payload, r.item.Payload) {
+				continue
+			}
+			change = &Change{Type: Changed, Old: l.item, New: r.item}
+		case !l.isItem() && !r.isItem() && bytes.Equal(l.id, r.id):
+			left.pop()
+			right.pop()
+			continue
+		default:
+			// the heads overlap so at least one has to be expanded,
+			// expanding the taller first gives children a chance to
+			// line up with the other side
+			var err error
+			switch {
+			case r.isItem() || (!l.isItem() && l.height >= r.height):
+				err = left.expand()
+			default:
+				err = right.expand()
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !fn(change) {
+			return nil
+		}
+	}
+}
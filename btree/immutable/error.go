@@ -24,3 +24,7 @@ var ErrNodeNotFound = errors.New(`node not found`)
 // ErrTreeNotFound is returned when a tree with the provided key could
 // not be loaded.
 var ErrTreeNotFound = errors.New(`tree not found`)
+
+// ErrDeleteNotSupported is returned when garbage collecting with a
+// persister that does not implement Deleter.
+var ErrDeleteNotSupported = errors.New(`persister does not support delete`)
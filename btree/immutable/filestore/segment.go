@@ -0,0 +1,253 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filestore
+
+/*
+This file contains the on-disk format of a segment.  A segment is a
+sequence of frames and every call to Save or Delete appends exactly one
+frame, which makes a batch all or nothing.
+
+frame:  [body length uint32][crc32 of body uint32][body]
+body:   entry*
+entry:  [op byte][key length uvarint][key][value length uvarint]
+        [crc32 of value uint32][value]
+
+Deletes carry no value.  The per-value checksum allows a single value
+to be verified when it is read back without reading the entire frame.
+*/
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	opPut byte = iota + 1
+	opDelete
+)
+
+const (
+	frameHeaderSize    = 8
+	logExtension       = `.log`
+	compactedExtension = `.clog`
+	tmpExtension       = `.tmp`
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// location describes where a value lives on disk.
+type location struct {
+	seq    uint64
+	offset int64
+	length int
+	crc    uint32
+}
+
+type segment struct {
+	seq       uint64
+	compacted bool
+	file      *os.File
+	size      int64
+}
+
+func segmentName(seq uint64, compacted bool) string {
+	if compacted {
+		return fmt.Sprintf(`%016x%s`, seq, compactedExtension)
+	}
+
+	return fmt.Sprintf(`%016x%s`, seq, logExtension)
+}
+
+// parseSegmentName returns the sequence number of the provided file
+// name and whether the segment is the output of a compaction.  The
+// final bool is false if the name does not belong to a segment.
+func parseSegmentName(name string) (uint64, bool, bool) {
+	ext := filepath.Ext(name)
+	if ext != logExtension && ext != compactedExtension {
+		return 0, false, false
+	}
+
+	seq, err := strconv.ParseUint(strings.TrimSuffix(name, ext), 16, 64)
+	if err != nil {
+		return 0, false, false
+	}
+
+	return seq, ext == compactedExtension, true
+}
+
+// frameBuilder accumulates entries into a single frame.
+type frameBuilder struct {
+	buf []byte
+	// offsets holds the offset of each put value relative to the
+	// start of the frame.
+	offsets []int64
+}
+
+func (fb *frameBuilder) reset() {
+	fb.buf = fb.buf[:0]
+	fb.offsets = fb.offsets[:0]
+	fb.buf = append(fb.buf, make([]byte, frameHeaderSize)...)
+}
+
+func (fb *frameBuilder) put(key, value []byte) uint32 {
+	crc := crc32.Checksum(value, crcTable)
+	fb.buf = append(fb.buf, opPut)
+	fb.buf = binary.AppendUvarint(fb.buf, uint64(len(key)))
+	fb.buf = append(fb.buf, key...)
+	fb.buf = binary.AppendUvarint(fb.buf, uint64(len(value)))
+	fb.buf = binary.LittleEndian.AppendUint32(fb.buf, crc)
+	fb.offsets = append(fb.offsets, int64(len(fb.buf)))
+	fb.buf = append(fb.buf, value...)
+	return crc
+}
+
+func (fb *frameBuilder) delete(key []byte) {
+	fb.buf = append(fb.buf, opDelete)
+	fb.buf = binary.AppendUvarint(fb.buf, uint64(len(key)))
+	fb.buf = append(fb.buf, key...)
+}
+
+func (fb *frameBuilder) len() int {
+	return len(fb.buf) - frameHeaderSize
+}
+
+// bytes finalizes the header and returns the encoded frame.
+func (fb *frameBuilder) bytes() []byte {
+	body := fb.buf[frameHeaderSize:]
+	binary.LittleEndian.PutUint32(fb.buf[0:], uint32(len(body)))
+	binary.LittleEndian.PutUint32(fb.buf[4:], crc32.Checksum(body, crcTable))
+	return fb.buf
+}
+
+func newFrameBuilder() *frameBuilder {
+	fb := &frameBuilder{}
+	fb.reset()
+	return fb
+}
+
+// entry is a single decoded entry of a frame.  Offset is the offset of
+// the value relative to the start of the body.
+type entry struct {
+	op     byte
+	key    []byte
+	offset int64
+	length int
+	crc    uint32
+}
+
+// decodeBody decodes all entries in the provided frame body.
+func decodeBody(body []byte) ([]entry, error) {
+	entries := make([]entry, 0, 8)
+	pos := 0
+	for pos < len(body) {
+		e := entry{op: body[pos]}
+		pos++
+
+		kl, n := binary.Uvarint(body[pos:])
+		if n <= 0 || uint64(len(body)-pos-n) < kl {
+			return nil, ErrCorrupt
+		}
+		pos += n
+		e.key = body[pos : pos+int(kl)]
+		pos += int(kl)
+
+		switch e.op {
+		case opDelete:
+		case opPut:
+			vl, n := binary.Uvarint(body[pos:])
+			if n <= 0 || len(body)-pos-n < 4 || uint64(len(body)-pos-n-4) < vl {
+				return nil, ErrCorrupt
+			}
+			pos += n
+			e.crc = binary.LittleEndian.Uint32(body[pos:])
+			pos += 4
+			e.offset = int64(pos)
+			e.length = int(vl)
+			pos += int(vl)
+		default:
+			return nil, ErrCorrupt
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// scan reads every frame in the provided file of the provided size
+// calling fn with the offset of each frame's body and its decoded
+// entries.  The returned offset is the end of the last valid frame.  If
+// a frame is torn or fails its checksum ErrCorrupt is returned along
+// with that offset.
+func scan(r io.Reader, size int64, fn func(bodyOffset int64, entries []entry)) (int64, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+	var (
+		offset int64
+		header [frameHeaderSize]byte
+		body   []byte
+	)
+
+	for {
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			if err == io.EOF {
+				return offset, nil
+			}
+			if err == io.ErrUnexpectedEOF {
+				return offset, ErrCorrupt
+			}
+			return offset, err
+		}
+
+		length := binary.LittleEndian.Uint32(header[0:])
+		crc := binary.LittleEndian.Uint32(header[4:])
+		// a corrupt length is treated as a torn frame rather than
+		// trusted with an allocation
+		if int64(length) > size-offset-frameHeaderSize {
+			return offset, ErrCorrupt
+		}
+		if cap(body) < int(length) {
+			body = make([]byte, length)
+		}
+		body = body[:length]
+
+		if _, err := io.ReadFull(br, body); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return offset, ErrCorrupt
+			}
+			return offset, err
+		}
+
+		if crc32.Checksum(body, crcTable) != crc {
+			return offset, ErrCorrupt
+		}
+
+		entries, err := decodeBody(body)
+		if err != nil {
+			return offset, err
+		}
+
+		fn(offset+frameHeaderSize, entries)
+		offset += frameHeaderSize + int64(length)
+	}
+}
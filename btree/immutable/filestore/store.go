@@ -0,0 +1,683 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package filestore provides a file-backed Persister for the immutable
+btree.  Payloads are appended to a log split into segments of bounded
+size and an in-memory index maps each key to the location of its latest
+value.  The index is rebuilt by replaying the segments when a store is
+opened.
+
+Every Save and Delete appends a single checksummed frame, so a batch is
+either entirely visible after a crash or not at all.  A frame torn by a
+crash at the tail of the active segment is truncated on open.
+
+Values that have been overwritten or deleted, along with any keys no
+longer referenced by a tree, are reclaimed by Compact, which rewrites
+the live values into a new segment.  Store implements btree.Deleter so
+nodes of old tree versions can be removed with btree.GC before
+compacting.
+
+Usage:
+
+store, err := filestore.Open(dir, filestore.DefaultConfig())
+tree := btree.New(btree.DefaultConfig(store, comparator))
+... operations
+
+err = store.Close()
+*/
+package filestore
+
+import (
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	btree "github.com/Workiva/go-datastructures/btree/immutable"
+)
+
+// ErrCorrupt is returned when a segment or value fails validation.
+var ErrCorrupt = errors.New(`filestore: corrupt segment`)
+
+// ErrClosed is returned when operating on a closed store.
+var ErrClosed = errors.New(`filestore: store is closed`)
+
+// compactionFrameSize is the size at which compaction flushes the frame
+// it is building.
+const compactionFrameSize = 1 << 20
+
+// Config defines the parameters of a store.
+type Config struct {
+	// MaxSegmentSize is the size in bytes at which the active segment
+	// is sealed and a new one is started.  A single batch larger than
+	// this value is still written to a single segment.
+	MaxSegmentSize int64
+	// NoSync disables syncing the active segment after every write.
+	// This is faster but batches written shortly before a crash may be
+	// lost.
+	NoSync bool
+}
+
+// DefaultConfig returns a configuration with durable writes and
+// 64MB segments.
+func DefaultConfig() Config {
+	return Config{
+		MaxSegmentSize: 64 << 20,
+	}
+}
+
+// Store is a file-backed implementation of btree.Persister.  Store
+// is threadsafe; loads proceed concurrently while writes and
+// compactions are serialized.  Loads also proceed while a compaction
+// rewrites the live values, and only wait for it to swap in the
+// compacted segment.
+type Store struct {
+	writeLock sync.Mutex // Serializes writes, compactions and Close
+	lock      sync.RWMutex
+	dir       string
+	config    Config
+	index     map[string]location
+	segments  map[uint64]*segment
+	active    *segment
+	frame     *frameBuilder
+	closed    bool
+}
+
+var _ btree.Deleter = (*Store)(nil)
+
+// Save appends the provided payloads to the log as a single batch.
+func (s *Store) Save(items ...*btree.Payload) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.frame.reset()
+	saved := make([]*btree.Payload, 0, len(items))
+	crcs := make([]uint32, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		saved = append(saved, item)
+		crcs = append(crcs, s.frame.put(item.Key, item.Payload))
+	}
+
+	if len(saved) == 0 {
+		return nil
+	}
+
+	offset, err := s.write(s.frame)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range saved {
+		s.index[string(item.Key)] = location{
+			seq:    s.active.seq,
+			offset: offset + s.frame.offsets[i],
+			length: len(item.Payload),
+			crc:    crcs[i],
+		}
+	}
+
+	return nil
+}
+
+// Load returns a payload for each of the provided keys in order.  A
+// nil payload is returned for any key that could not be found.
+func (s *Store) Load(keys ...[]byte) ([]*btree.Payload, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	items := make([]*btree.Payload, 0, len(keys))
+	for _, key := range keys {
+		loc, ok := s.index[string(key)]
+		if !ok {
+			items = append(items, nil)
+			continue
+		}
+
+		value, err := s.read(loc)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, &btree.Payload{
+			Key:     append([]byte(nil), key...),
+			Payload: value,
+		})
+	}
+
+	return items, nil
+}
+
+// Delete removes the provided keys from the store.  Keys that do not
+// exist are ignored.  Space is reclaimed on the next compaction.
+func (s *Store) Delete(keys ...[]byte) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	s.frame.reset()
+	for _, key := range keys {
+		if _, ok := s.index[string(key)]; ok {
+			s.frame.delete(key)
+		}
+	}
+
+	if s.frame.len() == 0 {
+		return nil
+	}
+
+	if _, err := s.write(s.frame); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		delete(s.index, string(key))
+	}
+
+	return nil
+}
+
+// Compact rewrites every live value into a single new segment and
+// removes the old segments.  If live is non-nil, only keys for which it
+// returns true are kept and all others are removed from the store,
+// which allows nodes no longer reachable from any tree to be dropped.
+// Writes are blocked while compacting, loads are only blocked while the
+// compacted segment replaces the old ones.
+func (s *Store) Compact(live func(key []byte) bool) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	// only writers change the index and segments, so holding the write
+	// lock they can be read without blocking loads
+	if s.closed {
+		return ErrClosed
+	}
+
+	if s.active.size > 0 {
+		s.lock.Lock()
+		err := s.rotate()
+		s.lock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	// everything before the active segment is compacted into a segment
+	// that takes the sequence of the newest input
+	seq := s.active.seq - 1
+	if seq == 0 {
+		return nil
+	}
+
+	tmp := filepath.Join(s.dir, segmentName(seq, true)+tmpExtension)
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(s.index))
+	for key := range s.index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	moved := make(map[string]location, len(keys))
+	dropped := make([]string, 0)
+	fb := newFrameBuilder()
+	pending := make([]string, 0, 64)
+	var size int64
+
+	flush := func() error {
+		if fb.len() == 0 {
+			return nil
+		}
+		buf := fb.bytes()
+		if _, err := f.Write(buf); err != nil {
+			return err
+		}
+		for i, key := range pending {
+			loc := moved[key]
+			loc.seq = seq
+			loc.offset = size + fb.offsets[i]
+			moved[key] = loc
+		}
+		size += int64(len(buf))
+		pending = pending[:0]
+		fb.reset()
+		return nil
+	}
+
+	for _, key := range keys {
+		if live != nil && !live([]byte(key)) {
+			dropped = append(dropped, key)
+			continue
+		}
+
+		loc := s.index[key]
+		value, err := s.read(loc)
+		if err == nil {
+			fb.put([]byte(key), value)
+			moved[key] = loc
+			pending = append(pending, key)
+			if fb.len() >= compactionFrameSize {
+				err = flush()
+			}
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+
+	if err := flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	f.Close()
+
+	// the rename is the commit point, on open a compacted segment
+	// supersedes every segment with a lower or equal sequence
+	if err := os.Rename(tmp, filepath.Join(s.dir, segmentName(seq, true))); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := syncDir(s.dir); err != nil {
+		return err
+	}
+
+	compacted, err := openSegment(s.dir, seq, true, os.O_RDONLY)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for old, seg := range s.segments {
+		if old > seq {
+			continue
+		}
+		seg.file.Close()
+		// a previous compacted segment with this sequence has already
+		// been replaced by the rename
+		if old != seq || !seg.compacted {
+			os.Remove(filepath.Join(s.dir, segmentName(old, seg.compacted)))
+		}
+		delete(s.segments, old)
+	}
+	s.segments[seq] = compacted
+
+	for key, loc := range moved {
+		s.index[key] = loc
+	}
+	for _, key := range dropped {
+		delete(s.index, key)
+	}
+
+	return nil
+}
+
+// Keys returns every key in the store in no particular order.
+func (s *Store) Keys() ([][]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	keys := make([][]byte, 0, len(s.index))
+	for key := range s.index {
+		keys = append(keys, []byte(key))
+	}
+
+	return keys, nil
+}
+
+// Len returns the number of keys in the store.
+func (s *Store) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.index)
+}
+
+// Close closes all segments.  Further operations on the store
+// return ErrClosed.
+func (s *Store) Close() error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var err error
+	if !s.config.NoSync {
+		err = s.active.file.Sync()
+	}
+
+	for _, seg := range s.segments {
+		if cerr := seg.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// read returns the value at the provided location, verifying its
+// checksum.
+func (s *Store) read(loc location) ([]byte, error) {
+	seg, ok := s.segments[loc.seq]
+	if !ok {
+		return nil, ErrCorrupt
+	}
+
+	value := make([]byte, loc.length)
+	if _, err := seg.file.ReadAt(value, loc.offset); err != nil {
+		return nil, err
+	}
+
+	if crc32.Checksum(value, crcTable) != loc.crc {
+		return nil, ErrCorrupt
+	}
+
+	return value, nil
+}
+
+// write appends the frame to the active segment and returns the offset
+// at which the frame was written.  A failed write is truncated so the
+// segment never contains a partial frame the store knows about.
+func (s *Store) write(fb *frameBuilder) (int64, error) {
+	buf := fb.bytes()
+	if s.active.size > 0 && s.active.size+int64(len(buf)) > s.config.MaxSegmentSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	offset := s.active.size
+	if _, err := s.active.file.WriteAt(buf, offset); err != nil {
+		s.active.file.Truncate(offset)
+		return 0, err
+	}
+
+	if !s.config.NoSync {
+		if err := s.active.file.Sync(); err != nil {
+			s.active.file.Truncate(offset)
+			return 0, err
+		}
+	}
+
+	s.active.size += int64(len(buf))
+	return offset, nil
+}
+
+// rotate seals the active segment and starts a new one.
+func (s *Store) rotate() error {
+	if err := s.active.file.Sync(); err != nil {
+		return err
+	}
+
+	seg, err := openSegment(s.dir, s.active.seq+1, false, os.O_CREATE|os.O_RDWR)
+	if err != nil {
+		return err
+	}
+
+	if err := syncDir(s.dir); err != nil {
+		seg.file.Close()
+		return err
+	}
+
+	s.segments[seg.seq] = seg
+	s.active = seg
+	return nil
+}
+
+// recover replays the provided segment into the index.  If truncate is
+// true a torn or corrupt tail is removed rather than returned as an
+// error; this is only safe for the segment that was last written.
+func (s *Store) recover(seg *segment, truncate bool) error {
+	if _, err := seg.file.Seek(0, 0); err != nil {
+		return err
+	}
+
+	info, err := seg.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	end, err := scan(seg.file, info.Size(), func(bodyOffset int64, entries []entry) {
+		for _, e := range entries {
+			switch e.op {
+			case opPut:
+				s.index[string(e.key)] = location{
+					seq:    seg.seq,
+					offset: bodyOffset + e.offset,
+					length: e.length,
+					crc:    e.crc,
+				}
+			case opDelete:
+				delete(s.index, string(e.key))
+			}
+		}
+	})
+
+	if err == ErrCorrupt && truncate {
+		if err := seg.file.Truncate(end); err != nil {
+			return err
+		}
+		if err := seg.file.Sync(); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	seg.size = end
+	return nil
+}
+
+func openSegment(dir string, seq uint64, compacted bool, flag int) (*segment, error) {
+	f, err := os.OpenFile(filepath.Join(dir, segmentName(seq, compacted)), flag, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &segment{
+		seq:       seq,
+		compacted: compacted,
+		file:      f,
+		size:      info.Size(),
+	}, nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// Open opens the store in the provided directory, creating it if
+// necessary, and rebuilds the index from the segments found there.
+// Leftovers of an interrupted compaction are cleaned up.
+func Open(dir string, cfg Config) (*Store, error) {
+	if cfg.MaxSegmentSize <= 0 {
+		cfg.MaxSegmentSize = DefaultConfig().MaxSegmentSize
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		logs      []uint64
+		compacted []uint64
+		newest    uint64
+	)
+	for _, e := range entries {
+		name := e.Name()
+		if filepath.Ext(name) == tmpExtension {
+			os.Remove(filepath.Join(dir, name))
+			continue
+		}
+
+		seq, isCompacted, ok := parseSegmentName(name)
+		if !ok {
+			continue
+		}
+
+		if isCompacted {
+			compacted = append(compacted, seq)
+			if seq > newest {
+				newest = seq
+			}
+		} else {
+			logs = append(logs, seq)
+		}
+	}
+
+	s := &Store{
+		dir:      dir,
+		config:   cfg,
+		index:    make(map[string]location),
+		segments: make(map[uint64]*segment),
+		frame:    newFrameBuilder(),
+	}
+
+	fail := func(err error) (*Store, error) {
+		for _, seg := range s.segments {
+			seg.file.Close()
+		}
+		return nil, err
+	}
+
+	// the newest compacted segment supersedes everything at or below
+	// its sequence, anything else there was left by a crash
+	for _, seq := range compacted {
+		if seq < newest {
+			os.Remove(filepath.Join(dir, segmentName(seq, true)))
+		}
+	}
+
+	if len(compacted) > 0 {
+		seg, err := openSegment(dir, newest, true, os.O_RDONLY)
+		if err != nil {
+			return fail(err)
+		}
+		s.segments[newest] = seg
+		if err := s.recover(seg, false); err != nil {
+			return fail(err)
+		}
+	}
+
+	sort.Slice(logs, func(i, j int) bool { return logs[i] < logs[j] })
+	live := logs[:0]
+	for _, seq := range logs {
+		if len(compacted) > 0 && seq <= newest {
+			os.Remove(filepath.Join(dir, segmentName(seq, false)))
+			continue
+		}
+		live = append(live, seq)
+	}
+
+	for i, seq := range live {
+		last := i == len(live)-1
+		flag := os.O_RDONLY
+		if last {
+			flag = os.O_RDWR
+		}
+
+		seg, err := openSegment(dir, seq, false, flag)
+		if err != nil {
+			return fail(err)
+		}
+		s.segments[seq] = seg
+		if err := s.recover(seg, last); err != nil {
+			return fail(err)
+		}
+
+		if last {
+			s.active = seg
+		}
+	}
+
+	if s.active == nil {
+		seq := newest + 1
+		seg, err := openSegment(dir, seq, false, os.O_CREATE|os.O_RDWR)
+		if err != nil {
+			return fail(err)
+		}
+		s.segments[seq] = seg
+		s.active = seg
+		if err := syncDir(dir); err != nil {
+			return fail(err)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,430 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filestore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	btree "github.com/Workiva/go-datastructures/btree/immutable"
+)
+
+func payload(i int) *btree.Payload {
+	return &btree.Payload{
+		Key:     []byte(fmt.Sprintf(`key-%04d`, i)),
+		Payload: []byte(fmt.Sprintf(`value-%d`, i)),
+	}
+}
+
+func payloads(start, stop int) []*btree.Payload {
+	items := make([]*btree.Payload, 0, stop-start)
+	for i := start; i < stop; i++ {
+		items = append(items, payload(i))
+	}
+
+	return items
+}
+
+func keysOf(items []*btree.Payload) [][]byte {
+	keys := make([][]byte, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.Key)
+	}
+
+	return keys
+}
+
+func openStore(t *testing.T, dir string, cfg Config) *Store {
+	s, err := Open(dir, cfg)
+	require.NoError(t, err)
+	return s
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+
+	return names
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	s := openStore(t, t.TempDir(), DefaultConfig())
+	defer s.Close()
+
+	items := payloads(0, 10)
+	require.NoError(t, s.Save(items...))
+
+	result, err := s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+	assert.Equal(t, 10, s.Len())
+
+	result, err = s.Load([]byte(`missing`), items[3].Key)
+	require.NoError(t, err)
+	assert.Equal(t, []*btree.Payload{nil, items[3]}, result)
+
+	overwrite := &btree.Payload{Key: items[3].Key, Payload: []byte(`new`)}
+	require.NoError(t, s.Save(overwrite))
+	result, err = s.Load(items[3].Key)
+	require.NoError(t, err)
+	assert.Equal(t, []*btree.Payload{overwrite}, result)
+}
+
+func TestReopen(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+	items := payloads(0, 100)
+	require.NoError(t, s.Save(items[:50]...))
+	require.NoError(t, s.Save(items[50:]...))
+	require.NoError(t, s.Delete(items[0].Key, items[1].Key, []byte(`missing`)))
+	require.NoError(t, s.Close())
+
+	_, err := s.Load(items[0].Key)
+	assert.Equal(t, ErrClosed, err)
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+
+	assert.Equal(t, 98, s.Len())
+	result, err := s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Nil(t, result[0])
+	assert.Nil(t, result[1])
+	assert.Equal(t, items[2:], result[2:])
+}
+
+func TestSegmentRotation(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.MaxSegmentSize = 128
+	s := openStore(t, dir, cfg)
+
+	items := payloads(0, 50)
+	for _, item := range items {
+		require.NoError(t, s.Save(item))
+	}
+	require.NoError(t, s.Close())
+	assert.True(t, len(segmentFiles(t, dir)) > 1)
+
+	s = openStore(t, dir, cfg)
+	defer s.Close()
+	result, err := s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+}
+
+func TestRecoverTornWrite(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+	items := payloads(0, 20)
+	require.NoError(t, s.Save(items[:10]...))
+	require.NoError(t, s.Save(items[10:]...))
+	size := s.active.size
+	path := filepath.Join(dir, segmentName(s.active.seq, false))
+	require.NoError(t, s.Close())
+
+	// simulate a crash partway through the second batch
+	require.NoError(t, os.Truncate(path, size-5))
+
+	s = openStore(t, dir, DefaultConfig())
+	result, err := s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Equal(t, items[:10], result[:10])
+	for _, item := range result[10:] {
+		assert.Nil(t, item)
+	}
+
+	// writes continue after the last good frame
+	require.NoError(t, s.Save(items[10:]...))
+	require.NoError(t, s.Close())
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+	result, err = s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+}
+
+func TestRecoverCorruptLength(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+	items := payloads(0, 10)
+	require.NoError(t, s.Save(items...))
+	size := s.active.size
+	path := filepath.Join(dir, segmentName(s.active.seq, false))
+	require.NoError(t, s.Close())
+
+	// a torn header claiming a body far larger than the segment
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// the length is rejected before anything is allocated for it
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	end, err := scan(bytes.NewReader(data), int64(len(data)), func(int64, []entry) {})
+	runtime.ReadMemStats(&after)
+	assert.Equal(t, ErrCorrupt, err)
+	assert.Equal(t, size, end)
+	assert.True(t, after.TotalAlloc-before.TotalAlloc < 1<<20)
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+	assert.Equal(t, size, s.active.size)
+	result, err := s.Load(keysOf(items)...)
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+}
+
+func TestCorruptSealedSegment(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.MaxSegmentSize = 64
+	s := openStore(t, dir, cfg)
+	require.NoError(t, s.Save(payloads(0, 5)...))
+	require.NoError(t, s.Save(payloads(5, 10)...))
+	require.NoError(t, s.Close())
+
+	path := filepath.Join(dir, segmentName(1, false))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	_, err = Open(dir, cfg)
+	assert.Equal(t, ErrCorrupt, err)
+}
+
+func TestCompact(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.MaxSegmentSize = 256
+	s := openStore(t, dir, cfg)
+
+	items := payloads(0, 100)
+	for i := 0; i < len(items); i += 10 {
+		require.NoError(t, s.Save(items[i:i+10]...))
+	}
+	require.NoError(t, s.Delete(keysOf(items[:10])...))
+
+	// keep only even keys
+	keep := make(map[string]bool)
+	for i := 10; i < len(items); i += 2 {
+		keep[string(items[i].Key)] = true
+	}
+	require.NoError(t, s.Compact(func(key []byte) bool {
+		return keep[string(key)]
+	}))
+	assert.Equal(t, len(keep), s.Len())
+
+	check := func(s *Store) {
+		result, err := s.Load(keysOf(items)...)
+		require.NoError(t, err)
+		for i, item := range result {
+			if keep[string(items[i].Key)] {
+				assert.Equal(t, items[i], item)
+			} else {
+				assert.Nil(t, item)
+			}
+		}
+	}
+	check(s)
+
+	// writes after a compaction land in the active segment
+	require.NoError(t, s.Save(items[1]))
+	keep[string(items[1].Key)] = true
+	check(s)
+
+	require.NoError(t, s.Compact(nil))
+	check(s)
+	require.NoError(t, s.Close())
+
+	assert.Equal(t, []string{segmentName(s.active.seq-1, true), segmentName(s.active.seq, false)}, segmentFiles(t, dir))
+
+	s = openStore(t, dir, cfg)
+	defer s.Close()
+	check(s)
+}
+
+func TestLoadDuringCompact(t *testing.T) {
+	s := openStore(t, t.TempDir(), DefaultConfig())
+	defer s.Close()
+	items := payloads(0, 10)
+	require.NoError(t, s.Save(items...))
+
+	// loads are not blocked while the live values are rewritten
+	loaded := 0
+	require.NoError(t, s.Compact(func(key []byte) bool {
+		result, err := s.Load(key)
+		require.NoError(t, err)
+		if assert.NotNil(t, result[0]) {
+			loaded++
+		}
+		return true
+	}))
+	assert.Equal(t, len(items), loaded)
+}
+
+func TestInterruptedCompaction(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+	items := payloads(0, 10)
+	require.NoError(t, s.Save(items...))
+	require.NoError(t, s.Compact(nil))
+	require.NoError(t, s.Save(payload(10)))
+	require.NoError(t, s.Close())
+
+	// a crash before the rename leaves a temporary file behind
+	tmp := filepath.Join(dir, segmentName(5, true)+tmpExtension)
+	require.NoError(t, os.WriteFile(tmp, []byte(`partial`), 0644))
+	// a crash after the rename leaves superseded segments behind
+	stale := filepath.Join(dir, segmentName(1, false))
+	require.NoError(t, os.WriteFile(stale, []byte(`stale`), 0644))
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+
+	result, err := s.Load(keysOf(append(items, payload(10)))...)
+	require.NoError(t, err)
+	assert.Equal(t, append(items, payload(10)), result)
+
+	_, err = os.Stat(tmp)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCorruptValue(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+	defer s.Close()
+	item := payload(0)
+	require.NoError(t, s.Save(item))
+
+	loc := s.index[string(item.Key)]
+	_, err := s.active.file.WriteAt([]byte{'x'}, loc.offset)
+	require.NoError(t, err)
+
+	_, err = s.Load(item.Key)
+	assert.Equal(t, ErrCorrupt, err)
+}
+
+var comparator = func(item1, item2 interface{}) int {
+	i1, i2 := item1.(int64), item2.(int64)
+	if i1 < i2 {
+		return -1
+	}
+	if i1 > i2 {
+		return 1
+	}
+
+	return 0
+}
+
+func TestTreeSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+
+	cfg := btree.DefaultConfig(s, comparator)
+	cfg.NodeWidth = 10
+	mutable := btree.New(cfg).AsMutable()
+	items := make([]*btree.Item, 0, 1000)
+	for i := int64(0); i < 1000; i++ {
+		items = append(items, &btree.Item{Value: i, Payload: []byte(fmt.Sprint(i))})
+	}
+	_, err := mutable.AddItems(items...)
+	require.NoError(t, err)
+	rt, err := mutable.Commit()
+	require.NoError(t, err)
+	id := rt.ID()
+	require.NoError(t, s.Close())
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+	rt, err = btree.Load(s, id, comparator)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, rt.Len())
+
+	result := make([]*btree.Item, 0, len(items))
+	err = rt.ApplyRange(int64(0), int64(1000), func(item *btree.Item) bool {
+		result = append(result, item)
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+}
+
+func TestGCAndCompact(t *testing.T) {
+	dir := t.TempDir()
+	s := openStore(t, dir, DefaultConfig())
+
+	cfg := btree.DefaultConfig(s, comparator)
+	cfg.NodeWidth = 10
+	mutable := btree.New(cfg).AsMutable()
+	items := make([]*btree.Item, 0, 500)
+	for i := int64(0); i < 500; i++ {
+		items = append(items, &btree.Item{Value: i, Payload: []byte(fmt.Sprint(i))})
+	}
+	_, err := mutable.AddItems(items...)
+	require.NoError(t, err)
+	rt, err := mutable.Commit()
+	require.NoError(t, err)
+
+	mutable = rt.AsMutable()
+	_, err = mutable.DeleteItems(int64(0), int64(1), int64(2))
+	require.NoError(t, err)
+	rt, err = mutable.Commit()
+	require.NoError(t, err)
+	id := rt.ID()
+	before := s.Len()
+
+	deleted, err := btree.GC(s, id)
+	require.NoError(t, err)
+	assert.Equal(t, before-len(deleted), s.Len())
+	require.NoError(t, s.Compact(nil))
+	require.NoError(t, s.Close())
+
+	s = openStore(t, dir, DefaultConfig())
+	defer s.Close()
+	assert.Equal(t, before-len(deleted), s.Len())
+	rt, err = btree.Load(s, id, comparator)
+	require.NoError(t, err)
+
+	result := make([]*btree.Item, 0, len(items))
+	err = rt.ApplyRange(int64(0), int64(500), func(item *btree.Item) bool {
+		result = append(result, item)
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, items[3:], result)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package btree
+
+/*
+This file contains the logic for reclaiming persisted nodes.  As trees
+are copy-on-write, every commit leaves behind the nodes of the previous
+version.  Collection is a simple mark and sweep: every node reachable
+from the trees to keep is marked, a level at a time so nodes can be
+loaded in batches, and every other key in persistence is deleted.
+Subtrees shared between the kept trees are only walked once.
+*/
+
+// gcBatchSize is the maximum number of keys loaded or deleted
+// in a single call to the persister.
+const gcBatchSize = 1000
+
+// reachable returns the set of keys, both trees and nodes, reachable
+// from the provided tree IDs.
+func reachable(p Persister, roots ...ID) (map[string]struct{}, error) {
+	marked := make(map[string]struct{}, len(roots))
+	level := make([]ID, 0, len(roots))
+
+	for _, id := range roots {
+		if _, ok := marked[string(id)]; ok {
+			continue
+		}
+
+		items, err := p.Load(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 0 || items[0] == nil {
+			return nil, ErrTreeNotFound
+		}
+
+		t := &Tr{}
+		if _, err := t.UnmarshalMsg(items[0].Payload); err != nil {
+			return nil, err
+		}
+
+		marked[string(id)] = struct{}{}
+		if len(t.Root) == 0 {
+			continue
+		}
+
+		if _, ok := marked[string(t.Root)]; !ok {
+			marked[string(t.Root)] = struct{}{}
+			level = append(level, t.Root)
+		}
+	}
+
+	for len(level) > 0 {
+		next := make([]ID, 0, len(level))
+		for len(level) > 0 {
+			batch := level
+			if len(batch) > gcBatchSize {
+				batch = batch[:gcBatchSize]
+			}
+			level = level[len(batch):]
+
+			keys := make([][]byte, 0, len(batch))
+			for _, id := range batch {
+				keys = append(keys, id)
+			}
+
+			items, err := p.Load(keys...)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range items {
+				if item == nil {
+					return nil, ErrNodeNotFound
+				}
+
+				n, err := nodeFromBytes(nil, item.Payload)
+				if err != nil {
+					return nil, err
+				}
+
+				if n.IsLeaf {
+					continue
+				}
+
+				for _, key := range n.ChildKeys {
+					id := ID(key.ID())
+					if _, ok := marked[string(id)]; ok {
+						continue
+					}
+
+					marked[string(id)] = struct{}{}
+					next = append(next, id)
+				}
+			}
+		}
+
+		level = next
+	}
+
+	return marked, nil
+}
+
+// GC deletes every persisted tree and node that is not reachable from
+// the trees identified by the provided IDs and returns the keys that
+// were deleted.  The persister must implement Deleter, otherwise
+// ErrDeleteNotSupported is returned.  An error is also returned if any
+// of the trees to keep could not be walked, in which case nothing is
+// deleted.
+//
+// Collection does not coordinate with commits.  Any tree committed to
+// the same persister while collecting must be included in the trees to
+// keep or its nodes may be deleted.
+func GC(p Persister, keep ...ID) ([][]byte, error) {
+	d, ok := p.(Deleter)
+	if !ok {
+		return nil, ErrDeleteNotSupported
+	}
+
+	marked, err := reachable(p, keep...)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := d.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	garbage := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := marked[string(key)]; !ok {
+			garbage = append(garbage, key)
+		}
+	}
+
+	for i := 0; i < len(garbage); i += gcBatchSize {
+		j := i + gcBatchSize
+		if j > len(garbage) {
+			j = len(garbage)
+		}
+
+		if err := d.Delete(garbage[i:j]...); err != nil {
+			return garbage[:i], err
+		}
+	}
+
+	return garbage, nil
+}
@@ -25,7 +25,6 @@ are optimized for dealing with large amounts of data.
 Future work includes:
 
 1) Optimization
-2) Range scans
 
 Usage:
 
@@ -38,6 +37,10 @@ rt, err := mutable.Commit() // saves all mutated nodes
 .. rt reading/operations
 
 Once a mutable has been committed, its further operations are undefined.
+
+Committing never modifies persisted nodes, so nodes belonging only to
+older versions of a tree accumulate in persistence.  These can be
+reclaimed with GC given the IDs of the trees that should be kept.
 */
 package btree
 
@@ -48,6 +51,11 @@ type Tree interface {
 	// in that range in order.  If a key could not be found, it is
 	// skipped.
 	Apply(fn func(item *Item), keys ...interface{}) error
+	// ApplyRange applies the provided function to every item whose value
+	// falls within [start, stop) in ascending order.  Iteration halts
+	// early if the function returns false.  An error is returned if
+	// the tree could not be traversed.
+	ApplyRange(start, stop interface{}, fn func(item *Item) bool) error
 	// ID returns the identifier for this tree.
 	ID() ID
 	// Len returns the number of items in the tree.
@@ -65,6 +73,42 @@ type ReadableTree interface {
 	// has common mutations and you can create as many mutable versions of this
 	// tree as you'd like.  However, the returned mutable is not threadsafe.
 	AsMutable() MutableTree
+	// Cursor returns a cursor positioned before the first item in this
+	// tree.  Nodes are loaded from persistence as the cursor visits them
+	// so the tree never needs to be loaded in its entirety.
+	Cursor() Cursor
+}
+
+// Cursor provides resumable, ordered iteration over a tree.  A cursor
+// begins positioned before the first item.  Moving past either end of
+// the tree leaves the cursor positioned beyond that end, from which
+// moving in the opposite direction returns the first or last item.  A
+// cursor is not threadsafe, but any number of cursors may be used
+// concurrently on the same readable tree.
+type Cursor interface {
+	// First moves the cursor to the first item in the tree and returns
+	// a bool indicating if such an item exists.
+	First() bool
+	// Last moves the cursor to the last item in the tree and returns
+	// a bool indicating if such an item exists.
+	Last() bool
+	// Seek moves the cursor to the first item with a value greater than
+	// or equal to the provided value and returns a bool indicating if
+	// such an item exists.  Seeking to the last value seen allows
+	// iteration to be resumed.
+	Seek(value interface{}) bool
+	// Next moves the cursor to the next item and returns a bool
+	// indicating if there is an item at the new position.
+	Next() bool
+	// Prev moves the cursor to the previous item and returns a bool
+	// indicating if there is an item at the new position.
+	Prev() bool
+	// Item returns the item at the current position or nil if the
+	// cursor is not positioned at an item.
+	Item() *Item
+	// Err returns any error encountered loading nodes during the last
+	// movement of the cursor.
+	Err() error
 }
 
 // MutableTree represents a mutable version of the btree.  This interface
@@ -97,8 +141,20 @@ type Payload struct {
 
 // Perister describes the interface of the different implementations.
 // Given that we expect that datastrutures are immutable, we never
-// have the need to delete.
+// have the need to delete outside of garbage collection, which is
+// an optional capability described by Deleter.
 type Persister interface {
 	Save(items ...*Payload) error
 	Load(keys ...[]byte) ([]*Payload, error)
 }
+
+// Deleter is implemented by persisters that allow unreachable trees
+// and nodes to be reclaimed with GC.
+type Deleter interface {
+	Persister
+	// Keys returns the key of every payload in persistence.
+	Keys() ([][]byte, error)
+	// Delete removes the payloads with the provided keys.  Keys that
+	// could not be found are ignored.
+	Delete(keys ...[]byte) error
+}
@@ -64,6 +64,29 @@ func (e *ephemeral) Load(keys ...[]byte) ([]*Payload, error) {
 	return items, nil
 }
 
+func (e *ephemeral) Keys() ([][]byte, error) {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	keys := make([][]byte, 0, len(e.mp))
+	for k := range e.mp {
+		keys = append(keys, []byte(k))
+	}
+
+	return keys, nil
+}
+
+func (e *ephemeral) Delete(keys ...[]byte) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, k := range keys {
+		delete(e.mp, string(k))
+	}
+
+	return nil
+}
+
 const (
 	maxValue = int64(100000)
 )
@@ -968,6 +991,386 @@ func TestGenerativeOperations(t *testing.T) {
 	}
 }
 
+func TestApplyRange(t *testing.T) {
+	cfg := defaultConfig()
+	rt := New(cfg)
+	mutable := rt.AsMutable()
+	generated := generateRandomItems(1000)
+	_, err := mutable.AddItems(generated...)
+	require.NoError(t, err)
+
+	id := mutable.ID()
+	_, err = mutable.Commit()
+	require.NoError(t, err)
+
+	rt, err = Load(cfg.Persister, id, comparator)
+	require.NoError(t, err)
+
+	oc := toOrdered(generated)
+	for i := 0; i < 100; i++ {
+		start, stop := generateRandomQuery()
+		expected := make(items, 0, 100)
+		for i := oc.search(start); i < len(oc) && comparator(oc[i].Value, stop) < 0; i++ {
+			expected = append(expected, oc[i])
+		}
+
+		result := make(items, 0, len(expected))
+		err := rt.ApplyRange(start, stop, func(item *Item) bool {
+			result = append(result, item)
+			return true
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+}
+
+func TestApplyRangeHaltsEarly(t *testing.T) {
+	rt := New(defaultConfig())
+	mutable := rt.AsMutable()
+	_, err := mutable.AddItems(generateLinearItems(100)...)
+	require.NoError(t, err)
+
+	count := 0
+	err = mutable.ApplyRange(int64(10), int64(50), func(item *Item) bool {
+		count++
+		return count < 5
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
+
+func TestCursor(t *testing.T) {
+	cfg := defaultConfig()
+	rt := New(cfg)
+	mutable := rt.AsMutable()
+	generated := generateRandomItems(1000)
+	_, err := mutable.AddItems(generated...)
+	require.NoError(t, err)
+	rt, err = mutable.Commit()
+	require.NoError(t, err)
+
+	oc := toOrdered(generated)
+	c := rt.Cursor()
+	assert.Nil(t, c.Item())
+
+	result := make(items, 0, len(oc))
+	for c.Next() {
+		result = append(result, c.Item())
+	}
+	require.NoError(t, c.Err())
+	assert.Equal(t, oc.toItems(), result)
+	assert.False(t, c.Next())
+	assert.Nil(t, c.Item())
+
+	result = result[:0]
+	for c.Prev() {
+		result = append(result, c.Item())
+	}
+	require.NoError(t, c.Err())
+	assert.Equal(t, reverse(oc.toItems()), result)
+	assert.False(t, c.Prev())
+
+	// resume iteration from the middle of the tree
+	middle := oc[len(oc)/2]
+	require.True(t, c.Seek(middle.Value))
+	assert.Equal(t, middle, c.Item())
+	require.True(t, c.Next())
+	assert.Equal(t, oc[len(oc)/2+1], c.Item())
+	require.True(t, c.Prev())
+	require.True(t, c.Prev())
+	assert.Equal(t, oc[len(oc)/2-1], c.Item())
+
+	require.True(t, c.Seek(int64(-1)))
+	assert.Equal(t, oc[0], c.Item())
+	assert.False(t, c.Seek(maxValue))
+	require.True(t, c.Last())
+	assert.Equal(t, oc[len(oc)-1], c.Item())
+	require.True(t, c.First())
+	assert.Equal(t, oc[0], c.Item())
+}
+
+func TestCursorEmptyTree(t *testing.T) {
+	rt := New(defaultConfig())
+	c := rt.Cursor()
+
+	assert.False(t, c.Next())
+	assert.False(t, c.Prev())
+	assert.False(t, c.Seek(int64(1)))
+	assert.False(t, c.First())
+	assert.False(t, c.Last())
+	assert.Nil(t, c.Item())
+	assert.NoError(t, c.Err())
+}
+
+type countingPersister struct {
+	Persister
+	loads int
+}
+
+func (c *countingPersister) Load(keys ...[]byte) ([]*Payload, error) {
+	c.loads += len(keys)
+	return c.Persister.Load(keys...)
+}
+
+func TestCursorLoadsSinglePath(t *testing.T) {
+	persister := &countingPersister{Persister: newEphemeral()}
+	cfg := defaultConfig()
+	cfg.Persister = persister
+	rt := New(cfg)
+	mutable := rt.AsMutable()
+	_, err := mutable.AddItems(generateLinearItems(1000)...)
+	require.NoError(t, err)
+	id := mutable.ID()
+	_, err = mutable.Commit()
+	require.NoError(t, err)
+
+	rt, err = Load(persister, id, comparator)
+	require.NoError(t, err)
+
+	persister.loads = 0
+	c := rt.Cursor()
+	require.True(t, c.Seek(int64(500)))
+	assert.Equal(t, int64(500), c.Item().Value)
+	// with a node width of 10, 1000 items are at most 4 levels deep
+	assert.True(t, persister.loads <= 4)
+}
+
+func applyAll(t *testing.T, rt ReadableTree) items {
+	result := make(items, 0, rt.Len())
+	err := rt.ApplyRange(int64(0), maxValue, func(item *Item) bool {
+		result = append(result, item)
+		return true
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestGC(t *testing.T) {
+	cfg := defaultConfig()
+	p := cfg.Persister.(*ephemeral)
+	linear := generateLinearItems(1000)
+
+	mutable := New(cfg).AsMutable()
+	_, err := mutable.AddItems(linear...)
+	require.NoError(t, err)
+	v1, err := mutable.Commit()
+	require.NoError(t, err)
+	before := len(p.mp)
+
+	mutable = v1.AsMutable()
+	_, err = mutable.DeleteItems(itemsToValues(linear[:100]...)...)
+	require.NoError(t, err)
+	v2, err := mutable.Commit()
+	require.NoError(t, err)
+
+	// nodes discarded while mutating are committed as well, those
+	// are collected even when keeping both versions
+	_, err = GC(p, v1.ID(), v2.ID())
+	require.NoError(t, err)
+	rt, err := Load(p, v1.ID(), comparator)
+	require.NoError(t, err)
+	assert.Equal(t, linear, applyAll(t, rt))
+
+	deleted, err := GC(p, v2.ID())
+	require.NoError(t, err)
+	assert.NotEmpty(t, deleted)
+	assert.Contains(t, deleted, []byte(v1.ID()))
+	// most nodes of the first version are shared with the second
+	assert.True(t, len(p.mp) < before)
+
+	_, err = Load(p, v1.ID(), comparator)
+	assert.Equal(t, ErrTreeNotFound, err)
+
+	rt, err = Load(p, v2.ID(), comparator)
+	require.NoError(t, err)
+	assert.Equal(t, linear[100:], applyAll(t, rt))
+
+	// everything left is reachable
+	deleted, err = GC(p, v2.ID())
+	require.NoError(t, err)
+	assert.Len(t, deleted, 0)
+}
+
+func TestGCEmptyTree(t *testing.T) {
+	cfg := defaultConfig()
+	p := cfg.Persister.(*ephemeral)
+
+	rt, err := New(cfg).AsMutable().Commit()
+	require.NoError(t, err)
+
+	deleted, err := GC(p, rt.ID())
+	require.NoError(t, err)
+	assert.Len(t, deleted, 0)
+
+	deleted, err = GC(p)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{rt.ID()}, deleted)
+	assert.Len(t, p.mp, 0)
+}
+
+func TestGCMissingTree(t *testing.T) {
+	cfg := defaultConfig()
+	p := cfg.Persister.(*ephemeral)
+
+	mutable := New(cfg).AsMutable()
+	_, err := mutable.AddItems(generateLinearItems(100)...)
+	require.NoError(t, err)
+	_, err = mutable.Commit()
+	require.NoError(t, err)
+	before := len(p.mp)
+
+	_, err = GC(p, newID())
+	assert.Equal(t, ErrTreeNotFound, err)
+	assert.Len(t, p.mp, before)
+}
+
+func TestGCNotSupported(t *testing.T) {
+	_, err := GC(newDelayed())
+	assert.Equal(t, ErrDeleteNotSupported, err)
+}
+
+func collectDiff(t *testing.T, from, to ReadableTree) []*Change {
+	changes := make([]*Change, 0, 10)
+	err := Diff(from, to, func(change *Change) bool {
+		changes = append(changes, change)
+		return true
+	})
+	require.NoError(t, err)
+	return changes
+}
+
+func TestDiff(t *testing.T) {
+	persister := &countingPersister{Persister: newEphemeral()}
+	cfg := defaultConfig()
+	cfg.Persister = persister
+	linear := generateLinearItems(10000)
+
+	mutable := New(cfg).AsMutable()
+	_, err := mutable.AddItems(linear...)
+	require.NoError(t, err)
+	v1, err := mutable.Commit()
+	require.NoError(t, err)
+
+	mutable = v1.AsMutable()
+	removed := linear[5000]
+	_, err = mutable.DeleteItems(removed.Value)
+	require.NoError(t, err)
+	changed := newItem(int64(7000))
+	_, err = mutable.AddItems(changed)
+	require.NoError(t, err)
+	added := newItem(int64(20000))
+	_, err = mutable.AddItems(added)
+	require.NoError(t, err)
+	v2, err := mutable.Commit()
+	require.NoError(t, err)
+
+	v1, err = Load(persister, v1.ID(), comparator)
+	require.NoError(t, err)
+	v2, err = Load(persister, v2.ID(), comparator)
+	require.NoError(t, err)
+
+	persister.loads = 0
+	changes := collectDiff(t, v1, v2)
+	assert.Equal(t, []*Change{
+		{Type: Removed, Old: removed},
+		{Type: Changed, Old: linear[7000], New: changed},
+		{Type: Added, New: added},
+	}, changes)
+	// only the paths to the changed leaves are loaded, 10000 items
+	// with a node width of 10 is well over 1000 nodes
+	assert.True(t, persister.loads < 100, `loaded %d nodes`, persister.loads)
+
+	changes = collectDiff(t, v2, v1)
+	assert.Equal(t, []*Change{
+		{Type: Added, New: removed},
+		{Type: Changed, Old: changed, New: linear[7000]},
+		{Type: Removed, Old: added},
+	}, changes)
+
+	persister.loads = 0
+	assert.Len(t, collectDiff(t, v2, v2), 0)
+	assert.Equal(t, 0, persister.loads)
+}
+
+func TestDiffRandom(t *testing.T) {
+	cfg := defaultConfig()
+	generated := generateRandomItems(1000)
+
+	mutable := New(cfg).AsMutable()
+	_, err := mutable.AddItems(generated...)
+	require.NoError(t, err)
+	v1, err := mutable.Commit()
+	require.NoError(t, err)
+
+	expected := make(map[interface{}]ChangeType)
+	mutable = v1.AsMutable()
+	for _, item := range generated[:100] {
+		_, err = mutable.DeleteItems(item.Value)
+		require.NoError(t, err)
+		expected[item.Value] = Removed
+	}
+	for _, item := range generated[100:200] {
+		_, err = mutable.AddItems(newItem(item.Value))
+		require.NoError(t, err)
+		expected[item.Value] = Changed
+	}
+	for i := 0; i < 100; i++ {
+		item := newItem(maxValue + int64(i))
+		_, err = mutable.AddItems(item)
+		require.NoError(t, err)
+		expected[item.Value] = Added
+	}
+	v2, err := mutable.Commit()
+	require.NoError(t, err)
+
+	changes := collectDiff(t, v1, v2)
+	require.Len(t, changes, len(expected))
+	var last interface{}
+	for _, change := range changes {
+		item := change.New
+		if item == nil {
+			item = change.Old
+		}
+		assert.Equal(t, expected[item.Value], change.Type)
+		if last != nil {
+			assert.True(t, comparator(last, item.Value) < 0)
+		}
+		last = item.Value
+	}
+}
+
+func TestDiffEmptyTree(t *testing.T) {
+	cfg := defaultConfig()
+	empty := New(cfg)
+	linear := generateLinearItems(100)
+
+	mutable := empty.AsMutable()
+	_, err := mutable.AddItems(linear...)
+	require.NoError(t, err)
+	rt, err := mutable.Commit()
+	require.NoError(t, err)
+
+	changes := collectDiff(t, empty, rt)
+	require.Len(t, changes, 100)
+	for i, change := range changes {
+		assert.Equal(t, &Change{Type: Added, New: linear[i]}, change)
+	}
+
+	changes = collectDiff(t, rt, empty)
+	require.Len(t, changes, 100)
+	for i, change := range changes {
+		assert.Equal(t, &Change{Type: Removed, Old: linear[i]}, change)
+	}
+
+	count := 0
+	err = Diff(empty, rt, func(change *Change) bool {
+		count++
+		return count < 10
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+}
+
 func BenchmarkGetitems(b *testing.B) {
 	number := 100
 	cfg := defaultConfig()
@@ -157,6 +157,74 @@ func newApplyAction(fn func(common.Comparator) bool, start, stop common.Comparat
 	return aa
 }
 
+type removeRangeAction struct {
+	start, stop common.Comparator
+	completer   *sync.WaitGroup
+}
+
+func (rra *removeRangeAction) operation() operation {
+	return removeRange
+}
+
+func (rra *removeRangeAction) nodes() []*node {
+	return nil
+}
+
+func (rra *removeRangeAction) addNode(i int64, n *node) {}
+
+func (rra *removeRangeAction) keys() common.Comparators {
+	return nil
+}
+
+func (rra *removeRangeAction) complete() {
+	rra.completer.Done()
+}
+
+func newRemoveRangeAction(start, stop common.Comparator) *removeRangeAction {
+	rra := &removeRangeAction{
+		start:     start,
+		stop:      stop,
+		completer: new(sync.WaitGroup),
+	}
+	rra.completer.Add(1)
+	return rra
+}
+
+type iterAction struct {
+	start, stop common.Comparator
+	leaves      []*keys // Keys of the leaves that may hold the range, each pinned
+	i           uint64  // Position of start in the first leaf
+	completer   *sync.WaitGroup
+}
+
+func (ia *iterAction) operation() operation {
+	return iterate
+}
+
+func (ia *iterAction) nodes() []*node {
+	return nil
+}
+
+func (ia *iterAction) addNode(i int64, n *node) {}
+
+func (ia *iterAction) keys() common.Comparators {
+	return nil
+}
+
+func (ia *iterAction) complete() {
+	ia.completer.Done()
+}
+
+func newIterAction(start, stop common.Comparator) *iterAction {
+	ia := &iterAction{
+		start:     start,
+		stop:      stop,
+		completer: new(sync.WaitGroup),
+	}
+	ia.completer.Add(1)
+	return ia
+}
+
 func minUint64(choices ...uint64) uint64 {
 	min := choices[0]
 	for i := 1; i < len(choices); i++ {
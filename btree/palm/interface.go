@@ -63,7 +63,29 @@ type BTree interface {
 	// provided start and stop Comparators.  Start is inclusive while
 	// stop is exclusive, ie [start, stop).
 	Query(start, stop common.Comparator) common.Comparators
+	// DeleteRange will remove every key that falls within the
+	// provided start and stop Comparators, ie [start, stop), as
+	// part of a single batch.  Keys added by operations queued
+	// before it are removed, those queued after it are not.
+	DeleteRange(start, stop common.Comparator)
+	// Iter returns an iterator over a snapshot of the keys that fall
+	// within the provided start and stop Comparators, ie
+	// [start, stop), taken once the operations queued before it are
+	// applied.  The tree is not held while the iterator is open.
+	Iter(start, stop common.Comparator) Iterator
 	// Dispose will clean up any resources used by this tree.  This
 	// must be called to prevent a memory leak.
 	Dispose()
 }
+
+// Iterator walks a range of keys in the tree in ascending order.
+type Iterator interface {
+	// Next moves the iterator to the next key and returns a bool
+	// indicating if a key was found.
+	Next() bool
+	// Value returns the key at the iterator's current position.
+	Value() common.Comparator
+	// Close stops the iterator and releases the snapshot it reads.
+	// Close is safe to call more than once.
+	Close()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package palm
+
+import "github.com/Workiva/go-datastructures/common"
+
+type iterator struct {
+	stop   common.Comparator
+	leaves []*keys // Pinned keys of the leaves not yet passed
+	i      uint64  // Position of the next key in leaves[0]
+	value  common.Comparator
+	closed bool
+}
+
+func (iter *iterator) Next() bool {
+	if iter.closed {
+		return false
+	}
+
+	for len(iter.leaves) > 0 {
+		ks := iter.leaves[0]
+		if iter.i < ks.len() {
+			k := ks.byPosition(iter.i)
+			if iter.stop.Compare(k) < 1 {
+				break
+			}
+
+			iter.i++
+			iter.value = k
+			return true
+		}
+
+		ks.unpin()
+		iter.leaves[0] = nil
+		iter.leaves = iter.leaves[1:]
+		iter.i = 0
+	}
+
+	iter.Close()
+	return false
+}
+
+func (iter *iterator) Value() common.Comparator {
+	return iter.value
+}
+
+func (iter *iterator) Close() {
+	if iter.closed {
+		return
+	}
+
+	for _, ks := range iter.leaves {
+		ks.unpin()
+	}
+	iter.closed = true
+	iter.value = nil
+	iter.leaves = nil
+}
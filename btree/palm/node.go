@@ -19,6 +19,7 @@ package palm
 import (
 	"log"
 	"sort"
+	"sync/atomic"
 
 	"github.com/Workiva/go-datastructures/common"
 )
@@ -82,6 +83,27 @@ func newNodes(size uint64) *nodes {
 
 type keys struct {
 	list common.Comparators
+	pins int32 // Number of iterators reading list, which must not change
+}
+
+func (ks *keys) pin() {
+	atomic.AddInt32(&ks.pins, 1)
+}
+
+func (ks *keys) unpin() {
+	atomic.AddInt32(&ks.pins, -1)
+}
+
+func (ks *keys) pinned() bool {
+	return atomic.LoadInt32(&ks.pins) > 0
+}
+
+// clone returns an unpinned copy of these keys that can be changed
+// while the original is still being read.
+func (ks *keys) clone() *keys {
+	list := make(common.Comparators, len(ks.list), cap(ks.list))
+	copy(list, ks.list)
+	return &keys{list: list}
 }
 
 func (ks *keys) splitAt(i, capacity uint64) (*keys, *keys) {
@@ -33,6 +33,8 @@ const (
 	add
 	remove
 	apply
+	removeRange
+	iterate
 )
 
 const multiThreadAt = 400 // number of keys before we multithread lookups
@@ -96,7 +98,7 @@ func (ptree *ptree) checkAndRun(action action) {
 				ptree.read(action)
 				action.complete()
 				ptree.reset()
-			case add, remove:
+			case add, remove, removeRange, iterate:
 				if len(action.keys()) > multiThreadAt {
 					ptree.operationRunner(interfaces{action}, true)
 				} else {
@@ -148,13 +150,58 @@ func (ptree *ptree) newKeyBundle(key common.Comparator) *keyBundle {
 }
 
 func (ptree *ptree) operationRunner(xns interfaces, threaded bool) {
+	// actions over a range of keys see every write queued before them,
+	// so the batch is mutated in runs that end at each of those actions
+	start := 0
+	for i, ifc := range xns {
+		switch ifc.(action).operation() {
+		case apply, removeRange, iterate:
+			ptree.mutate(xns[start:i], threaded)
+			ptree.runRange(ifc.(action), threaded)
+			start = i + 1
+		}
+	}
+	ptree.mutate(xns[start:], threaded)
+
+	ptree.reset()
+}
+
+// mutate applies the writes and reads of the provided actions, none of
+// which are over a range of keys, as a single batch.
+func (ptree *ptree) mutate(xns interfaces, threaded bool) {
+	if len(xns) == 0 {
+		return
+	}
+
 	writeOperations, deleteOperations, toComplete := ptree.fetchKeys(xns, threaded)
 	ptree.recursiveMutate(writeOperations, deleteOperations, false, threaded)
 	for _, a := range toComplete {
 		a.complete()
 	}
+}
 
-	ptree.reset()
+// runRange runs an action over a range of keys against the tree as it
+// stands.
+func (ptree *ptree) runRange(action action, threaded bool) {
+	switch action.operation() {
+	case apply:
+		q := action.(*applyAction)
+		ptree.apply(getParent(ptree.root, q.start), q)
+	case removeRange:
+		rra := action.(*removeRangeAction)
+		deleteOperations := make(map[*node][]*keyBundle)
+		count := 0
+		ptree.walk(getParent(ptree.root, rra.start), rra.start, rra.stop, func(n *node, k common.Comparator) bool {
+			deleteOperations[n] = append(deleteOperations[n], ptree.newKeyBundle(k))
+			count++
+			return true
+		})
+		ptree.recursiveMutate(map[*node][]*keyBundle{}, deleteOperations, false, threaded || count > multiThreadAt)
+	case iterate:
+		ptree.snapshot(action.(*iterAction))
+	}
+
+	action.complete()
 }
 
 func (ptree *ptree) read(action action) {
@@ -196,7 +243,7 @@ func (ptree *ptree) fetchKeys(xns interfaces, inParallel bool) (map[*node][]*key
 				deleteOperations[n] = append(deleteOperations[n], ptree.newKeyBundle(action.keys()[i]))
 			}
 			toComplete = append(toComplete, action)
-		case get, apply:
+		case get:
 			action.complete()
 		}
 	}
@@ -205,21 +252,49 @@ func (ptree *ptree) fetchKeys(xns interfaces, inParallel bool) (map[*node][]*key
 }
 
 func (ptree *ptree) apply(n *node, aa *applyAction) {
-	i := n.search(aa.start)
-	if i == n.keys.len() { // nothing to apply against
+	ptree.walk(n, aa.start, aa.stop, func(_ *node, k common.Comparator) bool {
+		return aa.fn(k)
+	})
+}
+
+// walk calls fn with every key in [start, stop) and the leaf holding
+// it, beginning with the provided leaf and following right pointers.
+// Walking halts if fn returns false.  Leaves emptied by deletes are
+// skipped over.
+func (ptree *ptree) walk(n *node, start, stop common.Comparator, fn func(*node, common.Comparator) bool) {
+	if n == nil {
 		return
 	}
 
 	var k common.Comparator
-	for n != nil {
+	for i := n.search(start); n != nil; i = 0 {
 		for j := i; j < n.keys.len(); j++ {
 			k = n.keys.byPosition(j)
-			if aa.stop.Compare(k) < 1 || !aa.fn(k) {
+			if stop.Compare(k) < 1 || !fn(n, k) {
 				return
 			}
 		}
 		n = n.right
-		i = 0
+	}
+}
+
+// snapshot pins the keys of every leaf that may hold a key in the
+// iterator's range.  Writes copy pinned keys before changing them, so
+// the iterator reads them as they are now without holding the tree.
+func (ptree *ptree) snapshot(ia *iterAction) {
+	n := getParent(ptree.root, ia.start)
+	if n == nil {
+		return
+	}
+
+	ia.i = n.search(ia.start)
+	for ; n != nil; n = n.right {
+		if n.keys.len() > 0 && ia.stop.Compare(n.keys.byPosition(0)) < 1 {
+			return
+		}
+
+		n.keys.pin()
+		ia.leaves = append(ia.leaves, n.keys)
 	}
 }
 
@@ -353,6 +428,10 @@ func (ptree *ptree) splitNode(n, parent *node, nodes *[]*node, keys *common.Comp
 }
 
 func (ptree *ptree) applyNode(n *node, adds, deletes []*keyBundle) {
+	if n.keys.pinned() {
+		n.keys = n.keys.clone()
+	}
+
 	for _, kb := range deletes {
 		if n.keys.len() == 0 {
 			break
@@ -526,6 +605,30 @@ func (ptree *ptree) Query(start, stop common.Comparator) common.Comparators {
 	return cmps
 }
 
+// DeleteRange will remove every key that falls within the provided
+// start and stop Comparators, ie [start, stop), as a single batch.  The
+// range is read once the operations queued before it have been
+// applied, so it removes keys they add and none added after it.
+func (ptree *ptree) DeleteRange(start, stop common.Comparator) {
+	rra := newRemoveRangeAction(start, stop)
+	ptree.checkAndRun(rra)
+	rra.completer.Wait()
+}
+
+// Iter returns an iterator over the keys that fall within the provided
+// start and stop Comparators, ie [start, stop).  The iterator returns
+// the keys in the range once the operations queued before it have been
+// applied, however the tree changes while it is open.  It does not
+// hold the tree: writes to the range copy the keys of the leaf they
+// change while the iterator has yet to pass it.  Close the iterator if
+// it is not read to the end to stop those copies.
+func (ptree *ptree) Iter(start, stop common.Comparator) Iterator {
+	ia := newIterAction(start, stop)
+	ptree.checkAndRun(ia)
+	ia.completer.Wait()
+	return &iterator{stop: stop, leaves: ia.leaves, i: ia.i}
+}
+
 // Dispose will clean up any resources used by this tree.  This
 // must be called to prevent a memory leak.
 func (ptree *ptree) Dispose() {
@@ -430,6 +430,221 @@ func TestCrossNodeQuery(t *testing.T) {
 	}
 }
 
+func TestQueuedQuery(t *testing.T) {
+	tree := newTree(16, 3)
+	defer tree.Dispose()
+	keys := generateKeys(10)
+	tree.Insert(keys...)
+
+	// run the query the way it is run when queued behind other actions
+	result := make(common.Comparators, 0, 10)
+	aa := newApplyAction(func(cmp common.Comparator) bool {
+		result = append(result, cmp)
+		return true
+	}, mockKey(0), mockKey(10))
+	tree.operationRunner(interfaces{aa}, false)
+	aa.completer.Wait()
+
+	assert.Equal(t, keys, result)
+}
+
+func collect(iter Iterator) common.Comparators {
+	result := make(common.Comparators, 0, 32)
+	for iter.Next() {
+		result = append(result, iter.Value())
+	}
+
+	return result
+}
+
+func TestIter(t *testing.T) {
+	tree := newTree(3, 3)
+	defer tree.Dispose()
+	keys := generateKeys(100)
+	tree.Insert(keys...)
+
+	assert.Equal(t, keys, collect(tree.Iter(mockKey(0), mockKey(100))))
+	assert.Equal(t, keys[10:20], collect(tree.Iter(mockKey(10), mockKey(20))))
+	assert.Len(t, collect(tree.Iter(mockKey(100), mockKey(200))), 0)
+	assert.Len(t, collect(tree.Iter(mockKey(5), mockKey(5))), 0)
+}
+
+func TestIterEmptyTree(t *testing.T) {
+	tree := newTree(3, 3)
+	defer tree.Dispose()
+
+	iter := tree.Iter(mockKey(0), mockKey(10))
+	assert.False(t, iter.Next())
+	assert.Nil(t, iter.Value())
+	assert.False(t, iter.Next())
+}
+
+func TestIterSkipsEmptyLeaves(t *testing.T) {
+	tree := newTree(3, 3)
+	defer tree.Dispose()
+	keys := generateKeys(100)
+	tree.Insert(keys...)
+	tree.Delete(keys[10:50]...)
+
+	expected := append(common.Comparators{}, keys[:10]...)
+	expected = append(expected, keys[50:]...)
+	assert.Equal(t, expected, collect(tree.Iter(mockKey(0), mockKey(100))))
+	assert.Equal(t, keys[50:60], collect(tree.Iter(mockKey(20), mockKey(60))))
+	assert.Equal(t, keys[50:60], tree.Query(mockKey(20), mockKey(60)))
+}
+
+func TestIterClose(t *testing.T) {
+	tree := newTree(3, 3)
+	defer tree.Dispose()
+	keys := generateKeys(10)
+	tree.Insert(keys...)
+
+	iter := tree.Iter(mockKey(0), mockKey(10))
+	assert.True(t, iter.Next())
+	assert.Equal(t, keys[0], iter.Value())
+	iter.Close()
+	iter.Close()
+	assert.False(t, iter.Next())
+	assert.Nil(t, iter.Value())
+
+	// the tree should be released
+	tree.Insert(mockKey(10))
+	assert.Equal(t, uint64(11), tree.Len())
+}
+
+func TestIterDoesNotHoldTree(t *testing.T) {
+	tree := newTree(16, 3)
+	defer tree.Dispose()
+	keys := generateKeys(20)
+	tree.Insert(keys...)
+
+	// an iterator left open does not block other operations
+	iter := tree.Iter(mockKey(0), mockKey(100))
+	assert.True(t, iter.Next())
+	assert.Equal(t, keys[0], iter.Value())
+
+	tree.Insert(mockKey(50))
+	tree.DeleteRange(mockKey(10), mockKey(20))
+	assert.Equal(t, common.Comparators{keys[1]}, tree.Get(keys[1]))
+	assert.Equal(t, append(keys[:10:10], mockKey(50)), tree.Query(mockKey(0), mockKey(100)))
+}
+
+func TestIterIsSnapshot(t *testing.T) {
+	tree := newTree(16, 3)
+	defer tree.Dispose()
+	keys := generateKeys(500)
+	tree.Insert(keys...)
+
+	// writes made while the iterator is open, including by whoever is
+	// iterating, are not seen by it
+	iter := tree.Iter(mockKey(0), mockKey(len(keys)))
+	result := make(common.Comparators, 0, len(keys))
+	for iter.Next() {
+		result = append(result, iter.Value())
+		k := iter.Value().(mockKey)
+		switch {
+		case k == 100:
+			tree.DeleteRange(mockKey(200), mockKey(300))
+		case k%2 == 0:
+			tree.Insert(mockKey(int(k) + 1000))
+		default:
+			tree.Delete(iter.Value())
+		}
+	}
+
+	assert.Equal(t, keys, result)
+	assert.True(t, checkTree(t, tree))
+	assert.Len(t, tree.Query(mockKey(0), mockKey(len(keys))), 200)
+	assert.Len(t, tree.Query(mockKey(1000), mockKey(2000)), 249)
+}
+
+func TestIterConcurrentWrites(t *testing.T) {
+	tree := newTree(16, 8)
+	defer tree.Dispose()
+	keys := generateKeys(2000)
+	tree.Insert(keys...)
+
+	iter := tree.Iter(mockKey(0), mockKey(len(keys)))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		tree.Delete(keys[:1000]...)
+		wg.Done()
+	}()
+	go func() {
+		tree.Insert(generateRandomKeys(1000)...)
+		wg.Done()
+	}()
+
+	assert.Equal(t, keys, collect(iter))
+	wg.Wait()
+}
+
+func TestIterInBatch(t *testing.T) {
+	tree := newTree(16, 3)
+	defer tree.Dispose()
+	keys := generateKeys(20)
+	tree.Insert(keys[:10]...)
+
+	// an iterator sees the writes queued before it in its batch and none
+	// of those queued after it
+	ia := newIterAction(mockKey(0), mockKey(20))
+	before, after := newInsertAction(keys[10:15]), newInsertAction(keys[15:])
+	remove := newRemoveAction(keys[:5])
+	tree.operationRunner(interfaces{before, remove, ia, after}, false)
+	ia.completer.Wait()
+
+	iter := &iterator{stop: ia.stop, leaves: ia.leaves, i: ia.i}
+	assert.Equal(t, keys[5:15], collect(iter))
+	assert.Equal(t, keys[5:], tree.Query(mockKey(0), mockKey(20)))
+}
+
+func TestDeleteRange(t *testing.T) {
+	tree := newTree(3, 3)
+	defer tree.Dispose()
+	keys := generateKeys(100)
+	tree.Insert(keys...)
+
+	tree.DeleteRange(mockKey(10), mockKey(90))
+	assert.Equal(t, uint64(20), tree.Len())
+	expected := append(common.Comparators{}, keys[:10]...)
+	expected = append(expected, keys[90:]...)
+	assert.Equal(t, expected, tree.Query(mockKey(0), mockKey(100)))
+
+	tree.DeleteRange(mockKey(200), mockKey(300))
+	assert.Equal(t, uint64(20), tree.Len())
+
+	tree.DeleteRange(mockKey(0), mockKey(100))
+	assert.Equal(t, uint64(0), tree.Len())
+	assert.Len(t, tree.Query(mockKey(0), mockKey(100)), 0)
+
+	tree.Insert(keys...)
+	assert.Equal(t, keys, tree.Query(mockKey(0), mockKey(100)))
+}
+
+func TestDeleteRangeWithInserts(t *testing.T) {
+	tree := newTree(1024, 16)
+	defer tree.Dispose()
+	keys := generateKeys(1000)
+	tree.Insert(keys[:500]...)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		tree.Insert(keys[500:]...)
+		wg.Done()
+	}()
+	go func() {
+		tree.DeleteRange(mockKey(0), mockKey(500))
+		wg.Done()
+	}()
+	wg.Wait()
+
+	assert.Equal(t, uint64(500), tree.Len())
+	assert.Equal(t, keys[500:], tree.Query(mockKey(0), mockKey(1000)))
+}
+
 func BenchmarkReadAndWrites(b *testing.B) {
 	numItems := 1000
 	keys := make([]common.Comparators, 0, b.N)
@@ -598,3 +813,25 @@ func BenchmarkExecuteQuery(b *testing.B) {
 		tree.Query(mockKey(0), mockKey(numItems))
 	}
 }
+
+func TestDeleteRangeInBatch(t *testing.T) {
+	tree := newTree(16, 3)
+	defer tree.Dispose()
+	keys := generateKeys(30)
+	tree.Insert(keys[:10]...)
+
+	// a range removes the keys added before it in its batch and none of
+	// those added after it
+	before, after := newInsertAction(keys[10:20]), newInsertAction(keys[20:])
+	rra := newRemoveRangeAction(mockKey(5), mockKey(25))
+	tree.operationRunner(interfaces{before, rra, after}, false)
+	before.completer.Wait()
+	rra.completer.Wait()
+	after.completer.Wait()
+
+	expected := append(common.Comparators{}, keys[:5]...)
+	expected = append(expected, keys[20:]...)
+	assert.Equal(t, expected, tree.Query(mockKey(0), mockKey(30)))
+	assert.Equal(t, uint64(15), tree.Len())
+	assert.True(t, checkTree(t, tree))
+}
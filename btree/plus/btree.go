@@ -15,16 +15,18 @@ limitations under the License.
 */
 
 /*
-Package btree/plus implements the ubiquitous B+ tree.  As of this writing,
-the tree is not quite finished.  The delete-node merge functionaly needs
-to be added.  There are also some performance improvements that can be
-made, with some possible concurrency mechanisms.
+Package btree/plus implements the ubiquitous B+ tree.  Deletes rebalance
+the tree by borrowing from or merging with sibling nodes and leaves are
+linked in both directions for forward and reverse iteration.  There are
+some performance improvements that can be made, with some possible
+concurrency mechanisms.
 
 This is a mutable b-tree so it is not threadsafe.
 
 Performance characteristics:
 Space: O(n)
 Insert: O(log n)
+Delete: O(log n)
 Search: O(log n)
 
 BenchmarkIteration-8	   	10000	   		 	109347 ns/op
@@ -92,6 +94,73 @@ func (tree *btree) Iter(key Key) Iterator {
 	return tree.root.find(key)
 }
 
+// ReverseIter returns an iterator that can be used to traverse the b-tree
+// in descending order starting from the specified key or its predecessor.
+func (tree *btree) ReverseIter(key Key) Iterator {
+	if tree.root == nil {
+		return nilIterator()
+	}
+
+	iter := tree.root.find(key)
+	iter.reverse = true
+	// the iterator sits just before the first key >= key, move the
+	// gap past key if it exists so it is included in the iteration.
+	if iter.index != iteratorExhausted {
+		peek := *iter
+		if peek.forward() && peek.Value().Compare(key) == 0 {
+			iter.node, iter.index = peek.node, peek.index
+		}
+	}
+
+	return iter
+}
+
+// Range returns an iterator that traverses, in ascending order, the keys
+// in the b-tree that fall within [start, stop).
+func (tree *btree) Range(start, stop Key) Iterator {
+	if tree.root == nil {
+		return nilIterator()
+	}
+
+	iter := tree.root.find(start)
+	iter.start, iter.stop = start, stop
+	return iter
+}
+
+// ReverseRange returns an iterator that traverses, in descending order,
+// the keys in the b-tree that fall within [start, stop).
+func (tree *btree) ReverseRange(start, stop Key) Iterator {
+	if tree.root == nil {
+		return nilIterator()
+	}
+
+	iter := tree.root.find(stop)
+	iter.start, iter.stop = start, stop
+	iter.reverse = true
+	return iter
+}
+
+func (tree *btree) delete(key Key) {
+	if tree.root == nil || !tree.root.delete(tree, key) {
+		return
+	}
+
+	tree.number--
+	// collapse the root if it has lost all of its keys
+	if in, ok := tree.root.(*inode); ok && len(in.keys) == 0 {
+		tree.root = in.nodes[0]
+	}
+}
+
+// Delete will remove the provided keys from the btree.  Keys that are
+// not found are ignored.  This is an O(m*log n) operation where m is the
+// number of keys to be deleted and n is the number of items in the tree.
+func (tree *btree) Delete(keys ...Key) {
+	for _, key := range keys {
+		tree.delete(key)
+	}
+}
+
 func (tree *btree) get(key Key) Key {
 	iter := tree.root.find(key)
 	if !iter.Next() {
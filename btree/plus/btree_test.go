@@ -17,6 +17,7 @@ limitations under the License.
 package plus
 
 import (
+	"math/rand"
 	"sync"
 	"testing"
 
@@ -307,6 +308,204 @@ func TestGetExactMatchesOnly(t *testing.T) {
 	assert.Equal(t, Keys{nil}, tree.Get(newMockKey(3)))
 }
 
+// verify walks the tree checking node occupancy and key ordering
+// and that the leaves are linked correctly in both directions.
+func (tree *btree) verify(t *testing.T) {
+	var leaves []*lnode
+	var walk func(n node, depth int, root bool) int
+	walk = func(n node, depth int, root bool) int {
+		if !root {
+			assert.False(t, n.underflows(tree.nodeSize))
+		}
+		assert.False(t, n.needsSplit(tree.nodeSize))
+
+		switch n := n.(type) {
+		case *lnode:
+			leaves = append(leaves, n)
+			return depth
+		case *inode:
+			assert.Len(t, n.nodes, len(n.keys)+1)
+			d := -1
+			for _, child := range n.nodes {
+				cd := walk(child, depth+1, false)
+				if d >= 0 {
+					assert.Equal(t, d, cd, `leaves at different depths`)
+				}
+				d = cd
+			}
+			return d
+		}
+		return depth
+	}
+	walk(tree.root, 0, true)
+
+	var all keys
+	for i, leaf := range leaves {
+		if i > 0 {
+			assert.True(t, leaves[i-1].pointer == leaf)
+			assert.True(t, leaf.prev == leaves[i-1])
+		}
+		all = append(all, leaf.keys...)
+	}
+	if len(leaves) > 0 {
+		assert.Nil(t, leaves[0].prev)
+		assert.Nil(t, leaves[len(leaves)-1].pointer)
+	}
+
+	assert.Len(t, all, int(tree.number))
+	for i := 1; i < len(all); i++ {
+		assert.Equal(t, 1, all[i-1].Compare(all[i]))
+	}
+}
+
+func reversed(ks keys) keys {
+	cp := make(keys, len(ks))
+	copy(cp, ks)
+	cp.reverse()
+	return cp
+}
+
+func TestTreeDelete(t *testing.T) {
+	tree := newBTree(3)
+	keys := constructMockKeys(100)
+	tree.Insert(keys...)
+
+	tree.Delete(keys[:50]...)
+	tree.verify(t)
+	assert.Equal(t, uint64(50), tree.Len())
+	assert.Equal(t, keys[50:], tree.Iter(newMockKey(0)).(*iterator).exhaust())
+	assert.Equal(t, Keys{nil}, tree.Get(keys[10]))
+
+	tree.Delete(newMockKey(200))
+	assert.Equal(t, uint64(50), tree.Len())
+
+	tree.Delete(keys[50:]...)
+	tree.verify(t)
+	assert.Equal(t, uint64(0), tree.Len())
+	assert.IsType(t, &lnode{}, tree.root)
+	assert.False(t, tree.Iter(newMockKey(0)).Next())
+}
+
+func TestTreeDeleteReverseOrder(t *testing.T) {
+	tree := newBTree(4)
+	keys := constructMockKeys(100)
+	tree.Insert(keys...)
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		tree.Delete(keys[i])
+		tree.verify(t)
+	}
+	assert.Equal(t, uint64(0), tree.Len())
+}
+
+func TestTreeDeleteRandomOrder(t *testing.T) {
+	for _, size := range []uint64{3, 4, 5, 16} {
+		tree := newBTree(size)
+		keys := constructMockKeys(500)
+		tree.Insert(keys...)
+
+		remaining := make(map[int]struct{}, len(keys))
+		for _, k := range keys {
+			remaining[k.(*mockKey).value] = struct{}{}
+		}
+
+		for _, i := range rand.Perm(len(keys)) {
+			tree.Delete(keys[i])
+			delete(remaining, i)
+			if len(remaining)%50 == 0 {
+				tree.verify(t)
+				result := tree.Iter(newMockKey(0)).(*iterator).exhaust()
+				assert.Len(t, result, len(remaining))
+				for _, k := range result {
+					assert.Contains(t, remaining, k.(*mockKey).value)
+				}
+			}
+		}
+	}
+}
+
+func TestTreeDeleteAndReinsert(t *testing.T) {
+	tree := newBTree(4)
+	keys := constructRandomMockKeys(200)
+	tree.Insert(keys...)
+	tree.Delete(keys[:100]...)
+	tree.Insert(keys[:50]...)
+	tree.verify(t)
+
+	assert.Equal(t, uint64(150), tree.Len())
+	for _, key := range keys[:50] {
+		assert.Equal(t, Keys{key}, tree.Get(key))
+	}
+}
+
+func TestTreeRange(t *testing.T) {
+	tree := newBTree(4)
+	keys := constructMockKeys(100)
+	tree.Insert(keys...)
+
+	iter := tree.Range(newMockKey(10), newMockKey(20))
+	assert.Equal(t, keys[10:20], iter.(*iterator).exhaust())
+
+	iter = tree.Range(newMockKey(95), newMockKey(200))
+	assert.Equal(t, keys[95:], iter.(*iterator).exhaust())
+
+	iter = tree.Range(newMockKey(20), newMockKey(20))
+	assert.False(t, iter.Next())
+
+	iter = tree.Range(newMockKey(10), newMockKey(20))
+	assert.False(t, iter.Prev())
+	assert.Nil(t, iter.Value())
+}
+
+func TestTreeReverseRange(t *testing.T) {
+	tree := newBTree(4)
+	keys := constructMockKeys(100)
+	tree.Insert(keys...)
+
+	expected := reversed(keys[10:20])
+	assert.Equal(t, expected, tree.ReverseRange(newMockKey(10), newMockKey(20)).(*iterator).exhaust())
+
+	expected = reversed(keys[95:])
+	assert.Equal(t, expected, tree.ReverseRange(newMockKey(95), newMockKey(200)).(*iterator).exhaust())
+}
+
+func TestTreeReverseIter(t *testing.T) {
+	tree := newBTree(3)
+	keys := constructMockKeys(50)
+	tree.Insert(keys...)
+
+	expected := reversed(keys[:26])
+	assert.Equal(t, expected, tree.ReverseIter(newMockKey(25)).(*iterator).exhaust())
+
+	expected = reversed(keys)
+	assert.Equal(t, expected, tree.ReverseIter(newMockKey(100)).(*iterator).exhaust())
+
+	tree.Delete(newMockKey(25))
+	iter := tree.ReverseIter(newMockKey(25))
+	assert.True(t, iter.Next())
+	assert.Equal(t, newMockKey(24), iter.Value())
+}
+
+func TestIteratorChangeDirection(t *testing.T) {
+	tree := newBTree(3)
+	keys := constructMockKeys(20)
+	tree.Insert(keys...)
+
+	iter := tree.Iter(newMockKey(10))
+	assert.True(t, iter.Prev())
+	assert.Equal(t, newMockKey(9), iter.Value())
+	assert.True(t, iter.Next())
+	assert.Equal(t, newMockKey(10), iter.Value())
+	assert.True(t, iter.Next())
+	assert.Equal(t, newMockKey(11), iter.Value())
+	assert.True(t, iter.Prev())
+	assert.Equal(t, newMockKey(10), iter.Value())
+
+	iter = tree.Iter(newMockKey(0))
+	assert.False(t, iter.Prev())
+	assert.False(t, iter.Next())
+}
+
 func BenchmarkIteration(b *testing.B) {
 	numItems := 1000
 	ary := uint64(16)
@@ -28,11 +28,17 @@ type Key interface {
 }
 
 // Iterator will be called with matching keys until either false is
-// returned or we run out of keys to iterate.
+// returned or we run out of keys to iterate.  An iterator is exhausted
+// once either movement method returns false and cannot be moved again.
 type Iterator interface {
 	// Next will move the iterator to the next position and return
 	// a bool indicating if there is a value.
 	Next() bool
+	// Prev will move the iterator to the previous position and return
+	// a bool indicating if there is a value.  Calling Prev on an
+	// iterator that has never been moved returns the key immediately
+	// preceding the iterator's starting position.
+	Prev() bool
 	// Value returns a Key at the associated iterator position.  Returns
 	// nil if the iterator is exhausted or has never been nexted.
 	Value() Key
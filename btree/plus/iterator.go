@@ -18,31 +18,103 @@ package plus
 
 const iteratorExhausted = -2
 
+// iterator walks the linked leaves of the tree.  Until the iterator is
+// first moved, it sits in the gap between index and index+1 so that
+// Next returns the item at index+1 and Prev returns the item at index.
+// Once moved, index refers to the current item.
 type iterator struct {
 	node  *lnode
 	index int
+	moved bool
+	// reverse swaps the direction of Next and Prev.
+	reverse bool
+	// start and stop bound the iterator to [start, stop) if provided.
+	start, stop Key
 }
 
+// Next will move the iterator to the next position and return
+// a bool indicating if there is a value.  If this is a reverse
+// iterator, the next position is the previous key in the tree.
 func (iter *iterator) Next() bool {
+	if iter.reverse {
+		return iter.backward()
+	}
+
+	return iter.forward()
+}
+
+// Prev will move the iterator to the previous position and return
+// a bool indicating if there is a value.  If this is a reverse
+// iterator, the previous position is the next key in the tree.
+func (iter *iterator) Prev() bool {
+	if iter.reverse {
+		return iter.forward()
+	}
+
+	return iter.backward()
+}
+
+func (iter *iterator) forward() bool {
 	if iter.index == iteratorExhausted {
 		return false
 	}
 
+	iter.moved = true
 	iter.index++
-	if iter.index >= len(iter.node.keys) {
+	for iter.index >= len(iter.node.keys) {
 		iter.node = iter.node.pointer
 		if iter.node == nil {
-			iter.index = iteratorExhausted
-			return false
+			return iter.exhausted()
 		}
 		iter.index = 0
 	}
 
+	return iter.inBounds()
+}
+
+func (iter *iterator) backward() bool {
+	if iter.index == iteratorExhausted {
+		return false
+	}
+
+	if iter.moved {
+		iter.index--
+	}
+	iter.moved = true
+	for iter.index < 0 {
+		iter.node = iter.node.prev
+		if iter.node == nil {
+			return iter.exhausted()
+		}
+		iter.index = len(iter.node.keys) - 1
+	}
+
+	return iter.inBounds()
+}
+
+// inBounds returns a bool indicating if the current key falls within
+// the bounds of this iterator, exhausting the iterator if it does not.
+func (iter *iterator) inBounds() bool {
+	key := iter.node.keys[iter.index]
+	if iter.stop != nil && key.Compare(iter.stop) <= 0 {
+		return iter.exhausted()
+	}
+
+	if iter.start != nil && key.Compare(iter.start) > 0 {
+		return iter.exhausted()
+	}
+
 	return true
 }
 
+func (iter *iterator) exhausted() bool {
+	iter.node = nil
+	iter.index = iteratorExhausted
+	return false
+}
+
 func (iter *iterator) Value() Key {
-	if iter.index == iteratorExhausted ||
+	if iter.index == iteratorExhausted || !iter.moved ||
 		iter.index < 0 || iter.index >= len(iter.node.keys) {
 
 		return nil
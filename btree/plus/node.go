@@ -32,13 +32,6 @@ func split(tree *btree, parent, child node) node {
 
 	p := parent.(*inode)
 	i := p.search(key)
-	// we want to ensure if the children are leaves we set
-	// the left node's left sibling to point to left
-	if cr, ok := left.(*lnode); ok {
-		if i > 0 {
-			p.nodes[i-1].(*lnode).pointer = cr
-		}
-	}
 	p.keys.insertAt(i, key)
 	p.nodes[i] = left
 	p.nodes.insertAt(i+1, right)
@@ -46,8 +39,23 @@ func split(tree *btree, parent, child node) node {
 	return parent
 }
 
+// minKeys returns the minimum number of keys a non-root node must hold
+// before it is rebalanced.  Nodes split when they reach nodeSize keys so
+// two minimally occupied siblings can always be merged without splitting.
+func minKeys(nodeSize uint64) int {
+	return int(nodeSize-1) / 2
+}
+
 type node interface {
 	insert(tree *btree, key Key) bool
+	// delete removes the provided key from this node or its children
+	// and returns a bool indicating if a key was removed.
+	delete(tree *btree, key Key) bool
+	// underflows returns a bool indicating if this node has too few
+	// keys and must be rebalanced with a sibling.
+	underflows(nodeSize uint64) bool
+	// numKeys returns the number of keys held directly by this node.
+	numKeys() int
 	needsSplit(nodeSize uint64) bool
 	// key is the median key while left and right nodes
 	// represent the left and right nodes respectively
@@ -69,6 +77,24 @@ func (nodes *nodes) insertAt(i int, node node) {
 	(*nodes)[i] = node
 }
 
+func (nodes *nodes) deleteAt(i int) {
+	copy((*nodes)[i:], (*nodes)[i+1:])
+	(*nodes)[len(*nodes)-1] = nil
+	*nodes = (*nodes)[:len(*nodes)-1]
+}
+
+func (nodes *nodes) pop() node {
+	n := (*nodes)[len(*nodes)-1]
+	nodes.deleteAt(len(*nodes) - 1)
+	return n
+}
+
+func (nodes *nodes) popFirst() node {
+	n := (*nodes)[0]
+	nodes.deleteAt(0)
+	return n
+}
+
 func (ns nodes) splitAt(i int) (nodes, nodes) {
 	left := make(nodes, i, cap(ns))
 	right := make(nodes, len(ns)-i, cap(ns))
@@ -102,20 +128,7 @@ func (node *inode) find(key Key) *iterator {
 }
 
 func (n *inode) insert(tree *btree, key Key) bool {
-	i := n.search(key)
-	var child node
-	if i == len(n.keys) { // we want the last child node in this case
-		child = n.nodes[len(n.nodes)-1]
-	} else {
-		match := n.keys[i]
-		switch match.Compare(key) {
-		case 1, 0:
-			child = n.nodes[i+1]
-		default:
-			child = n.nodes[i]
-		}
-	}
-
+	child := n.nodes[n.childIndex(key)]
 	result := child.insert(tree, key)
 	if !result { // no change of state occurred
 		return result
@@ -128,6 +141,123 @@ func (n *inode) insert(tree *btree, key Key) bool {
 	return result
 }
 
+// childIndex returns the index of the child node that would contain
+// the provided key.
+func (n *inode) childIndex(key Key) int {
+	i := n.search(key)
+	if i == len(n.keys) {
+		return len(n.nodes) - 1
+	}
+
+	switch n.keys[i].Compare(key) {
+	case 1, 0:
+		return i + 1
+	default:
+		return i
+	}
+}
+
+func (n *inode) delete(tree *btree, key Key) bool {
+	i := n.childIndex(key)
+	child := n.nodes[i]
+	if !child.delete(tree, key) {
+		return false
+	}
+
+	if child.underflows(tree.nodeSize) {
+		n.rebalance(tree, i)
+	}
+
+	return true
+}
+
+// rebalance restores the minimum occupancy of the child at index i by
+// borrowing a key from a sibling or, if neither sibling can spare a key,
+// merging the child with a sibling.
+func (n *inode) rebalance(tree *btree, i int) {
+	min := minKeys(tree.nodeSize)
+	if i > 0 && n.nodes[i-1].numKeys() > min {
+		n.borrowFromLeft(i)
+		return
+	}
+
+	if i < len(n.nodes)-1 && n.nodes[i+1].numKeys() > min {
+		n.borrowFromRight(i)
+		return
+	}
+
+	if i > 0 {
+		n.merge(i - 1)
+		return
+	}
+
+	if i < len(n.nodes)-1 {
+		n.merge(i)
+	}
+}
+
+// borrowFromLeft moves the last key of the left sibling of the child at
+// index i into that child.
+func (n *inode) borrowFromLeft(i int) {
+	switch child := n.nodes[i].(type) {
+	case *lnode:
+		left := n.nodes[i-1].(*lnode)
+		child.keys.insertAt(0, left.keys.pop())
+		n.keys[i-1] = child.keys[0]
+	case *inode:
+		left := n.nodes[i-1].(*inode)
+		child.keys.insertAt(0, n.keys[i-1])
+		child.nodes.insertAt(0, left.nodes.pop())
+		n.keys[i-1] = left.keys.pop()
+	}
+}
+
+// borrowFromRight moves the first key of the right sibling of the child
+// at index i into that child.
+func (n *inode) borrowFromRight(i int) {
+	switch child := n.nodes[i].(type) {
+	case *lnode:
+		right := n.nodes[i+1].(*lnode)
+		child.keys = append(child.keys, right.keys.popFirst())
+		n.keys[i] = right.keys[0]
+	case *inode:
+		right := n.nodes[i+1].(*inode)
+		child.keys = append(child.keys, n.keys[i])
+		child.nodes = append(child.nodes, right.nodes.popFirst())
+		n.keys[i] = right.keys.popFirst()
+	}
+}
+
+// merge combines the children at index i and i+1 into the child at
+// index i, removing the separating key from this node.
+func (n *inode) merge(i int) {
+	switch left := n.nodes[i].(type) {
+	case *lnode:
+		right := n.nodes[i+1].(*lnode)
+		left.keys = append(left.keys, right.keys...)
+		left.pointer = right.pointer
+		if right.pointer != nil {
+			right.pointer.prev = left
+		}
+	case *inode:
+		right := n.nodes[i+1].(*inode)
+		left.keys = append(left.keys, n.keys[i])
+		left.keys = append(left.keys, right.keys...)
+		left.nodes = append(left.nodes, right.nodes...)
+	}
+
+	n.keys.deleteAt(i)
+	n.nodes.deleteAt(i + 1)
+}
+
+func (n *inode) underflows(nodeSize uint64) bool {
+	return len(n.keys) < minKeys(nodeSize)
+}
+
+func (n *inode) numKeys() int {
+	return len(n.keys)
+}
+
 func (n *inode) needsSplit(nodeSize uint64) bool {
 	return uint64(len(n.keys)) >= nodeSize
 }
@@ -162,9 +292,11 @@ func newInternalNode(size uint64) *inode {
 }
 
 type lnode struct {
-	// points to the left leaf node is there is one
+	// points to the right leaf node if there is one
 	pointer *lnode
-	keys    keys
+	// points to the left leaf node if there is one
+	prev *lnode
+	keys keys
 }
 
 func (node *lnode) search(key Key) int {
@@ -195,17 +327,8 @@ func (lnode *lnode) insert(tree *btree, key Key) bool {
 
 func (node *lnode) find(key Key) *iterator {
 	i := node.search(key)
-	if i == len(node.keys) {
-		if node.pointer == nil {
-			return nilIterator()
-		}
-
-		return &iterator{
-			node:  node.pointer,
-			index: -1,
-		}
-	}
-
+	// the iterator sits in the gap just before the first key >= key,
+	// which may be past the end of this node.
 	iter := &iterator{
 		node:  node,
 		index: i - 1,
@@ -232,10 +355,33 @@ func (node *lnode) split() (Key, node, node) {
 	otherNode := &lnode{
 		keys:    otherKeys,
 		pointer: node,
+		prev:    node.prev,
+	}
+	if node.prev != nil {
+		node.prev.pointer = otherNode
 	}
+	node.prev = otherNode
 	return key, otherNode, node
 }
 
+func (node *lnode) delete(tree *btree, key Key) bool {
+	i := node.search(key)
+	if i == len(node.keys) || node.keys[i].Compare(key) != 0 {
+		return false
+	}
+
+	node.keys.deleteAt(i)
+	return true
+}
+
+func (node *lnode) underflows(nodeSize uint64) bool {
+	return len(node.keys) < minKeys(nodeSize)
+}
+
+func (node *lnode) numKeys() int {
+	return len(node.keys)
+}
+
 func (lnode *lnode) needsSplit(nodeSize uint64) bool {
 	return uint64(len(lnode.keys)) >= nodeSize
 }
@@ -263,6 +409,24 @@ func (keys *keys) insertAt(i int, key Key) {
 	(*keys)[i] = key
 }
 
+func (keys *keys) deleteAt(i int) {
+	copy((*keys)[i:], (*keys)[i+1:])
+	(*keys)[len(*keys)-1] = nil
+	*keys = (*keys)[:len(*keys)-1]
+}
+
+func (keys *keys) pop() Key {
+	key := (*keys)[len(*keys)-1]
+	keys.deleteAt(len(*keys) - 1)
+	return key
+}
+
+func (keys *keys) popFirst() Key {
+	key := (*keys)[0]
+	keys.deleteAt(0)
+	return key
+}
+
 func (keys keys) reverse() {
 	for i := 0; i < len(keys)/2; i++ {
 		keys[i], keys[len(keys)-i-1] = keys[len(keys)-i-1], keys[i]
@@ -0,0 +1,133 @@
+package cache
+
+import "container/list"
+
+// arc is an adaptive replacement cache policy, as described by Megiddo and
+// Modha, measured in bytes rather than items.  Items in the cache are split
+// between recent, holding items used once, and frequent, holding items used
+// more than once.  Keys recently evicted from either are remembered in the
+// ghost lists, and a request for one of those keys shifts the target size of
+// recent towards the list that would have kept it.
+type arc struct {
+	capacity       uint64
+	target         uint64                   // Target size of recent
+	recent         *sizedList               // Items used once
+	frequent       *sizedList               // Items used more than once
+	recentGhosts   *sizedList               // Keys evicted from recent
+	frequentGhosts *sizedList               // Keys evicted from frequent
+	entries        map[string]*list.Element // Map from keys to their entries
+}
+
+func newARC(capacity uint64) *arc {
+	return &arc{
+		capacity:       capacity,
+		recent:         newSizedList(),
+		frequent:       newSizedList(),
+		recentGhosts:   newSizedList(),
+		frequentGhosts: newSizedList(),
+		entries:        map[string]*list.Element{},
+	}
+}
+
+func (a *arc) add(key string, size uint64) {
+	element, ok := a.entries[key]
+	if !ok {
+		a.entries[key] = a.recent.pushFront(&sizedKey{key: key, size: size})
+		a.trimGhosts()
+		return
+	}
+
+	// ghosts of empty items have no size, so either ghost list may be empty
+	// by size while holding keys
+	e := element.Value.(*sizedKey)
+	switch e.list {
+	case a.recentGhosts:
+		// recent was too small to keep this key
+		delta := size
+		if a.recentGhosts.size > 0 && a.recentGhosts.size < a.frequentGhosts.size {
+			delta = size * a.frequentGhosts.size / a.recentGhosts.size
+		}
+		a.target = min(a.capacity, a.target+delta)
+	case a.frequentGhosts:
+		// frequent was too small to keep this key
+		delta := size
+		if a.frequentGhosts.size > 0 && a.frequentGhosts.size < a.recentGhosts.size {
+			delta = size * a.recentGhosts.size / a.frequentGhosts.size
+		}
+		a.target -= min(a.target, delta)
+	}
+
+	e.list.remove(element)
+	e.size = size
+	a.entries[key] = a.frequent.pushFront(e)
+	a.trimGhosts()
+}
+
+func (a *arc) access(key string) {
+	element, ok := a.entries[key]
+	if !ok {
+		return
+	}
+
+	e := element.Value.(*sizedKey)
+	if e.list == a.recent || e.list == a.frequent {
+		e.list.remove(element)
+		a.entries[key] = a.frequent.pushFront(e)
+	}
+}
+
+// Keys are only remembered once they are evicted, so a ghost being requested
+// counts when it is added again.
+func (a *arc) miss(string) {}
+
+func (a *arc) remove(key string) {
+	if element, ok := a.entries[key]; ok {
+		element.Value.(*sizedKey).list.remove(element)
+		delete(a.entries, key)
+	}
+}
+
+func (a *arc) victim(key string, size uint64) (string, bool) {
+	// recent gives way if it is over its target or, when the new key was
+	// evicted from frequent, at it
+	over := a.recent.size > a.target
+	if element, ok := a.entries[key]; ok && element.Value.(*sizedKey).list == a.frequentGhosts {
+		over = a.recent.size >= a.target
+	}
+
+	from, to := a.frequent, a.frequentGhosts
+	if a.recent.keys.Len() > 0 && (over || a.frequent.keys.Len() == 0) {
+		from, to = a.recent, a.recentGhosts
+	}
+
+	back := from.keys.Back()
+	if back == nil {
+		return "", false
+	}
+
+	// ghosts are trimmed once the new key is added so that it is still
+	// remembered if it is one of them
+	e := from.remove(back)
+	a.entries[e.key] = to.pushFront(e)
+	return e.key, true
+}
+
+// Forget the oldest ghosts until recent and its ghosts fit in the capacity
+// and all lists together fit in twice the capacity.
+func (a *arc) trimGhosts() {
+	for a.recent.size+a.recentGhosts.size > a.capacity && a.recentGhosts.keys.Len() > 0 {
+		a.forget(a.recentGhosts)
+	}
+
+	total := a.recent.size + a.frequent.size + a.recentGhosts.size + a.frequentGhosts.size
+	for total > 2*a.capacity && a.frequentGhosts.keys.Len() > 0 {
+		total -= a.forget(a.frequentGhosts)
+	}
+}
+
+// Forget the oldest ghost on the given list and return its size.
+func (a *arc) forget(ghosts *sizedList) uint64 {
+	e := ghosts.remove(ghosts.keys.Back())
+	delete(a.entries, e.key)
+	return e.size
+}
@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"container/heap"
 	"container/list"
 	"sync"
+	"time"
 )
 
 // Cache is a bounded-size in-memory cache of sized items with a configurable eviction policy
@@ -11,14 +13,32 @@ type Cache interface {
 	// If an item for a particular key is not found, its position in the result will be nil.
 	Get(keys ...string) []Item
 
-	// Put adds an item to the cache.
+	// Put adds an item to the cache that expires after the default TTL.
 	Put(key string, item Item)
 
+	// PutWithTTL adds an item to the cache that expires after the given TTL.
+	// A non-positive TTL means the item never expires.
+	PutWithTTL(key string, item Item, ttl time.Duration)
+
+	// GetOrLoad retrieves an item from the cache by key, calling loader to
+	// load and add it if it is not found.  Concurrent calls for the same key
+	// share a single call to a loader.  An error from the loader is returned
+	// and nothing is added.
+	GetOrLoad(key string, loader Loader) (Item, error)
+
 	// Remove clears items with the given keys from the cache
 	Remove(keys ...string)
 
 	// Size returns the size of all items currently in the cache.
+	// Expired items count until they are removed.
 	Size() uint64
+
+	// Stats returns counts describing the cache's use and contents.
+	Stats() Stats
+
+	// Dispose stops removing expired items in the background.  The cache
+	// remains usable and expired items are still never returned.
+	Dispose()
 }
 
 // Item is an item in a cache
@@ -27,10 +47,14 @@ type Item interface {
 	Size() uint64
 }
 
-// A tuple tracking a cached item and a reference to its node in the eviction list
+// A tuple tracking a cached item, a reference to its node in the eviction list
+// and when it expires
 type cached struct {
 	item    Item
 	element *list.Element
+	key     string
+	expires time.Time // Zero if the item never expires
+	index   int       // Index in the expiry heap, -1 if the item never expires
 }
 
 // Sets the provided list element on the cached item if it is not nil
@@ -49,6 +73,18 @@ type cache struct {
 	keyList      *list.List                     // List of cached items in order of increasing evictability
 	recordAdd    func(key string) *list.Element // Function called to indicate that an item with the given key was added
 	recordAccess func(key string) *list.Element // Function called to indicate that an item with the given key was accessed
+	policy       policy                         // Tracks keys for policies that need more than the eviction list
+	ttl          time.Duration                  // Default time to live, zero if items never expire
+	interval     time.Duration                  // Interval between removals of expired items, zero if never
+	now          func() time.Time               // Clock used to expire items
+	expiries     expiryHeap                     // Items that expire, soonest first
+	loads        map[string]*load               // Loads in progress by key
+	done         chan struct{}                  // Closed to stop removing expired items
+	disposeOnce  sync.Once
+	onEvict      func(key string, item Item, reason EvictionReason) // Function called with items that left the cache
+	evictions    []eviction                                         // Items that left the cache since it was locked
+	stats        Stats                                              // Counts of hits, misses and evictions
+	budget       *budget                                            // Capacity shared with the other shards of a sharded cache, nil if not sharded
 }
 
 // CacheOption configures a cache.
@@ -62,6 +98,17 @@ const (
 	LeastRecentlyAdded Policy = iota
 	// LeastRecentlyUsed indicates a least-recently-used eviction policy.
 	LeastRecentlyUsed
+	// LeastFrequentlyUsed indicates a least-frequently-used eviction policy.
+	// Items used equally often are evicted in least-recently-used order.
+	LeastFrequentlyUsed
+	// AdaptiveReplacement indicates an adaptive replacement cache (ARC)
+	// eviction policy, which balances recency against frequency based on
+	// recently evicted keys that are requested again.
+	AdaptiveReplacement
+	// WindowTinyLFU indicates a W-TinyLFU eviction policy.  New items enter
+	// a small LRU window and only displace items in the rest of the cache if
+	// they have been requested more often, which makes it resistant to scans.
+	WindowTinyLFU
 )
 
 // EvictionPolicy sets the eviction policy to be used to make room for new items.
@@ -72,21 +119,73 @@ func EvictionPolicy(policy Policy) CacheOption {
 		case LeastRecentlyAdded:
 			c.recordAccess = c.noop
 			c.recordAdd = c.record
+			c.policy = nil
 		case LeastRecentlyUsed:
 			c.recordAccess = c.record
 			c.recordAdd = c.noop
+			c.policy = nil
+		case LeastFrequentlyUsed:
+			c.recordAccess = c.noop
+			c.recordAdd = c.noop
+			c.policy = newLFU()
+		case AdaptiveReplacement:
+			c.recordAccess = c.noop
+			c.recordAdd = c.noop
+			c.policy = newARC(c.cap)
+		case WindowTinyLFU:
+			c.recordAccess = c.noop
+			c.recordAdd = c.noop
+			c.policy = newTinyLFU(c.cap)
 		}
 	}
 }
 
+// DefaultTTL sets how long items added with Put live before they expire.
+// By default they never expire.
+func DefaultTTL(ttl time.Duration) CacheOption {
+	return func(c *cache) {
+		c.ttl = ttl
+	}
+}
+
+// ExpiryInterval sets how often expired items are removed in the background
+// until the cache is disposed.  By default they are only removed once they
+// are requested or their room is needed.
+func ExpiryInterval(interval time.Duration) CacheOption {
+	return func(c *cache) {
+		c.interval = interval
+	}
+}
+
+// Clock sets the function used to tell the time when expiring items.
+// By default it is time.Now.
+func Clock(now func() time.Time) CacheOption {
+	return func(c *cache) {
+		c.now = now
+	}
+}
+
 // New returns a cache with the requested options configured.
 // The cache consumes memory bounded by a fixed capacity,
 // plus tracking overhead linear in the number of items.
 func New(capacity uint64, options ...CacheOption) Cache {
+	c := newCache(capacity, options...)
+	if c.interval > 0 {
+		go expireEvery(c.interval, c.done, c)
+	}
+	return c
+}
+
+// Return a cache with the requested options configured that does not yet
+// remove expired items in the background.
+func newCache(capacity uint64, options ...CacheOption) *cache {
 	c := &cache{
 		cap:     capacity,
 		keyList: list.New(),
 		items:   map[string]*cached{},
+		now:     time.Now,
+		loads:   map[string]*load{},
+		done:    make(chan struct{}),
 	}
 	// Default LRU eviction policy
 	EvictionPolicy(LeastRecentlyUsed)(c)
@@ -100,76 +199,175 @@ func New(capacity uint64, options ...CacheOption) Cache {
 
 func (c *cache) Get(keys ...string) []Item {
 	c.Lock()
-	defer c.Unlock()
+	defer c.unlock()
 
+	now := c.now()
 	items := make([]Item, len(keys))
 	for i, key := range keys {
-		cached := c.items[key]
-		if cached == nil {
-			items[i] = nil
-		} else {
-			c.recordAccess(key)
-			items[i] = cached.item
-		}
+		items[i] = c.get(key, now)
 	}
 
 	return items
 }
 
 func (c *cache) Put(key string, item Item) {
+	c.PutWithTTL(key, item, c.ttl)
+}
+
+func (c *cache) PutWithTTL(key string, item Item, ttl time.Duration) {
+	c.Lock()
+	defer c.unlock()
+
+	c.invalidateLoad(key)
+	c.put(key, item, ttl)
+}
+
+func (c *cache) Remove(keys ...string) {
+	c.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.invalidateLoad(key)
+		c.remove(key, Removed)
+	}
+}
+
+func (c *cache) Size() uint64 {
 	c.Lock()
 	defer c.Unlock()
 
+	return c.size
+}
+
+func (c *cache) Dispose() {
+	c.disposeOnce.Do(func() {
+		close(c.done)
+	})
+}
+
+// Retrieve the item with the given key, nil if it is not found or has
+// expired by the given time.
+// The caller should hold the cache lock.
+func (c *cache) get(key string, now time.Time) Item {
+	cached := c.items[key]
+	if cached != nil && cached.expiredAt(now) {
+		c.remove(key, Expired)
+		cached = nil
+	}
+	if cached == nil {
+		c.stats.Misses++
+		if c.policy != nil {
+			c.policy.miss(key)
+		}
+		return nil
+	}
+
+	c.stats.Hits++
+	c.recordAccess(key)
+	if c.policy != nil {
+		c.policy.access(key)
+	}
+	return cached.item
+}
+
+// Add an item that expires after the given TTL, replacing the item currently
+// with the given key.
+// The caller should hold the cache lock.
+func (c *cache) put(key string, item Item, ttl time.Duration) {
 	// Remove the item currently with this key (if any)
-	c.remove(key)
+	c.remove(key, Replaced)
 
 	// Make sure there's room to add this item
-	c.ensureCapacity(item.Size())
+	if !c.ensureCapacity(key, item.Size()) {
+		c.evict(key, item, Evicted)
+		return
+	}
 
 	// Actually add the new item
-	cached := &cached{item: item}
+	cached := &cached{item: item, key: key, index: -1}
 	cached.setElementIfNotNil(c.recordAdd(key))
 	cached.setElementIfNotNil(c.recordAccess(key))
 	c.items[key] = cached
 	c.size += item.Size()
+	if c.policy != nil {
+		c.policy.add(key, item.Size())
+	}
+	if ttl > 0 {
+		cached.expires = c.now().Add(ttl)
+		heap.Push(&c.expiries, cached)
+	}
 }
 
-func (c *cache) Remove(keys ...string) {
-	c.Lock()
-	defer c.Unlock()
+// Given the need to add an item with some number of new bytes to the cache,
+// remove expired items and then evict items according to the eviction policy
+// until there is room, and take that room.  A shard of a sharded cache that
+// holds less than its share takes room from the other shards first.  Returns
+// false if the item can never fit or the policy rejected it instead.
+// The caller should hold the cache lock.
+func (c *cache) ensureCapacity(toAdd string, size uint64) bool {
+	if size > c.cap {
+		return false
+	}
+	if c.size+size > c.cap || !c.budget.fits(size) {
+		c.expire(c.now())
+	}
 
-	for _, key := range keys {
-		c.remove(key)
+	for c.size+size > c.cap || !c.budget.reserve(size) {
+		if c.budget != nil && c.size < c.budget.share && c.budget.reclaim(c) {
+			continue
+		}
+		key, ok := c.victim(toAdd, size)
+		if !ok || key == toAdd {
+			return false
+		}
+		c.delete(key, Evicted)
 	}
+	return true
 }
 
-func (c *cache) Size() uint64 {
-	c.Lock()
-	defer c.Unlock()
+// Return the key of the next item to evict to make room for the given new
+// item and whether there is one.  A policy stops tracking the key it returns.
+// The caller should hold the cache lock.
+func (c *cache) victim(toAdd string, size uint64) (string, bool) {
+	if c.policy != nil {
+		return c.policy.victim(toAdd, size)
+	}
 
-	return c.size
+	back := c.keyList.Back()
+	if back == nil {
+		return "", false
+	}
+	return back.Value.(string), true
 }
 
-// Given the need to add some number of new bytes to the cache,
-// evict items according to the eviction policy until there is room.
+// Remove the item associated with the given key for the given reason.
 // The caller should hold the cache lock.
-func (c *cache) ensureCapacity(toAdd uint64) {
-	mustRemove := int64(c.size+toAdd) - int64(c.cap)
-	for mustRemove > 0 {
-		key := c.keyList.Back().Value.(string)
-		mustRemove -= int64(c.items[key].item.Size())
-		c.remove(key)
+func (c *cache) remove(key string, reason EvictionReason) {
+	if c.delete(key, reason) && c.policy != nil {
+		c.policy.remove(key)
 	}
 }
 
-// Remove the item associated with the given key.
+// Remove the item associated with the given key for the given reason without
+// notifying the policy and return whether there was one.
 // The caller should hold the cache lock.
-func (c *cache) remove(key string) {
-	if cached, ok := c.items[key]; ok {
-		delete(c.items, key)
-		c.size -= cached.item.Size()
+func (c *cache) delete(key string, reason EvictionReason) bool {
+	cached, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.evict(key, cached.item, reason)
+
+	delete(c.items, key)
+	c.size -= cached.item.Size()
+	c.budget.release(cached.item.Size())
+	if cached.element != nil {
 		c.keyList.Remove(cached.element)
 	}
+	if cached.index >= 0 {
+		heap.Remove(&c.expiries, cached.index)
+	}
+	return true
 }
 
 // A no-op function that does nothing for the provided key
@@ -0,0 +1,66 @@
+package cache
+
+import "time"
+
+// expiryHeap is a min-heap of cached items ordered by when they expire,
+// for use with container/heap.  Items track their index in the heap so they
+// can be removed when they leave the cache early.
+type expiryHeap []*cached
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool { return h[i].expires.Before(h[j].expires) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	cached := x.(*cached)
+	cached.index = len(*h)
+	*h = append(*h, cached)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	cached := old[len(old)-1]
+	old[len(old)-1] = nil
+	cached.index = -1
+	*h = old[:len(old)-1]
+	return cached
+}
+
+// Return whether the item has expired by the given time.
+func (c *cached) expiredAt(now time.Time) bool {
+	return !c.expires.IsZero() && !now.Before(c.expires)
+}
+
+// Remove all items that have expired by the given time.
+// The caller should hold the cache lock.
+func (c *cache) expire(now time.Time) {
+	for len(c.expiries) > 0 && c.expiries[0].expiredAt(now) {
+		c.remove(c.expiries[0].key, Expired)
+	}
+}
+
+// Remove expired items from the given caches at the given interval until
+// done is closed.
+func expireEvery(interval time.Duration, done <-chan struct{}, caches ...*cache) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, c := range caches {
+				c.Lock()
+				c.expire(c.now())
+				c.unlock()
+			}
+		case <-done:
+			return
+		}
+	}
+}
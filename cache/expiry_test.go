@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A clock that only moves when told to
+type fakeClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.Lock()
+	defer fc.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.Lock()
+	defer fc.Unlock()
+	fc.now = fc.now.Add(d)
+}
+
+func TestTTL(t *testing.T) {
+	clock := newFakeClock()
+	c := New(100, DefaultTTL(5*time.Second), Clock(clock.Now))
+	c.Put("foo", testItem(1))
+	c.PutWithTTL("bar", testItem(2), 10*time.Second)
+	c.PutWithTTL("baz", testItem(4), 0)
+
+	clock.Advance(4 * time.Second)
+	assert.Equal(t, []Item{testItem(1), testItem(2), testItem(4)}, c.Get("foo", "bar", "baz"))
+
+	clock.Advance(time.Second)
+	assert.Equal(t, uint64(7), c.Size())
+	assert.Equal(t, []Item{nil, testItem(2), testItem(4)}, c.Get("foo", "bar", "baz"))
+	assert.Equal(t, uint64(6), c.Size())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, []Item{nil, nil, testItem(4)}, c.Get("foo", "bar", "baz"))
+	assert.Equal(t, uint64(4), c.Size())
+}
+
+func TestTTLPutResetsExpiry(t *testing.T) {
+	clock := newFakeClock()
+	c := New(100, Clock(clock.Now))
+	c.PutWithTTL("foo", testItem(1), time.Second)
+	c.PutWithTTL("foo", testItem(2), time.Minute)
+	c.PutWithTTL("bar", testItem(3), time.Second)
+	c.Put("bar", testItem(4))
+
+	clock.Advance(time.Second)
+	assert.Equal(t, []Item{testItem(2), testItem(4)}, c.Get("foo", "bar"))
+	assert.Len(t, c.(*cache).expiries, 1)
+
+	c.Remove("foo")
+	assert.Len(t, c.(*cache).expiries, 0)
+}
+
+func TestTTLExpiredBeforeEviction(t *testing.T) {
+	clock := newFakeClock()
+	c := New(2, EvictionPolicy(LeastRecentlyUsed), Clock(clock.Now))
+	c.PutWithTTL("foo", testItem(1), time.Second)
+	c.Put("bar", testItem(1))
+	clock.Advance(time.Second)
+
+	// bar is least recently used but foo has expired
+	c.Put("baz", testItem(1))
+	assert.Equal(t, []Item{nil, testItem(1), testItem(1)}, c.Get("foo", "bar", "baz"))
+}
+
+func TestExpiryInterval(t *testing.T) {
+	clock := newFakeClock()
+	c := New(100, DefaultTTL(time.Second), ExpiryInterval(time.Millisecond), Clock(clock.Now))
+	defer c.Dispose()
+	c.Put("foo", testItem(1))
+	c.Put("bar", testItem(2))
+	c.PutWithTTL("baz", testItem(4), time.Minute)
+
+	clock.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		return c.Size() == 4
+	}, time.Second, time.Millisecond)
+}
+
+func TestDispose(t *testing.T) {
+	clock := newFakeClock()
+	c := New(100, DefaultTTL(time.Second), ExpiryInterval(time.Millisecond), Clock(clock.Now))
+	c.Dispose()
+	c.Dispose()
+
+	// expired items are no longer removed in the background but are
+	// still never returned
+	c.Put("foo", testItem(1))
+	clock.Advance(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, uint64(1), c.Size())
+	assert.Equal(t, []Item{nil}, c.Get("foo"))
+	assert.Equal(t, uint64(0), c.Size())
+}
+
+func TestExpiryHeap(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	clock := newFakeClock()
+	c := New(50, Clock(clock.Now)).(*cache)
+	for i := 0; i < 10000; i++ {
+		key := string(rune('a' + r.Intn(26)))
+		switch r.Intn(4) {
+		case 0:
+			c.Put(key, testItem(r.Intn(5)+1))
+		case 1:
+			c.PutWithTTL(key, testItem(r.Intn(5)+1), time.Duration(r.Intn(10))*time.Second)
+		case 2:
+			c.Remove(key)
+		case 3:
+			clock.Advance(time.Second)
+			c.Get(key)
+		}
+
+		expiring := 0
+		for _, cached := range c.items {
+			if cached.index >= 0 {
+				expiring++
+				if !assert.Equal(t, cached, c.expiries[cached.index]) {
+					return
+				}
+			}
+		}
+		if !assert.Equal(t, expiring, len(c.expiries)) {
+			return
+		}
+		for j := 1; j < len(c.expiries); j++ {
+			if !assert.False(t, c.expiries[j].expires.Before(c.expiries[(j-1)/2].expires)) {
+				return
+			}
+		}
+	}
+}
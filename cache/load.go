@@ -0,0 +1,58 @@
+package cache
+
+import "errors"
+
+// ErrLoaderPanicked is returned by GetOrLoad to callers waiting on a load
+// whose loader panicked.  The caller that ran the loader sees the panic.
+var ErrLoaderPanicked = errors.New("cache: loader panicked")
+
+// Loader loads the item with the given key on a cache miss.
+type Loader func(key string) (Item, error)
+
+// A load in progress, shared by all callers of GetOrLoad for its key
+type load struct {
+	done  chan struct{} // Closed once the load finishes
+	item  Item
+	err   error
+	stale bool // Whether the key was put or removed during the load
+}
+
+func (c *cache) GetOrLoad(key string, loader Loader) (Item, error) {
+	c.Lock()
+	if item := c.get(key, c.now()); item != nil {
+		c.unlock()
+		return item, nil
+	}
+	if l, ok := c.loads[key]; ok {
+		c.unlock()
+		<-l.done
+		return l.item, l.err
+	}
+	l := &load{done: make(chan struct{})}
+	c.loads[key] = l
+	c.unlock()
+
+	defer func() {
+		c.Lock()
+		delete(c.loads, key)
+		// a loaded item must not replace one put while it was loading
+		if l.err == nil && l.item != nil && !l.stale {
+			c.put(key, l.item, c.ttl)
+		}
+		c.unlock()
+		close(l.done)
+	}()
+
+	// if the loader panics this error is left for those waiting
+	l.err = ErrLoaderPanicked
+	l.item, l.err = loader(key)
+	return l.item, l.err
+}
+
+// Mark the load in progress for the given key, if any, as stale.
+// The caller should hold the cache lock.
+func (c *cache) invalidateLoad(key string) {
+	if l, ok := c.loads[key]; ok {
+		l.stale = true
+	}
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	c := New(100)
+	calls := 0
+	loader := func(key string) (Item, error) {
+		calls++
+		return testItem(len(key)), nil
+	}
+
+	item, err := c.GetOrLoad("foo", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, testItem(3), item)
+	assert.Equal(t, []Item{testItem(3)}, c.Get("foo"))
+
+	item, err = c.GetOrLoad("foo", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, testItem(3), item)
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	c := New(100)
+	loadErr := errors.New("backend down")
+
+	item, err := c.GetOrLoad("foo", func(string) (Item, error) {
+		return nil, loadErr
+	})
+	assert.Equal(t, loadErr, err)
+	assert.Nil(t, item)
+	assert.Equal(t, []Item{nil}, c.Get("foo"))
+	assert.Equal(t, uint64(0), c.Size())
+}
+
+func TestGetOrLoadExpired(t *testing.T) {
+	clock := newFakeClock()
+	c := New(100, DefaultTTL(time.Second), Clock(clock.Now))
+	c.Put("foo", testItem(1))
+	clock.Advance(time.Second)
+
+	item, err := c.GetOrLoad("foo", func(string) (Item, error) {
+		return testItem(2), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testItem(2), item)
+
+	// loaded items expire after the default TTL
+	clock.Advance(time.Second)
+	assert.Equal(t, []Item{nil}, c.Get("foo"))
+}
+
+func TestGetOrLoadConcurrent(t *testing.T) {
+	c := New(100)
+	var calls int32
+	release := make(chan struct{})
+	loader := func(string) (Item, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return testItem(1), nil
+	}
+
+	var wg sync.WaitGroup
+	items := make([]Item, 10)
+	for i := range items {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			items[i], _ = c.GetOrLoad("foo", loader)
+		}(i)
+	}
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, item := range items {
+		assert.Equal(t, testItem(1), item)
+	}
+}
+
+func TestGetOrLoadStale(t *testing.T) {
+	c := New(100)
+	started, release := make(chan struct{}), make(chan struct{})
+	result := make(chan Item)
+	go func() {
+		item, _ := c.GetOrLoad("foo", func(string) (Item, error) {
+			close(started)
+			<-release
+			return testItem(1), nil
+		})
+		result <- item
+	}()
+
+	<-started
+	c.Put("foo", testItem(2))
+	close(release)
+
+	// the caller gets what it loaded but the cache keeps the newer item
+	assert.Equal(t, testItem(1), <-result)
+	assert.Equal(t, []Item{testItem(2)}, c.Get("foo"))
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	c := New(100)
+	started, release := make(chan struct{}), make(chan struct{})
+	loader := func(string) (Item, error) {
+		close(started)
+		<-release
+		panic("boom")
+	}
+
+	waited := make(chan error)
+	go func() {
+		<-started
+		go func() {
+			_, err := c.GetOrLoad("foo", func(string) (Item, error) {
+				return nil, errors.New("load not shared")
+			})
+			waited <- err
+		}()
+		// give the second caller time to wait on the load
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	assert.Panics(t, func() {
+		c.GetOrLoad("foo", loader)
+	})
+	assert.Equal(t, ErrLoaderPanicked, <-waited)
+
+	// the failed load does not linger
+	item, err := c.GetOrLoad("foo", func(string) (Item, error) {
+		return testItem(1), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testItem(1), item)
+}
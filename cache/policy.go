@@ -0,0 +1,149 @@
+package cache
+
+import "container/list"
+
+// A policy tracks the keys in a cache and decides which to evict.
+// The eviction policies beyond LeastRecentlyAdded and LeastRecentlyUsed
+// need more state than a single eviction list and implement this instead.
+// The cache holds its lock while calling a policy.
+type policy interface {
+	// add records that an item of the given size was added with the given key.
+	add(key string, size uint64)
+	// access records that the item with the given key was accessed.
+	access(key string)
+	// miss records that the given key was requested while not in the cache.
+	miss(key string)
+	// remove forgets the given key after its item was removed from the cache.
+	remove(key string)
+	// victim chooses the key of the next item to evict to make room for an
+	// item of the given size being added with the given key, and forgets it.
+	// Choosing the given key rejects the new item instead.  Returns false if
+	// there is no item to evict.
+	victim(key string, size uint64) (string, bool)
+}
+
+// A sizedKey is a key tracked by a policy along with the size of its item
+// and the list it is on
+type sizedKey struct {
+	key  string
+	size uint64
+	list *sizedList
+}
+
+// A sizedList is a list of keys that tracks the size of their items
+type sizedList struct {
+	keys *list.List // Keys in order of increasing evictability
+	size uint64     // Cumulative size of the items of the keys
+}
+
+func newSizedList() *sizedList {
+	return &sizedList{keys: list.New()}
+}
+
+func (l *sizedList) pushFront(k *sizedKey) *list.Element {
+	k.list = l
+	l.size += k.size
+	return l.keys.PushFront(k)
+}
+
+func (l *sizedList) remove(element *list.Element) *sizedKey {
+	k := l.keys.Remove(element).(*sizedKey)
+	l.size -= k.size
+	return k
+}
+
+// A group of keys used equally often, in order of increasing evictability
+type lfuBucket struct {
+	count uint64
+	keys  *list.List
+}
+
+// A key's bucket and its node in that bucket's list of keys
+type lfuEntry struct {
+	bucket  *list.Element
+	element *list.Element
+}
+
+// lfu is a least-frequently-used policy.  Keys are grouped in buckets by the
+// number of times they were used, so every operation takes constant time.
+type lfu struct {
+	buckets *list.List           // Buckets in order of increasing count
+	entries map[string]*lfuEntry // Map from keys to their entries
+}
+
+func newLFU() *lfu {
+	return &lfu{
+		buckets: list.New(),
+		entries: map[string]*lfuEntry{},
+	}
+}
+
+// Move the given key to the front of the bucket with the given count, which
+// follows mark or is the first bucket if mark is nil.
+func (l *lfu) push(key string, count uint64, mark *list.Element) *lfuEntry {
+	next := l.buckets.Front()
+	if mark != nil {
+		next = mark.Next()
+	}
+
+	if next == nil || next.Value.(*lfuBucket).count != count {
+		bucket := &lfuBucket{count: count, keys: list.New()}
+		if mark == nil {
+			next = l.buckets.PushFront(bucket)
+		} else {
+			next = l.buckets.InsertAfter(bucket, mark)
+		}
+	}
+
+	entry := &lfuEntry{bucket: next, element: next.Value.(*lfuBucket).keys.PushFront(key)}
+	l.entries[key] = entry
+	return entry
+}
+
+// Remove the given entry from its bucket, dropping the bucket if it empties,
+// and return the bucket preceding it.
+func (l *lfu) unlink(entry *lfuEntry) *list.Element {
+	bucket := entry.bucket.Value.(*lfuBucket)
+	bucket.keys.Remove(entry.element)
+
+	prev := entry.bucket.Prev()
+	if bucket.keys.Len() == 0 {
+		l.buckets.Remove(entry.bucket)
+		return prev
+	}
+	return entry.bucket
+}
+
+func (l *lfu) add(key string, size uint64) {
+	l.push(key, 1, nil)
+}
+
+func (l *lfu) access(key string) {
+	entry, ok := l.entries[key]
+	if !ok {
+		return
+	}
+
+	count := entry.bucket.Value.(*lfuBucket).count
+	l.push(key, count+1, l.unlink(entry))
+}
+
+func (l *lfu) miss(string) {}
+
+func (l *lfu) remove(key string) {
+	if entry, ok := l.entries[key]; ok {
+		l.unlink(entry)
+		delete(l.entries, key)
+	}
+}
+
+func (l *lfu) victim(string, uint64) (string, bool) {
+	front := l.buckets.Front()
+	if front == nil {
+		return "", false
+	}
+
+	key := front.Value.(*lfuBucket).keys.Back().Value.(string)
+	l.remove(key)
+	return key, true
+}
@@ -0,0 +1,294 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var policies = map[string]Policy{
+	"LeastRecentlyAdded":  LeastRecentlyAdded,
+	"LeastRecentlyUsed":   LeastRecentlyUsed,
+	"LeastFrequentlyUsed": LeastFrequentlyUsed,
+	"AdaptiveReplacement": AdaptiveReplacement,
+	"WindowTinyLFU":       WindowTinyLFU,
+}
+
+// Return the total size of the items a policy tracks as being in the cache.
+func trackedSize(c *cache) uint64 {
+	switch p := c.policy.(type) {
+	case *lfu:
+		size := uint64(0)
+		for key := range p.entries {
+			size += c.items[key].item.Size()
+		}
+		return size
+	case *arc:
+		return p.recent.size + p.frequent.size
+	case *tinyLFU:
+		return p.window.size + p.probation.size + p.protected.size
+	}
+
+	size := uint64(0)
+	for e := c.keyList.Front(); e != nil; e = e.Next() {
+		size += c.items[e.Value.(string)].item.Size()
+	}
+	return size
+}
+
+func TestEvictionPolicyTracker(t *testing.T) {
+	c := New(100, EvictionPolicy(LeastFrequentlyUsed)).(*cache)
+	assert.IsType(t, &lfu{}, c.policy)
+	assert.Nil(t, c.recordAccess("foo"))
+	assert.Nil(t, c.recordAdd("foo"))
+
+	c = New(100, EvictionPolicy(AdaptiveReplacement)).(*cache)
+	assert.IsType(t, &arc{}, c.policy)
+
+	c = New(100, EvictionPolicy(WindowTinyLFU)).(*cache)
+	assert.IsType(t, &tinyLFU{}, c.policy)
+
+	// setting a list policy again drops the tracker
+	EvictionPolicy(LeastRecentlyUsed)(c)
+	assert.Nil(t, c.policy)
+}
+
+// Return the sorted keys of the items in the cache without using them.
+func cachedKeys(c Cache) []string {
+	keys := make([]string, 0, len(c.(*cache).items))
+	for key := range c.(*cache).items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestLFUEviction(t *testing.T) {
+	c := New(3, EvictionPolicy(LeastFrequentlyUsed))
+	c.Put("foo", testItem(1))
+	c.Put("bar", testItem(1))
+	c.Put("baz", testItem(1))
+	c.Get("foo", "foo", "bar")
+
+	c.Put("qux", testItem(1))
+	assert.Equal(t, []string{"bar", "foo", "qux"}, cachedKeys(c))
+
+	// qux is now used as often as bar but bar was used less recently
+	c.Get("qux")
+	c.Put("baz", testItem(1))
+	assert.Equal(t, []string{"baz", "foo", "qux"}, cachedKeys(c))
+	assert.Equal(t, uint64(3), c.Size())
+}
+
+func TestLFUEvictionBySize(t *testing.T) {
+	c := New(10, EvictionPolicy(LeastFrequentlyUsed))
+	c.Put("foo", testItem(4))
+	c.Put("bar", testItem(4))
+	c.Put("baz", testItem(2))
+	c.Get("baz", "foo")
+
+	// bar alone is not enough room
+	c.Put("qux", testItem(5))
+	assert.Equal(t, []string{"foo", "qux"}, cachedKeys(c))
+	assert.Equal(t, uint64(9), c.Size())
+}
+
+func TestARCScanResistance(t *testing.T) {
+	c := New(10, EvictionPolicy(AdaptiveReplacement))
+	hot := []string{"a", "b", "c", "d", "e"}
+	for _, key := range hot {
+		c.Put(key, testItem(1))
+	}
+	c.Get(hot...)
+
+	for i := 0; i < 100; i++ {
+		c.Put(fmt.Sprintf("scan%d", i), testItem(1))
+	}
+
+	for i, item := range c.Get(hot...) {
+		assert.NotNil(t, item, hot[i])
+	}
+	assert.Equal(t, uint64(10), c.Size())
+}
+
+func TestARCAdaptsTarget(t *testing.T) {
+	c := New(4, EvictionPolicy(AdaptiveReplacement)).(*cache)
+	a := c.policy.(*arc)
+	c.Put("a", testItem(1))
+	c.Put("b", testItem(1))
+	c.Get("a", "b")
+	c.Put("c", testItem(1))
+	c.Put("d", testItem(1))
+	c.Put("e", testItem(1))
+	c.Put("f", testItem(1))
+	assert.Equal(t, []string{"a", "b", "e", "f"}, cachedKeys(c))
+	assert.Equal(t, uint64(0), a.target)
+
+	// c was evicted from recent too soon, so recent should grow
+	c.Put("c", testItem(1))
+	assert.Equal(t, uint64(1), a.target)
+	assert.Equal(t, uint64(3), a.frequent.size)
+	assert.Equal(t, []string{"a", "b", "c", "f"}, cachedKeys(c))
+
+	// recent is at its target so frequent gives way
+	c.Put("g", testItem(1))
+	assert.Equal(t, []string{"b", "c", "f", "g"}, cachedKeys(c))
+}
+
+func TestARCZeroSizeGhosts(t *testing.T) {
+	// a key returns from recent's ghosts, which are all empty
+	a := newARC(4)
+	a.add("a", 0)
+	a.add("b", 2)
+	a.add("b", 2)
+	a.victim("c", 2)
+	a.victim("c", 2)
+	assert.Equal(t, uint64(0), a.recentGhosts.size)
+	assert.Equal(t, uint64(2), a.frequentGhosts.size)
+	a.add("a", 0)
+	assert.Equal(t, uint64(0), a.target)
+
+	// a key returns from frequent's ghosts, which are all empty
+	a = newARC(4)
+	a.add("a", 0)
+	a.add("a", 0)
+	a.add("b", 2)
+	a.victim("c", 2)
+	a.victim("c", 2)
+	assert.Equal(t, uint64(2), a.recentGhosts.size)
+	assert.Equal(t, uint64(0), a.frequentGhosts.size)
+	a.target = 3
+	a.add("a", 0)
+	assert.Equal(t, uint64(3), a.target)
+
+	r := rand.New(rand.NewSource(1))
+	c := New(10, EvictionPolicy(AdaptiveReplacement))
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprintf("%d", r.Intn(20))
+		switch r.Intn(3) {
+		case 0:
+			c.Put(key, testItem(r.Intn(6)))
+		case 1:
+			c.Get(key)
+		case 2:
+			c.Remove(key)
+		}
+	}
+	assert.True(t, c.Size() <= 10)
+}
+
+func TestTinyLFUScanResistance(t *testing.T) {
+	c := New(100, EvictionPolicy(WindowTinyLFU))
+	hot := make([]string, 50)
+	for i := range hot {
+		hot[i] = fmt.Sprintf("hot%d", i)
+		c.Put(hot[i], testItem(1))
+	}
+	// push the last hot key out of the window so all are protected
+	c.Put("filler", testItem(1))
+	for i := 0; i < 3; i++ {
+		c.Get(hot...)
+	}
+
+	for i := 0; i < 1000; i++ {
+		c.Put(fmt.Sprintf("scan%d", i), testItem(1))
+	}
+
+	for i, item := range c.Get(hot...) {
+		assert.NotNil(t, item, hot[i])
+	}
+	assert.Equal(t, uint64(100), c.Size())
+}
+
+func TestTinyLFURejection(t *testing.T) {
+	c := New(100, EvictionPolicy(WindowTinyLFU))
+	hot := make([]string, 9)
+	for i := range hot {
+		hot[i] = fmt.Sprintf("hot%d", i)
+		c.Put(hot[i], testItem(11))
+	}
+	c.Get(hot...)
+
+	// too big for the window and used less than anything it would displace
+	c.Put("big", testItem(5))
+	assert.Equal(t, []Item{nil}, c.Get("big"))
+	assert.Equal(t, uint64(99), c.Size())
+
+	// a small item fits in the window without displacing anything
+	c.Put("small", testItem(1))
+	assert.Equal(t, []Item{testItem(1)}, c.Get("small"))
+	assert.Equal(t, uint64(100), c.Size())
+}
+
+func TestTinyLFUAdmitsMissedKeys(t *testing.T) {
+	c := New(100, EvictionPolicy(WindowTinyLFU))
+	hot := make([]string, 9)
+	for i := range hot {
+		hot[i] = fmt.Sprintf("hot%d", i)
+		c.Put(hot[i], testItem(11))
+	}
+	c.Get(hot...)
+
+	// requests that miss count towards admission
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, []Item{nil}, c.Get("wanted"))
+	}
+	c.Put("wanted", testItem(5))
+	assert.Equal(t, []Item{testItem(5)}, c.Get("wanted"))
+	assert.Equal(t, uint64(93), c.Size())
+}
+
+func TestPolicySizeAccounting(t *testing.T) {
+	for name, p := range policies {
+		t.Run(name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			c := New(50, EvictionPolicy(p)).(*cache)
+			for i := 0; i < 10000; i++ {
+				key := fmt.Sprintf("%d", r.Intn(40))
+				switch r.Intn(4) {
+				case 0, 1:
+					c.Put(key, testItem(r.Intn(10)+1))
+				case 2:
+					c.Get(key)
+				case 3:
+					c.Remove(key)
+				}
+
+				size := uint64(0)
+				for _, cached := range c.items {
+					size += cached.item.Size()
+				}
+				if !assert.Equal(t, size, c.Size()) ||
+					!assert.True(t, c.Size() <= 50) ||
+					!assert.Equal(t, size, trackedSize(c)) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPolicies(b *testing.B) {
+	for name, p := range policies {
+		b.Run(name, func(b *testing.B) {
+			r := rand.New(rand.NewSource(1))
+			c := New(1000, EvictionPolicy(p))
+			keys := make([]string, 10000)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("%d", i)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				// skewed towards the first keys
+				key := keys[r.Intn(r.Intn(len(keys))+1)]
+				if c.Get(key)[0] == nil {
+					c.Put(key, testItem(1))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A cache that splits its keys between independently locked shards
+type sharded struct {
+	seed        maphash.Seed
+	shards      []*cache
+	done        chan struct{} // Closed to stop removing expired items
+	disposeOnce sync.Once
+}
+
+// Capacity shared between the shards of a sharded cache
+type budget struct {
+	capacity uint64        // Capacity bound of the whole cache
+	share    uint64        // Room each shard may always take from the others
+	size     atomic.Uint64 // Cumulative size of the items in every shard
+	shards   []*cache
+	next     atomic.Uint64 // Counter choosing the shard to reclaim room from first
+}
+
+// Return whether an item of the given size fits in the budget.
+func (b *budget) fits(size uint64) bool {
+	return b == nil || b.size.Load()+size <= b.capacity
+}
+
+// Take room for an item of the given size from the budget and return whether
+// there was enough.
+func (b *budget) reserve(size uint64) bool {
+	if b == nil {
+		return true
+	}
+	for {
+		used := b.size.Load()
+		if used+size > b.capacity {
+			return false
+		}
+		if b.size.CompareAndSwap(used, used+size) {
+			return true
+		}
+	}
+}
+
+// Give back the room taken by an item of the given size.
+func (b *budget) release(size uint64) {
+	if b != nil {
+		b.size.Add(-size)
+	}
+}
+
+// Free room in one of the shards other than c that holds more than its share,
+// by removing its expired items or else evicting its next victim, and return
+// whether any was freed.  The caller holds the lock of c, so a shard is
+// skipped rather than waited for if it is locked, and the items that leave it
+// are passed to the OnEvict function once c is unlocked.
+func (b *budget) reclaim(c *cache) bool {
+	start := b.next.Add(1)
+	for i := range b.shards {
+		shard := b.shards[(start+uint64(i))%uint64(len(b.shards))]
+		if shard == c || !shard.TryLock() {
+			continue
+		}
+
+		size := shard.size
+		if size > b.share {
+			shard.expire(shard.now())
+		}
+		if size > b.share && shard.size == size {
+			// the victim is chosen as if for an empty item, which no policy rejects
+			if key, ok := shard.victim("", 0); ok {
+				shard.delete(key, Evicted)
+			}
+		}
+		freed := shard.size < size
+		c.evictions = append(c.evictions, shard.evictions...)
+		shard.evictions = nil
+		shard.Unlock()
+
+		if freed {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSharded returns a cache with the requested options configured that
+// splits its keys between the given number of shards, at least one.  Each
+// shard has its own lock and eviction policy, and requests for keys in
+// different shards do not wait for each other.  The shards share the
+// capacity, so the cache as a whole never exceeds it: a shard may fill the
+// room the others leave free, and while it holds less than an equal share it
+// makes room by evicting from shards holding more before evicting its own
+// items.
+func NewSharded(capacity uint64, shards int, options ...CacheOption) Cache {
+	shards = max(shards, 1)
+	s := &sharded{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*cache, shards),
+		done:   make(chan struct{}),
+	}
+
+	b := &budget{
+		capacity: capacity,
+		share:    capacity / uint64(shards),
+		shards:   s.shards,
+	}
+	for i := range s.shards {
+		s.shards[i] = newCache(capacity, options...)
+		s.shards[i].budget = b
+	}
+
+	// a single goroutine removes expired items from every shard
+	if interval := s.shards[0].interval; interval > 0 {
+		go expireEvery(interval, s.done, s.shards...)
+	}
+
+	return s
+}
+
+// Return the shard responsible for the given key.
+func (s *sharded) shard(key string) *cache {
+	return s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+func (s *sharded) Get(keys ...string) []Item {
+	items := make([]Item, len(keys))
+	for i, key := range keys {
+		c := s.shard(key)
+		c.Lock()
+		items[i] = c.get(key, c.now())
+		c.unlock()
+	}
+
+	return items
+}
+
+func (s *sharded) Put(key string, item Item) {
+	s.shard(key).Put(key, item)
+}
+
+func (s *sharded) PutWithTTL(key string, item Item, ttl time.Duration) {
+	s.shard(key).PutWithTTL(key, item, ttl)
+}
+
+func (s *sharded) GetOrLoad(key string, loader Loader) (Item, error) {
+	return s.shard(key).GetOrLoad(key, loader)
+}
+
+func (s *sharded) Remove(keys ...string) {
+	for _, key := range keys {
+		s.shard(key).Remove(key)
+	}
+}
+
+func (s *sharded) Size() uint64 {
+	size := uint64(0)
+	for _, c := range s.shards {
+		size += c.Size()
+	}
+
+	return size
+}
+
+func (s *sharded) Stats() Stats {
+	stats := Stats{}
+	for _, c := range s.shards {
+		shardStats := c.Stats()
+		stats.Hits += shardStats.Hits
+		stats.Misses += shardStats.Misses
+		stats.Evictions += shardStats.Evictions
+		stats.Expirations += shardStats.Expirations
+		stats.Items += shardStats.Items
+		stats.Size += shardStats.Size
+	}
+
+	return stats
+}
+
+func (s *sharded) Dispose() {
+	s.disposeOnce.Do(func() {
+		close(s.done)
+	})
+}
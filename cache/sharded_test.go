@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSharded(t *testing.T) {
+	s := NewSharded(10, 4, EvictionPolicy(AdaptiveReplacement)).(*sharded)
+	assert.Len(t, s.shards, 4)
+
+	for _, c := range s.shards {
+		assert.Equal(t, uint64(10), c.cap)
+		assert.Same(t, s.shards[0].budget, c.budget)
+		assert.IsType(t, &arc{}, c.policy)
+	}
+	assert.Equal(t, uint64(2), s.shards[0].budget.share)
+
+	s = NewSharded(10, 0).(*sharded)
+	assert.Len(t, s.shards, 1)
+	assert.Equal(t, uint64(10), s.shards[0].cap)
+}
+
+func TestShardedPutGetRemove(t *testing.T) {
+	c := NewSharded(100, 8)
+	c.Put("foo", testItem(1))
+	c.Put("bar", testItem(2))
+	c.PutWithTTL("baz", testItem(3), time.Hour)
+	assert.Equal(t, []Item{testItem(1), testItem(2), testItem(3), nil}, c.Get("foo", "bar", "baz", "qux"))
+	assert.Equal(t, uint64(6), c.Size())
+
+	c.Put("foo", testItem(4))
+	c.Remove("bar", "qux")
+	assert.Equal(t, []Item{testItem(4), nil, testItem(3)}, c.Get("foo", "bar", "baz"))
+	assert.Equal(t, uint64(7), c.Size())
+
+	item, err := c.GetOrLoad("qux", func(key string) (Item, error) {
+		return testItem(5), nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, testItem(5), item)
+	assert.Equal(t, []Item{testItem(5)}, c.Get("qux"))
+}
+
+func TestShardedCapacity(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	c := NewSharded(100, 4, EvictionPolicy(WindowTinyLFU)).(*sharded)
+	for i := 0; i < 10000; i++ {
+		c.Put(fmt.Sprintf("%d", r.Intn(1000)), testItem(r.Intn(10)+1))
+		if !assert.True(t, c.Size() <= 100) {
+			return
+		}
+		for _, shard := range c.shards {
+			if !assert.True(t, shard.size <= shard.cap) {
+				return
+			}
+		}
+	}
+
+	// every shard takes its share of the keys
+	for _, shard := range c.shards {
+		assert.NotZero(t, len(shard.items))
+	}
+}
+
+// Return the given number of keys that belong to the given shard.
+func shardKeys(s *sharded, shard, n int) []string {
+	keys := []string{}
+	for i := 0; len(keys) < n; i++ {
+		key := fmt.Sprintf("%d", i)
+		if s.shard(key) == s.shards[shard] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func TestShardedSharesCapacity(t *testing.T) {
+	s := NewSharded(100, 4).(*sharded)
+
+	// a single shard may fill the whole cache
+	hot := shardKeys(s, 0, 100)
+	for _, key := range hot {
+		s.Put(key, testItem(1))
+	}
+	assert.Equal(t, uint64(100), s.Size())
+	assert.Equal(t, uint64(100), s.shards[0].size)
+
+	// and an item may be larger than an equal share
+	s.Put("big", testItem(60))
+	assert.Equal(t, []Item{testItem(60)}, s.Get("big"))
+	assert.Equal(t, uint64(100), s.Size())
+}
+
+func TestShardedReclaim(t *testing.T) {
+	evicted := []string{}
+	s := NewSharded(100, 4, OnEvict(func(key string, item Item, reason EvictionReason) {
+		evicted = append(evicted, key)
+	})).(*sharded)
+
+	hot, cold := shardKeys(s, 0, 100), shardKeys(s, 1, 50)
+	for _, key := range hot {
+		s.Put(key, testItem(1))
+	}
+
+	// a shard below its share evicts from the others
+	for _, key := range cold[:25] {
+		s.Put(key, testItem(1))
+	}
+	assert.Equal(t, uint64(75), s.shards[0].size)
+	assert.Equal(t, uint64(25), s.shards[1].size)
+	assert.Equal(t, hot[:25], evicted)
+	assert.Equal(t, uint64(25), s.Stats().Evictions)
+
+	// and its own items once it has its share
+	for _, key := range cold[25:] {
+		s.Put(key, testItem(1))
+	}
+	assert.Equal(t, uint64(75), s.shards[0].size)
+	assert.Equal(t, uint64(25), s.shards[1].size)
+	assert.Equal(t, cold[:25], evicted[25:])
+	assert.Equal(t, uint64(100), s.Size())
+}
+
+func TestShardedReclaimExpired(t *testing.T) {
+	clock := newFakeClock()
+	s := NewSharded(100, 4, Clock(clock.Now)).(*sharded)
+
+	hot, cold := shardKeys(s, 0, 100), shardKeys(s, 1, 1)
+	for _, key := range hot[:50] {
+		s.PutWithTTL(key, testItem(1), time.Second)
+	}
+	for _, key := range hot[50:] {
+		s.Put(key, testItem(1))
+	}
+
+	// expired items go before any item is evicted
+	clock.Advance(time.Second)
+	s.Put(cold[0], testItem(1))
+	assert.Equal(t, uint64(51), s.Size())
+	assert.Equal(t, uint64(0), s.Stats().Evictions)
+	assert.Equal(t, uint64(50), s.Stats().Expirations)
+}
+
+func TestShardedExpiry(t *testing.T) {
+	clock := newFakeClock()
+	c := NewSharded(100, 4, DefaultTTL(time.Second), ExpiryInterval(time.Millisecond), Clock(clock.Now))
+	defer c.Dispose()
+	for i := 0; i < 20; i++ {
+		c.Put(fmt.Sprintf("%d", i), testItem(1))
+	}
+	c.PutWithTTL("foo", testItem(5), 0)
+
+	clock.Advance(time.Second)
+	assert.Eventually(t, func() bool {
+		return c.Size() == 5
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []Item{nil, testItem(5)}, c.Get("0", "foo"))
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	c := NewSharded(1000, 16)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(i)))
+			for j := 0; j < 1000; j++ {
+				key := fmt.Sprintf("%d", r.Intn(200))
+				switch r.Intn(3) {
+				case 0:
+					c.Put(key, testItem(r.Intn(10)+1))
+				case 1:
+					c.Get(key)
+				case 2:
+					c.Remove(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, c.Size() <= 1000)
+}
+
+func BenchmarkContention(b *testing.B) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%d", i)
+	}
+
+	for name, c := range map[string]Cache{
+		"Single":  New(500),
+		"Sharded": NewSharded(500, 16),
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewSource(1))
+				for pb.Next() {
+					key := keys[r.Intn(len(keys))]
+					if c.Get(key)[0] == nil {
+						c.Put(key, testItem(1))
+					}
+				}
+			})
+		})
+	}
+}
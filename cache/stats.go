@@ -0,0 +1,95 @@
+package cache
+
+// EvictionReason is the reason an item left the cache, given to the
+// function set with the OnEvict CacheOption.
+type EvictionReason uint8
+
+const (
+	// Evicted indicates the eviction policy made room for other items with
+	// the item, or declined to keep the item when it was added.
+	Evicted EvictionReason = iota
+	// Expired indicates the item outlived its TTL.
+	Expired
+	// Removed indicates the item was removed with Remove.
+	Removed
+	// Replaced indicates another item was put with the item's key.
+	Replaced
+)
+
+func (r EvictionReason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	case Removed:
+		return "removed"
+	case Replaced:
+		return "replaced"
+	}
+	return "unknown"
+}
+
+// OnEvict sets a function to call with every item that leaves the cache and
+// the reason it left.  It is called after the cache is unlocked, so it may
+// use the cache, but may be called concurrently for different items.
+func OnEvict(onEvict func(key string, item Item, reason EvictionReason)) CacheOption {
+	return func(c *cache) {
+		c.onEvict = onEvict
+	}
+}
+
+// Stats are counts describing a cache's use and contents.
+type Stats struct {
+	Hits        uint64 // Number of keys found by Get and GetOrLoad
+	Misses      uint64 // Number of keys not found by Get and GetOrLoad
+	Evictions   uint64 // Number of items evicted by the eviction policy
+	Expirations uint64 // Number of items that expired
+	Items       uint64 // Number of items currently in the cache
+	Size        uint64 // Size of all items currently in the cache
+}
+
+// An item that left the cache, waiting to be passed to the OnEvict function
+type eviction struct {
+	key    string
+	item   Item
+	reason EvictionReason
+}
+
+func (c *cache) Stats() Stats {
+	c.Lock()
+	defer c.Unlock()
+
+	stats := c.stats
+	stats.Items = uint64(len(c.items))
+	stats.Size = c.size
+	return stats
+}
+
+// Count the given item leaving the cache and queue it for the OnEvict
+// function, if any.
+// The caller should hold the cache lock.
+func (c *cache) evict(key string, item Item, reason EvictionReason) {
+	switch reason {
+	case Evicted:
+		c.stats.Evictions++
+	case Expired:
+		c.stats.Expirations++
+	}
+
+	if c.onEvict != nil {
+		c.evictions = append(c.evictions, eviction{key: key, item: item, reason: reason})
+	}
+}
+
+// Unlock the cache and then pass the items that left it while it was locked
+// to the OnEvict function.
+func (c *cache) unlock() {
+	evictions := c.evictions
+	c.evictions = nil
+	c.Unlock()
+
+	for _, e := range evictions {
+		c.onEvict(e.key, e.item, e.reason)
+	}
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type evictionLog []eviction
+
+func (l *evictionLog) onEvict(key string, item Item, reason EvictionReason) {
+	*l = append(*l, eviction{key: key, item: item, reason: reason})
+}
+
+func TestOnEvict(t *testing.T) {
+	clock := newFakeClock()
+	log := evictionLog{}
+	c := New(3, OnEvict(log.onEvict), Clock(clock.Now))
+
+	c.Put("foo", testItem(1))
+	c.Put("foo", testItem(2))
+	c.Put("bar", testItem(1))
+	c.Put("baz", testItem(1))
+	c.Remove("bar", "qux")
+	c.PutWithTTL("qux", testItem(1), time.Second)
+	clock.Advance(time.Second)
+	c.Get("qux")
+	c.Put("big", testItem(4))
+
+	assert.Equal(t, evictionLog{
+		{"foo", testItem(1), Replaced},
+		{"foo", testItem(2), Evicted},
+		{"bar", testItem(1), Removed},
+		{"qux", testItem(1), Expired},
+		{"big", testItem(4), Evicted},
+	}, log)
+	assert.Equal(t, []Item{testItem(1)}, c.Get("baz"))
+}
+
+func TestOnEvictRejected(t *testing.T) {
+	log := evictionLog{}
+	c := New(100, EvictionPolicy(WindowTinyLFU), OnEvict(log.onEvict))
+	for i := 0; i < 9; i++ {
+		c.Put(string(rune('a'+i)), testItem(11))
+	}
+	c.Put("big", testItem(5))
+	assert.Equal(t, evictionLog{{"big", testItem(5), Evicted}}, log)
+}
+
+func TestOnEvictUnlocked(t *testing.T) {
+	var c Cache
+	readd := true
+	c = New(1, OnEvict(func(key string, item Item, reason EvictionReason) {
+		// the cache can be used from within the callback
+		if readd {
+			readd = false
+			c.Remove("bar")
+			c.Put(key, item)
+		}
+	}))
+
+	c.Put("foo", testItem(1))
+	c.Put("bar", testItem(1))
+	assert.Equal(t, []Item{testItem(1), nil}, c.Get("foo", "bar"))
+}
+
+func TestStats(t *testing.T) {
+	clock := newFakeClock()
+	for name, c := range map[string]Cache{
+		"Single":  New(10, Clock(clock.Now)),
+		"Sharded": NewSharded(10, 1, Clock(clock.Now)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c.Put("foo", testItem(2))
+			c.Put("bar", testItem(3))
+			c.PutWithTTL("baz", testItem(1), time.Second)
+			c.Get("foo", "bar", "qux")
+			c.GetOrLoad("foo", nil)
+			c.GetOrLoad("qux", func(string) (Item, error) {
+				return nil, errors.New("not found")
+			})
+			clock.Advance(time.Second)
+
+			// baz expired so only bar needs to be evicted
+			c.Put("big", testItem(6))
+			c.Get("baz")
+
+			assert.Equal(t, Stats{
+				Hits:        3,
+				Misses:      3,
+				Evictions:   1,
+				Expirations: 1,
+				Items:       2,
+				Size:        8,
+			}, c.Stats())
+		})
+	}
+}
+
+func TestEvictionReasonString(t *testing.T) {
+	assert.Equal(t, "evicted", Evicted.String())
+	assert.Equal(t, "expired", Expired.String())
+	assert.Equal(t, "removed", Removed.String())
+	assert.Equal(t, "replaced", Replaced.String())
+	assert.Equal(t, "unknown", EvictionReason(42).String())
+}
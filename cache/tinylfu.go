@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"container/list"
+	"hash/maphash"
+)
+
+// sketch is a count-min sketch estimating how often keys were used, with
+// four rows of counters capped at 15.  Only the smallest of a key's counters
+// are incremented, which keeps rarely used keys from inheriting the counts of
+// those they collide with.  Once it has counted ten uses per counter in a row
+// all counters are halved so old uses matter less.  The width of a row is a
+// power of two so the sketch can grow without losing its counts.
+type sketch struct {
+	seed      maphash.Seed
+	counters  []uint8
+	mask      uint64
+	additions uint64
+}
+
+func newSketch() *sketch {
+	return &sketch{
+		seed:     maphash.MakeSeed(),
+		counters: make([]uint8, 4*1024),
+		mask:     1023,
+	}
+}
+
+// Double the number of counters per row.  A key's counter in a row either
+// keeps its index or moves up by the old width, so both new counters start
+// with the old count.
+func (s *sketch) grow() {
+	width := s.mask + 1
+	counters := make([]uint8, 8*width)
+	for row := uint64(0); row < 4; row++ {
+		old := s.counters[row*width : (row+1)*width]
+		copy(counters[2*row*width:], old)
+		copy(counters[(2*row+1)*width:], old)
+	}
+	s.counters = counters
+	s.mask = 2*width - 1
+}
+
+// Return the index of the given key's counter in every row.
+func (s *sketch) indices(key string) [4]uint64 {
+	h := maphash.String(s.seed, key)
+	h1, h2 := h, h>>32|1
+	var indices [4]uint64
+	for i := range indices {
+		indices[i] = uint64(i)*(s.mask+1) + (h1+uint64(i)*h2)&s.mask
+	}
+	return indices
+}
+
+func (s *sketch) increment(key string) {
+	indices := s.indices(key)
+	estimate := s.estimateAt(indices)
+	for _, i := range indices {
+		if s.counters[i] == estimate && estimate < 15 {
+			s.counters[i]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= 10*(s.mask+1) {
+		for i := range s.counters {
+			s.counters[i] >>= 1
+		}
+		s.additions /= 2
+	}
+}
+
+func (s *sketch) estimate(key string) uint8 {
+	return s.estimateAt(s.indices(key))
+}
+
+// Return the smallest of the counters at the given indices.
+func (s *sketch) estimateAt(indices [4]uint64) uint8 {
+	estimate := uint8(15)
+	for _, i := range indices {
+		estimate = min(estimate, s.counters[i])
+	}
+	return estimate
+}
+
+// tinyLFU is a W-TinyLFU policy, as described by Einziger, Friedman and
+// Manes, measured in bytes rather than items.  New items enter a window
+// holding 1% of the capacity and evicted in least-recently-used order.  Items
+// leaving the window are admitted to the main space only if the sketch
+// estimates they were requested more often, whether or not they were cached
+// at the time, than the item main would evict for them.  Main is a segmented
+// LRU: items start on probation and are protected once used again, with
+// protected holding at most 80% of main.
+type tinyLFU struct {
+	window       *sizedList
+	probation    *sizedList
+	protected    *sizedList
+	windowCap    uint64
+	mainCap      uint64
+	protectedCap uint64
+	entries      map[string]*list.Element // Map from keys to their entries
+	sketch       *sketch
+}
+
+func newTinyLFU(capacity uint64) *tinyLFU {
+	windowCap := max(capacity/100, 1)
+	mainCap := capacity - min(capacity, windowCap)
+	return &tinyLFU{
+		window:       newSizedList(),
+		probation:    newSizedList(),
+		protected:    newSizedList(),
+		windowCap:    windowCap,
+		mainCap:      mainCap,
+		protectedCap: mainCap / 5 * 4,
+		entries:      map[string]*list.Element{},
+		sketch:       newSketch(),
+	}
+}
+
+// Move the key in the given element to the front of the given segment.
+func (t *tinyLFU) move(element *list.Element, to *sizedList) {
+	k := element.Value.(*sizedKey)
+	k.list.remove(element)
+	t.entries[k.key] = to.pushFront(k)
+}
+
+func (t *tinyLFU) add(key string, size uint64) {
+	// grow while the sketch is sparse, since counts that collided before
+	// growing still collide after
+	if uint64(len(t.entries)+1) > (t.sketch.mask+1)/4 {
+		t.sketch.grow()
+	}
+	t.sketch.increment(key)
+
+	t.entries[key] = t.window.pushFront(&sizedKey{key: key, size: size})
+
+	// move items out of the window while main has room for them
+	for t.window.size > t.windowCap {
+		back := t.window.keys.Back()
+		if t.probation.size+t.protected.size+back.Value.(*sizedKey).size > t.mainCap {
+			break
+		}
+		t.move(back, t.probation)
+	}
+}
+
+func (t *tinyLFU) access(key string) {
+	element, ok := t.entries[key]
+	if !ok {
+		return
+	}
+
+	t.sketch.increment(key)
+	switch element.Value.(*sizedKey).list {
+	case t.window:
+		t.window.keys.MoveToFront(element)
+	case t.probation:
+		t.move(element, t.protected)
+		for t.protected.size > t.protectedCap {
+			t.move(t.protected.keys.Back(), t.probation)
+		}
+	case t.protected:
+		t.protected.keys.MoveToFront(element)
+	}
+}
+
+func (t *tinyLFU) miss(key string) {
+	t.sketch.increment(key)
+}
+
+func (t *tinyLFU) remove(key string) {
+	if element, ok := t.entries[key]; ok {
+		element.Value.(*sizedKey).list.remove(element)
+		delete(t.entries, key)
+	}
+}
+
+// Return the element main would evict next, nil if main is empty.
+func (t *tinyLFU) mainVictim() *list.Element {
+	if back := t.probation.keys.Back(); back != nil {
+		return back
+	}
+	return t.protected.keys.Back()
+}
+
+// Forget the key in the given element and return it.
+func (t *tinyLFU) evict(element *list.Element) (string, bool) {
+	key := element.Value.(*sizedKey).key
+	t.remove(key)
+	return key, true
+}
+
+func (t *tinyLFU) victim(key string, size uint64) (string, bool) {
+	for {
+		mainVictim := t.mainVictim()
+		if t.window.size+size <= t.windowCap {
+			// the window has room so main must shrink
+			if mainVictim == nil {
+				return "", false
+			}
+			return t.evict(mainVictim)
+		}
+
+		// the window overflows so the item leaving it, or the new item if
+		// the window is empty, is a candidate for main
+		candidate := t.window.keys.Back()
+		candidateKey, candidateSize := key, size
+		if candidate != nil {
+			candidateKey, candidateSize = candidate.Value.(*sizedKey).key, candidate.Value.(*sizedKey).size
+		}
+
+		if candidate != nil && t.probation.size+t.protected.size+candidateSize <= t.mainCap {
+			t.move(candidate, t.probation)
+			continue
+		}
+
+		// the candidate is admitted only if it is used more often
+		if mainVictim != nil && t.sketch.estimate(candidateKey) > t.sketch.estimate(mainVictim.Value.(*sizedKey).key) {
+			if candidate != nil {
+				t.move(candidate, t.probation)
+			}
+			return t.evict(mainVictim)
+		}
+
+		if candidate == nil {
+			return key, true
+		}
+		return t.evict(candidate)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import "cmp"
+
+// CompareFunc is the generic replacement for Comparator.  It returns a
+// positive number if a is greater than b, 0 if they are equal, and a
+// negative number if a is less than b.
+type CompareFunc[T any] func(a, b T) int
+
+// Ordered returns a CompareFunc for any type that supports the builtin
+// ordering operators.
+func Ordered[T cmp.Ordered]() CompareFunc[T] {
+	return cmp.Compare[T]
+}
+
+// Reverse returns a CompareFunc that orders items in the opposite
+// direction of the provided CompareFunc.
+func Reverse[T any](compare CompareFunc[T]) CompareFunc[T] {
+	return func(a, b T) int {
+		return compare(b, a)
+	}
+}
+
+// CompareComparators is a CompareFunc that delegates to the Compare
+// method of a Comparator.  This allows the generic datastructures to be
+// used with existing Comparator implementations.
+func CompareComparators(a, b Comparator) int {
+	return a.Compare(b)
+}
@@ -66,6 +66,12 @@ and continue this process.  This can be shown to run in O(1) amortized time
 using yet another clever potential function.  Finally, given this function,
 we can implement delete by decreasing a key to -\infty, then calling
 dequeueMin to extract it.
+
+Every entry may carry a value alongside its priority.  FibonacciHeapOf
+is parameterized over the type of that value and FloatingFibonacciHeap
+is its instantiation over interface{}.  IndexedFibHeap builds on it to
+address entries by a unique key, so an algorithm can decrease the key
+of, or delete, an element it knows only by its key.
 */
 package fibheap
 
@@ -78,23 +84,33 @@ import (
  ************** INTERFACE *****************
  ******************************************/
 
-// FloatingFibonacciHeap is an implementation of a fibonacci heap
-// with only floating-point priorities and no user data attached.
-type FloatingFibonacciHeap struct {
-	min  *Entry // The minimal element
-	size uint   // Size of the heap
+// FibonacciHeapOf is an implementation of a fibonacci heap with
+// floating-point priorities where every entry carries a value of
+// type V.
+type FibonacciHeapOf[V any] struct {
+	min  *EntryOf[V] // The minimal element
+	size uint        // Size of the heap
 }
 
-// Entry is the entry type that will be used
-// for each node of the Fibonacci heap
-type Entry struct {
+// FloatingFibonacciHeap is a fibonacci heap whose entries carry
+// interface{} values.  Entries enqueued with Enqueue carry no value.
+type FloatingFibonacciHeap = FibonacciHeapOf[interface{}]
+
+// EntryOf is the entry type that will be used
+// for each node of a FibonacciHeapOf
+type EntryOf[V any] struct {
 	degree                    int
 	marked                    bool
-	next, prev, child, parent *Entry
+	next, prev, child, parent *EntryOf[V]
 	// Priority is the numerical priority of the node
 	Priority float64
+	// Value is the user data attached to the node
+	Value V
 }
 
+// Entry is the entry type of a FloatingFibonacciHeap.
+type Entry = EntryOf[interface{}]
+
 // EmptyHeapError fires when the heap is empty and an operation could
 // not be completed for that reason. Its string holds additional data.
 type EmptyHeapError string
@@ -114,9 +130,19 @@ func (e NilError) Error() string {
 // NewFloatFibHeap creates a new, empty, Fibonacci heap object.
 func NewFloatFibHeap() FloatingFibonacciHeap { return FloatingFibonacciHeap{nil, 0} }
 
+// NewFibHeapOf creates a new, empty, Fibonacci heap object whose
+// entries carry values of type V.
+func NewFibHeapOf[V any]() FibonacciHeapOf[V] { return FibonacciHeapOf[V]{nil, 0} }
+
 // Enqueue adds and element to the heap
-func (heap *FloatingFibonacciHeap) Enqueue(priority float64) *Entry {
-	singleton := newEntry(priority)
+func (heap *FibonacciHeapOf[V]) Enqueue(priority float64) *EntryOf[V] {
+	var zero V
+	return heap.EnqueueValue(priority, zero)
+}
+
+// EnqueueValue adds an element carrying the provided value to the heap
+func (heap *FibonacciHeapOf[V]) EnqueueValue(priority float64, value V) *EntryOf[V] {
+	singleton := newEntry(priority, value)
 
 	// Merge singleton list with heap
 	heap.min = mergeLists(heap.min, singleton)
@@ -125,7 +151,7 @@ func (heap *FloatingFibonacciHeap) Enqueue(priority float64) *Entry {
 }
 
 // Min returns the minimum element in the heap
-func (heap *FloatingFibonacciHeap) Min() (*Entry, error) {
+func (heap *FibonacciHeapOf[V]) Min() (*EntryOf[V], error) {
 	if heap.IsEmpty() {
 		return nil, EmptyHeapError("Trying to get minimum element of empty heap")
 	}
@@ -133,18 +159,18 @@ func (heap *FloatingFibonacciHeap) Min() (*Entry, error) {
 }
 
 // IsEmpty answers: is the heap empty?
-func (heap *FloatingFibonacciHeap) IsEmpty() bool {
+func (heap *FibonacciHeapOf[V]) IsEmpty() bool {
 	return heap.size == 0
 }
 
 // Size gives the number of elements in the heap
-func (heap *FloatingFibonacciHeap) Size() uint {
+func (heap *FibonacciHeapOf[V]) Size() uint {
 	return heap.size
 }
 
 // DequeueMin removes and returns the
 // minimal element in the heap
-func (heap *FloatingFibonacciHeap) DequeueMin() (*Entry, error) {
+func (heap *FibonacciHeapOf[V]) DequeueMin() (*EntryOf[V], error) {
 	if heap.IsEmpty() {
 		return nil, EmptyHeapError("Cannot dequeue minimum of empty heap")
 	}
@@ -178,8 +204,8 @@ func (heap *FloatingFibonacciHeap) DequeueMin() (*Entry, error) {
 		return min, nil
 	}
 
-	treeSlice := make([]*Entry, 0, heap.size)
-	toVisit := make([]*Entry, 0, heap.size)
+	treeSlice := make([]*EntryOf[V], 0, heap.size)
+	toVisit := make([]*EntryOf[V], 0, heap.size)
 
 	for curr := heap.min; len(toVisit) == 0 || toVisit[0] != curr; curr = curr.next {
 		toVisit = append(toVisit, curr)
@@ -200,7 +226,7 @@ func (heap *FloatingFibonacciHeap) DequeueMin() (*Entry, error) {
 			treeSlice[curr.degree] = nil
 
 			// Determine which of two trees has the smaller root
-			var minT, maxT *Entry
+			var minT, maxT *EntryOf[V]
 			if other.Priority < curr.Priority {
 				minT = other
 				maxT = curr
@@ -248,7 +274,7 @@ func (heap *FloatingFibonacciHeap) DequeueMin() (*Entry, error) {
 
 // DecreaseKey decreases the key of the given element, sets it to the new
 // given priority and returns the node if successfully set
-func (heap *FloatingFibonacciHeap) DecreaseKey(node *Entry, newPriority float64) (*Entry, error) {
+func (heap *FibonacciHeapOf[V]) DecreaseKey(node *EntryOf[V], newPriority float64) (*EntryOf[V], error) {
 
 	if heap.IsEmpty() {
 		return nil, EmptyHeapError("Cannot decrease key in an empty heap")
@@ -268,7 +294,7 @@ func (heap *FloatingFibonacciHeap) DecreaseKey(node *Entry, newPriority float64)
 }
 
 // Delete deletes the given element in the heap
-func (heap *FloatingFibonacciHeap) Delete(node *Entry) error {
+func (heap *FibonacciHeapOf[V]) Delete(node *EntryOf[V]) error {
 
 	if heap.IsEmpty() {
 		return EmptyHeapError("Cannot delete element from an empty heap")
@@ -288,10 +314,10 @@ func (heap *FloatingFibonacciHeap) Delete(node *Entry) error {
 // destructively modified by having all its elements removed.  You can
 // continue to use those heaps, but be aware that they will be empty
 // after this call completes.
-func (heap *FloatingFibonacciHeap) Merge(other *FloatingFibonacciHeap) (FloatingFibonacciHeap, error) {
+func (heap *FibonacciHeapOf[V]) Merge(other *FibonacciHeapOf[V]) (FibonacciHeapOf[V], error) {
 
 	if heap == nil || other == nil {
-		return FloatingFibonacciHeap{}, NilError("One of the heaps to merge is nil. Cannot merge")
+		return FibonacciHeapOf[V]{}, NilError("One of the heaps to merge is nil. Cannot merge")
 	}
 
 	resultSize := heap.size + other.size
@@ -303,7 +329,7 @@ func (heap *FloatingFibonacciHeap) Merge(other *FloatingFibonacciHeap) (Floating
 	heap.size = 0
 	other.size = 0
 
-	return FloatingFibonacciHeap{resultMin, resultSize}, nil
+	return FibonacciHeapOf[V]{resultMin, resultSize}, nil
 }
 
 /******************************************
@@ -314,8 +340,8 @@ func (heap *FloatingFibonacciHeap) Merge(other *FloatingFibonacciHeap) (Floating
 // HELPER FUNCTIONS
 // ****************
 
-func newEntry(priority float64) *Entry {
-	result := new(Entry)
+func newEntry[V any](priority float64, value V) *EntryOf[V] {
+	result := new(EntryOf[V])
 	result.degree = 0
 	result.marked = false
 	result.child = nil
@@ -323,10 +349,11 @@ func newEntry(priority float64) *Entry {
 	result.next = result
 	result.prev = result
 	result.Priority = priority
+	result.Value = value
 	return result
 }
 
-func mergeLists(one, two *Entry) *Entry {
+func mergeLists[V any](one, two *EntryOf[V]) *EntryOf[V] {
 	if one == nil && two == nil {
 		return nil
 	} else if one != nil && two == nil {
@@ -348,7 +375,7 @@ func mergeLists(one, two *Entry) *Entry {
 
 }
 
-func decreaseKeyUnchecked(heap *FloatingFibonacciHeap, node *Entry, priority float64) {
+func decreaseKeyUnchecked[V any](heap *FibonacciHeapOf[V], node *EntryOf[V], priority float64) {
 	node.Priority = priority
 
 	if node.parent != nil && node.Priority <= node.parent.Priority {
@@ -360,7 +387,7 @@ func decreaseKeyUnchecked(heap *FloatingFibonacciHeap, node *Entry, priority flo
 	}
 }
 
-func cutNode(heap *FloatingFibonacciHeap, node *Entry) {
+func cutNode[V any](heap *FibonacciHeapOf[V], node *EntryOf[V]) {
 	node.marked = false
 
 	if node.parent == nil {
@@ -5,6 +5,7 @@ package fibheap
 import (
 	"testing"
 
+	"math"
 	"math/rand"
 
 	"github.com/stretchr/testify/assert"
@@ -338,6 +339,148 @@ func TestFibHeap_Merge_NilHeap(t *testing.T) {
 	assert.Equal(t, newHeap, FloatingFibonacciHeap{})
 }
 
+func TestFibHeapOf_EnqueueValue(t *testing.T) {
+	heap := NewFibHeapOf[string]()
+	heap.EnqueueValue(3, "c")
+	b := heap.EnqueueValue(2, "b")
+	heap.EnqueueValue(1, "a")
+	heap.Enqueue(4)
+
+	_, err := heap.DecreaseKey(b, 0)
+	require.NoError(t, err)
+
+	values := make([]string, 0, 4)
+	for !heap.IsEmpty() {
+		min, err := heap.DequeueMin()
+		require.NoError(t, err)
+		values = append(values, min.Value)
+	}
+
+	assert.Equal(t, []string{"b", "a", "c", ""}, values)
+}
+
+func TestIndexedFibHeap(t *testing.T) {
+	heap := NewIndexedFibHeap[int]()
+	for i := 0; i < len(NumberSequence2); i++ {
+		entry, err := heap.Enqueue(i, NumberSequence2[i])
+		require.NoError(t, err)
+		assert.Equal(t, i, entry.Value)
+	}
+	assert.Equal(t, uint(len(NumberSequence2)), heap.Size())
+
+	for i := 0; i < len(NumberSequence2); i++ {
+		switch NumberSequence2[i] {
+		case Seq2DecreaseKey1Orig:
+			_, err := heap.DecreaseKeyByID(i, Seq2DecreaseKey1Trgt)
+			require.NoError(t, err)
+		case Seq2DecreaseKey2Orig:
+			_, err := heap.DecreaseKeyByID(i, Seq2DecreaseKey2Trgt)
+			require.NoError(t, err)
+		case Seq2DecreaseKey3Orig:
+			_, err := heap.DecreaseKeyByID(i, Seq2DecreaseKey3Trgt)
+			require.NoError(t, err)
+		}
+	}
+
+	for i := 0; i < len(NumberSequence2Sorted); i++ {
+		min, err := heap.DequeueMin()
+		require.NoError(t, err)
+		assert.Equal(t, NumberSequence2Sorted[i], min.Priority)
+		assert.False(t, heap.Contains(min.Value))
+	}
+	assert.True(t, heap.IsEmpty())
+}
+
+func TestIndexedFibHeap_DeleteByID(t *testing.T) {
+	heap := NewIndexedFibHeap[string]()
+	heap.Enqueue("a", 1)
+	heap.Enqueue("b", 2)
+	heap.Enqueue("c", 3)
+
+	require.NoError(t, heap.DeleteByID("a"))
+	assert.False(t, heap.Contains("a"))
+	assert.Equal(t, uint(2), heap.Size())
+
+	entry, ok := heap.Entry("c")
+	require.True(t, ok)
+	assert.Equal(t, 3.0, entry.Priority)
+
+	min, err := heap.DequeueMin()
+	require.NoError(t, err)
+	assert.Equal(t, "b", min.Value)
+
+	// a deleted key may be enqueued again
+	_, err = heap.Enqueue("a", 0)
+	require.NoError(t, err)
+	min, err = heap.Min()
+	require.NoError(t, err)
+	assert.Equal(t, "a", min.Value)
+}
+
+func TestIndexedFibHeap_KeyErrors(t *testing.T) {
+	heap := NewIndexedFibHeap[string]()
+	heap.Enqueue("a", 1)
+
+	entry, err := heap.Enqueue("a", 0)
+	assert.IsType(t, KeyError(""), err)
+	assert.EqualError(t, err, "Cannot enqueue key a: key already in heap")
+	assert.Nil(t, entry)
+
+	entry, err = heap.DecreaseKeyByID("b", 0)
+	assert.IsType(t, KeyError(""), err)
+	assert.EqualError(t, err, "Cannot decrease key b: key not in heap")
+	assert.Nil(t, entry)
+
+	err = heap.DeleteByID("b")
+	assert.IsType(t, KeyError(""), err)
+	assert.EqualError(t, err, "Cannot delete key b: key not in heap")
+
+	entry, err = heap.DecreaseKeyByID("a", 2)
+	assert.EqualError(t, err, "The given new priority: 2, is larger than or equal to the old: 1")
+	assert.Nil(t, entry)
+
+	_, ok := heap.Entry("b")
+	assert.False(t, ok)
+	assert.Equal(t, uint(1), heap.Size())
+}
+
+func TestIndexedFibHeap_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	heap := NewIndexedFibHeap[int]()
+	priorities := make(map[int]float64)
+	for i := 0; i < 1000; i++ {
+		priorities[i] = r.Float64() * 1000
+		heap.Enqueue(i, priorities[i])
+	}
+
+	for i := 0; i < 500; i++ {
+		id := r.Intn(1000)
+		if _, ok := priorities[id]; !ok {
+			continue
+		}
+		if r.Intn(2) == 0 {
+			require.NoError(t, heap.DeleteByID(id))
+			delete(priorities, id)
+		} else {
+			priorities[id] -= r.Float64()*100 + 1
+			_, err := heap.DecreaseKeyByID(id, priorities[id])
+			require.NoError(t, err)
+		}
+	}
+
+	require.Equal(t, uint(len(priorities)), heap.Size())
+	last := -math.MaxFloat64
+	for !heap.IsEmpty() {
+		min, err := heap.DequeueMin()
+		require.NoError(t, err)
+		assert.Equal(t, priorities[min.Value], min.Priority)
+		assert.True(t, min.Priority >= last)
+		last = min.Priority
+		delete(priorities, min.Value)
+	}
+	assert.Len(t, priorities, 0)
+}
+
 // ***************
 // BENCHMARK TESTS
 // ***************
@@ -0,0 +1,123 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fibheap
+
+import "fmt"
+
+// KeyError fires when an operation on an IndexedFibHeap references
+// a key that is missing from the heap or, when enqueueing, a key that
+// is already present. Its string holds additional data.
+type KeyError string
+
+func (e KeyError) Error() string {
+	return string(e)
+}
+
+// IndexedFibHeap is a Fibonacci heap whose entries are addressed by a
+// unique key rather than by *Entry.  It tracks the entry of every key
+// in the heap so algorithms like Dijkstra's or Prim's, which only know
+// the vertex whose distance changed, can decrease its key or delete it
+// directly.  The key of each entry is stored as its Value.
+type IndexedFibHeap[K comparable] struct {
+	heap    FibonacciHeapOf[K]
+	entries map[K]*EntryOf[K]
+}
+
+// NewIndexedFibHeap creates a new, empty, key-indexed Fibonacci heap.
+func NewIndexedFibHeap[K comparable]() *IndexedFibHeap[K] {
+	return &IndexedFibHeap[K]{
+		heap:    NewFibHeapOf[K](),
+		entries: make(map[K]*EntryOf[K]),
+	}
+}
+
+// Enqueue adds the given key to the heap with the given priority and
+// returns its entry.  Returns a KeyError if the key is already in the
+// heap.
+func (heap *IndexedFibHeap[K]) Enqueue(id K, priority float64) (*EntryOf[K], error) {
+	if _, ok := heap.entries[id]; ok {
+		return nil, KeyError(fmt.Sprintf("Cannot enqueue key %v: key already in heap", id))
+	}
+
+	entry := heap.heap.EnqueueValue(priority, id)
+	heap.entries[id] = entry
+	return entry, nil
+}
+
+// Min returns the entry with the minimum priority without removing it.
+func (heap *IndexedFibHeap[K]) Min() (*EntryOf[K], error) {
+	return heap.heap.Min()
+}
+
+// DequeueMin removes and returns the entry with the minimum priority.
+func (heap *IndexedFibHeap[K]) DequeueMin() (*EntryOf[K], error) {
+	min, err := heap.heap.DequeueMin()
+	if err != nil {
+		return nil, err
+	}
+
+	delete(heap.entries, min.Value)
+	return min, nil
+}
+
+// DecreaseKeyByID decreases the priority of the entry with the given key
+// to the new given priority and returns the entry if successfully set.
+// Like DecreaseKey, the new priority must be smaller than the old.
+func (heap *IndexedFibHeap[K]) DecreaseKeyByID(id K, newPriority float64) (*EntryOf[K], error) {
+	entry, ok := heap.entries[id]
+	if !ok {
+		return nil, KeyError(fmt.Sprintf("Cannot decrease key %v: key not in heap", id))
+	}
+
+	return heap.heap.DecreaseKey(entry, newPriority)
+}
+
+// DeleteByID deletes the entry with the given key from the heap.
+func (heap *IndexedFibHeap[K]) DeleteByID(id K) error {
+	entry, ok := heap.entries[id]
+	if !ok {
+		return KeyError(fmt.Sprintf("Cannot delete key %v: key not in heap", id))
+	}
+
+	if err := heap.heap.Delete(entry); err != nil {
+		return err
+	}
+
+	delete(heap.entries, id)
+	return nil
+}
+
+// Entry returns the entry of the given key and a bool indicating if
+// the key is in the heap.
+func (heap *IndexedFibHeap[K]) Entry(id K) (*EntryOf[K], bool) {
+	entry, ok := heap.entries[id]
+	return entry, ok
+}
+
+// Contains returns a bool indicating if the given key is in the heap.
+func (heap *IndexedFibHeap[K]) Contains(id K) bool {
+	_, ok := heap.entries[id]
+	return ok
+}
+
+// IsEmpty returns true if the heap is empty
+func (heap *IndexedFibHeap[K]) IsEmpty() bool {
+	return heap.heap.IsEmpty()
+}
+
+// Size gives the number of elements in the heap
+func (heap *IndexedFibHeap[K]) Size() uint {
+	return heap.heap.Size()
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoFutures signals that a future combining others was given none to
+// wait on.
+var ErrNoFutures = errors.New("no futures given")
+
+// ErrThenPanicked signals that the function given to Then or Map panicked
+// instead of returning a result.
+var ErrThenPanicked = errors.New("then function panicked")
+
+// whenFilled calls fn with the result of f once it is filled, unless done is
+// filled first, in which case fn is never called and is no longer held by f.
+// fn runs in the goroutine filling f.
+func whenFilled(f, done *Selectable, fn func(interface{}, error)) {
+	stop := f.OnComplete(func(value interface{}, err error) {
+		if atomic.LoadUint32(&done.filled) == 0 {
+			fn(value, err)
+		}
+	})
+	done.OnComplete(func(interface{}, error) {
+		stop()
+	})
+}
+
+// All returns a future that is filled with the values of the given futures,
+// as an []interface{} in the same order, once all of them are filled.  It
+// fails as soon as any of them fails, with that future's error.  Filling or
+// canceling the returned future early does not affect the given futures.
+func All(futures ...*Selectable) *Selectable {
+	result := NewSelectable()
+	if len(futures) == 0 {
+		result.SetValue([]interface{}{})
+		return result
+	}
+
+	values := make([]interface{}, len(futures))
+	remaining := int32(len(futures))
+	for i, f := range futures {
+		i := i
+		whenFilled(f, result, func(value interface{}, err error) {
+			if err != nil {
+				result.SetError(err)
+				return
+			}
+
+			values[i] = value
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				result.SetValue(values)
+			}
+		})
+	}
+
+	return result
+}
+
+// Any returns a future that is filled with the value of the first of the
+// given futures to be filled without an error.  If all of them fail it fails
+// with their errors joined in the order the futures were given, and if none
+// are given it fails with ErrNoFutures.  Filling or canceling the returned
+// future early does not affect the given futures.
+func Any(futures ...*Selectable) *Selectable {
+	result := NewSelectable()
+	if len(futures) == 0 {
+		result.SetError(ErrNoFutures)
+		return result
+	}
+
+	errs := make([]error, len(futures))
+	remaining := int32(len(futures))
+	for i, f := range futures {
+		i := i
+		whenFilled(f, result, func(value interface{}, err error) {
+			if err == nil {
+				result.SetValue(value)
+				return
+			}
+
+			errs[i] = err
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				result.SetError(errors.Join(errs...))
+			}
+		})
+	}
+
+	return result
+}
+
+// First returns a future that is filled with the value or error of the first
+// of the given futures to be filled.  If none are given it fails with
+// ErrNoFutures.  Filling or canceling the returned future early does not
+// affect the given futures.
+func First(futures ...*Selectable) *Selectable {
+	result := NewSelectable()
+	if len(futures) == 0 {
+		result.SetError(ErrNoFutures)
+		return result
+	}
+
+	for _, f := range futures {
+		whenFilled(f, result, func(value interface{}, err error) {
+			result.Fill(value, err)
+		})
+	}
+
+	return result
+}
+
+// Then returns a future that is filled with the result of calling fn with
+// the value of the given future once it is filled.  If the given future fails,
+// fn is not called and the returned future fails with the same error.  fn is
+// not called either if the returned future was already filled or canceled.
+// fn runs synchronously as an OnComplete callback of the given future: in the
+// goroutine that fills it, before that Fill returns, or in the calling
+// goroutine if the given future is already filled.  fn should not block.  If
+// it panics the returned future fails with ErrThenPanicked and the panic is
+// raised again from that Fill or from Then.
+func Then(f *Selectable, fn func(interface{}) (interface{}, error)) *Selectable {
+	result := NewSelectable()
+	whenFilled(f, result, func(value interface{}, err error) {
+		if err != nil {
+			result.SetError(err)
+			return
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				result.SetError(ErrThenPanicked)
+				panic(r)
+			}
+		}()
+		result.Fill(fn(value))
+	})
+
+	return result
+}
+
+// Map returns a future that is filled with the result of calling fn with the
+// value of the given future once it is filled.  If the given future fails, fn
+// is not called and the returned future fails with the same error.  fn runs
+// synchronously in the same way as for Then.
+func Map(f *Selectable, fn func(interface{}) interface{}) *Selectable {
+	return Then(f, func(value interface{}) (interface{}, error) {
+		return fn(value), nil
+	})
+}
+
+// WithContext fails the given future with the context's error if the context
+// is done before the future is filled, and returns the future.
+func WithContext(ctx context.Context, f *Selectable) *Selectable {
+	stop := context.AfterFunc(ctx, func() {
+		f.SetError(ctx.Err())
+	})
+	f.OnComplete(func(interface{}, error) {
+		stop()
+	})
+
+	return f
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSelectables returns n unfilled selectable futures.
+func newSelectables(n int) []*Selectable {
+	futures := make([]*Selectable, n)
+	for i := range futures {
+		futures[i] = NewSelectable()
+	}
+	return futures
+}
+
+// assertPending asserts that f is not filled shortly after.
+func assertPending(t *testing.T, f *Selectable) {
+	select {
+	case <-f.WaitChan():
+		t.Fatal("future filled early")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestAll(t *testing.T) {
+	futures := newSelectables(3)
+	all := All(futures...)
+
+	futures[2].SetValue(`c`)
+	futures[0].SetValue(`a`)
+	assertPending(t, all)
+
+	futures[1].SetValue(`b`)
+	result, err := all.GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{`a`, `b`, `c`}, result)
+}
+
+func TestAllFailsFast(t *testing.T) {
+	futures := newSelectables(3)
+	all := All(futures...)
+
+	futures[0].SetValue(`a`)
+	futures[1].SetError(fmt.Errorf("boom"))
+	result, err := all.GetResult()
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "boom")
+
+	// the other futures are left alone
+	futures[2].SetValue(`c`)
+	result, err = futures[2].GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, `c`, result)
+}
+
+func TestAllEmpty(t *testing.T) {
+	result, err := All().GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{}, result)
+}
+
+func TestAny(t *testing.T) {
+	futures := newSelectables(3)
+	first := Any(futures...)
+
+	futures[0].SetError(fmt.Errorf("boom"))
+	assertPending(t, first)
+
+	futures[2].SetValue(`c`)
+	result, err := first.GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, `c`, result)
+}
+
+func TestAnyAllFail(t *testing.T) {
+	futures := newSelectables(2)
+	errA, errB := errors.New("a"), errors.New("b")
+	first := Any(futures...)
+
+	futures[1].SetError(errB)
+	futures[0].SetError(errA)
+	_, err := first.GetResult()
+	assert.True(t, errors.Is(err, errA))
+	assert.True(t, errors.Is(err, errB))
+	assert.Equal(t, "a\nb", err.Error())
+
+	_, err = Any().GetResult()
+	assert.Equal(t, ErrNoFutures, err)
+}
+
+func TestFirst(t *testing.T) {
+	futures := newSelectables(2)
+	first := First(futures...)
+	assertPending(t, first)
+
+	futures[1].SetError(fmt.Errorf("boom"))
+	_, err := first.GetResult()
+	assert.EqualError(t, err, "boom")
+
+	futures[0].SetValue(`a`)
+	_, err = first.GetResult()
+	assert.EqualError(t, err, "boom")
+
+	_, err = First().GetResult()
+	assert.Equal(t, ErrNoFutures, err)
+}
+
+func TestCombinatorsReleaseCallbacks(t *testing.T) {
+	// a future that is never filled does not hold on to the callbacks of
+	// combined futures that are already filled
+	pending := NewSelectable()
+	for i := 0; i < 10; i++ {
+		filled := NewSelectable()
+		filled.SetValue(i)
+		First(pending, filled)
+		Any(filled, pending)
+		All(pending, NewSelectable()).Cancel()
+		Map(pending, nil).Cancel()
+	}
+
+	assert.Equal(t, 0, pending.callbacks.Len())
+}
+
+func TestThen(t *testing.T) {
+	f := NewSelectable()
+	then := Then(f, func(value interface{}) (interface{}, error) {
+		return value.(int) * 2, nil
+	})
+	mapped := Map(then, func(value interface{}) interface{} {
+		return fmt.Sprint(value)
+	})
+
+	f.SetValue(21)
+	result, err := mapped.GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, `42`, result)
+
+	f = NewSelectable()
+	then = Then(f, func(value interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("bad value %v", value)
+	})
+	f.SetValue(1)
+	_, err = then.GetResult()
+	assert.EqualError(t, err, "bad value 1")
+}
+
+func TestThenPanic(t *testing.T) {
+	f := NewSelectable()
+	mapped := Map(f, func(interface{}) interface{} {
+		panic("boom")
+	})
+
+	assert.PanicsWithValue(t, "boom", func() {
+		f.SetValue(1)
+	})
+	_, err := mapped.GetResult()
+	assert.Equal(t, ErrThenPanicked, err)
+
+	// fn runs in Then itself once the given future is filled
+	var then *Selectable
+	assert.PanicsWithValue(t, "boom", func() {
+		then = Then(f, func(interface{}) (interface{}, error) {
+			panic("boom")
+		})
+	})
+	assert.Nil(t, then)
+}
+
+func TestThenPropagatesError(t *testing.T) {
+	f := NewSelectable()
+	called := false
+	mapped := Map(f, func(value interface{}) interface{} {
+		called = true
+		return value
+	})
+
+	f.Cancel()
+	_, err := mapped.GetResult()
+	assert.Equal(t, ErrFutureCanceled, err)
+	assert.False(t, called)
+}
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := WithContext(ctx, NewSelectable())
+	assertPending(t, f)
+
+	cancel()
+	_, err := f.GetResult()
+	assert.Equal(t, context.Canceled, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = WithContext(ctx, NewSelectable()).GetResult()
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestWithContextFilled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := WithContext(ctx, NewSelectable())
+	f.SetValue(`a`)
+	cancel()
+
+	result, err := f.GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, `a`, result)
+
+	f = WithContext(context.Background(), NewSelectable())
+	assertPending(t, f)
+}
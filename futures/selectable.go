@@ -18,6 +18,7 @@ limitations under the License.
 package futures
 
 import (
+	"container/list"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -32,11 +33,12 @@ var ErrFutureCanceled = errors.New("future canceled")
 // fulfilled.
 // Selectable contains sync.Mutex, so it is not movable/copyable.
 type Selectable struct {
-	m      sync.Mutex
-	val    interface{}
-	err    error
-	wait   chan struct{}
-	filled uint32
+	m         sync.Mutex
+	val       interface{}
+	err       error
+	wait      chan struct{}
+	filled    uint32
+	callbacks *list.List // Callbacks waiting for the future, in order
 }
 
 // NewSelectable returns new selectable future.
@@ -75,7 +77,9 @@ func (f *Selectable) GetResult() (interface{}, error) {
 
 // Fill sets value for future, if it were not already fullfilled
 // Returns error, if it were already set to future.
+// Callbacks registered with OnComplete run before Fill returns.
 func (f *Selectable) Fill(v interface{}, e error) error {
+	var callbacks []func(interface{}, error)
 	f.m.Lock()
 	if f.filled == 0 {
 		f.val = v
@@ -86,11 +90,82 @@ func (f *Selectable) Fill(v interface{}, e error) error {
 		if w != nil {
 			close(w)
 		}
+		if f.callbacks != nil {
+			for element := f.callbacks.Front(); element != nil; element = element.Next() {
+				callbacks = append(callbacks, element.Value.(func(interface{}, error)))
+			}
+		}
+		f.callbacks = nil
 	}
 	f.m.Unlock()
+
+	runCallbacks(callbacks, f.val, f.err)
 	return f.err
 }
 
+// OnComplete registers a callback to be called exactly once with the value
+// and error of the future once it is fullfilled.  Callbacks registered before
+// that run in the order they were registered, in the goroutine fullfilling
+// the future, and callbacks registered after run immediately in the
+// registering goroutine.  Either way the future is fullfilled by the time a
+// callback runs.  A callback that panics does not stop the others from
+// running; the first panic is raised again once they all have.
+// Calling the returned stop function deregisters the callback, so it is no
+// longer held by the future.  stop returns true if it kept the callback from
+// running and false if the callback has already run or been stopped.
+func (f *Selectable) OnComplete(callback func(interface{}, error)) (stop func() bool) {
+	f.m.Lock()
+	if f.filled == 0 {
+		if f.callbacks == nil {
+			f.callbacks = list.New()
+		}
+		callbacks := f.callbacks
+		element := callbacks.PushBack(callback)
+		f.m.Unlock()
+
+		var stopped bool
+		return func() bool {
+			f.m.Lock()
+			defer f.m.Unlock()
+			// Fill takes the list of callbacks, so the callback is only
+			// still on it if the future was not fullfilled
+			if stopped || callbacks != f.callbacks {
+				return false
+			}
+			callbacks.Remove(element)
+			stopped = true
+			return true
+		}
+	}
+	f.m.Unlock()
+
+	runCallbacks([]func(interface{}, error){callback}, f.val, f.err)
+	return func() bool {
+		return false
+	}
+}
+
+// runCallbacks calls every callback with the given value and error, then
+// raises the first panic among them, if any, again.
+func runCallbacks(callbacks []func(interface{}, error), v interface{}, e error) {
+	var panicked bool
+	var recovered interface{}
+	for _, callback := range callbacks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil && !panicked {
+					panicked, recovered = true, r
+				}
+			}()
+			callback(v, e)
+		}()
+	}
+
+	if panicked {
+		panic(recovered)
+	}
+}
+
 // SetValue is alias for Fill(v, nil)
 func (f *Selectable) SetValue(v interface{}) error {
 	return f.Fill(v, nil)
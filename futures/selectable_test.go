@@ -20,6 +20,7 @@ package futures
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -64,6 +65,126 @@ func TestSelectableSetError(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestSelectableOnComplete(t *testing.T) {
+	f := NewSelectable()
+	calls := []string{}
+	f.OnComplete(func(value interface{}, err error) {
+		// the future is already filled
+		result, _ := f.GetResult()
+		calls = append(calls, fmt.Sprintf("first %v %v %v", value, err, result))
+	})
+	f.OnComplete(func(value interface{}, err error) {
+		calls = append(calls, fmt.Sprintf("second %v %v", value, err))
+	})
+	assert.Empty(t, calls)
+
+	f.SetValue(`test`)
+	assert.Equal(t, []string{"first test <nil> test", "second test <nil>"}, calls)
+
+	// callbacks only run once
+	f.SetValue(`other`)
+	f.Cancel()
+	assert.Len(t, calls, 2)
+
+	// callbacks registered after completion run immediately
+	f.OnComplete(func(value interface{}, err error) {
+		calls = append(calls, fmt.Sprintf("late %v %v", value, err))
+	})
+	assert.Equal(t, "late test <nil>", calls[2])
+}
+
+func TestSelectableOnCompleteCancel(t *testing.T) {
+	f := NewSelectable()
+	var err error
+	f.OnComplete(func(_ interface{}, e error) {
+		err = e
+	})
+
+	f.Cancel()
+	assert.Equal(t, ErrFutureCanceled, err)
+}
+
+func TestSelectableOnCompletePanic(t *testing.T) {
+	f := NewSelectable()
+	calls := 0
+	f.OnComplete(func(interface{}, error) {
+		calls++
+		panic("first")
+	})
+	f.OnComplete(func(interface{}, error) {
+		calls++
+		panic("second")
+	})
+	f.OnComplete(func(interface{}, error) {
+		calls++
+	})
+
+	assert.PanicsWithValue(t, "first", func() {
+		f.SetValue(`test`)
+	})
+	assert.Equal(t, 3, calls)
+
+	// the future is filled despite the panics
+	result, err := f.GetResult()
+	assert.Nil(t, err)
+	assert.Equal(t, `test`, result)
+
+	assert.PanicsWithValue(t, "late", func() {
+		f.OnComplete(func(interface{}, error) {
+			panic("late")
+		})
+	})
+}
+
+func TestSelectableOnCompleteStop(t *testing.T) {
+	f := NewSelectable()
+	var calls []string
+	stopA := f.OnComplete(func(interface{}, error) {
+		calls = append(calls, "a")
+	})
+	f.OnComplete(func(interface{}, error) {
+		calls = append(calls, "b")
+	})
+	stopC := f.OnComplete(func(interface{}, error) {
+		calls = append(calls, "c")
+	})
+
+	assert.True(t, stopA())
+	assert.False(t, stopA())
+	assert.Equal(t, 2, f.callbacks.Len())
+
+	f.SetValue(`test`)
+	assert.Equal(t, []string{"b", "c"}, calls)
+	assert.False(t, stopC())
+
+	stop := f.OnComplete(func(interface{}, error) {
+		calls = append(calls, "d")
+	})
+	assert.Equal(t, []string{"b", "c", "d"}, calls)
+	assert.False(t, stop())
+}
+
+func TestSelectableOnCompleteConcurrent(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		f := NewSelectable()
+		var calls int32
+		var wg sync.WaitGroup
+		for j := 0; j < 10; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				f.OnComplete(func(interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+				})
+			}()
+		}
+
+		f.SetValue(i)
+		wg.Wait()
+		assert.Equal(t, int32(10), atomic.LoadInt32(&calls))
+	}
+}
+
 func BenchmarkSelectable(b *testing.B) {
 	timeout := time.After(30 * time.Minute)
 	var wg sync.WaitGroup
@@ -0,0 +1,192 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+// disjointSet is a union-find structure over vertices using path
+// compression and union by rank, so any sequence of operations runs in
+// nearly linear time.
+type disjointSet struct {
+	parent map[interface{}]interface{}
+	rank   map[interface{}]int
+}
+
+// add puts v in a set of its own if it is not in any set yet.
+func (ds *disjointSet) add(v interface{}) {
+	if _, ok := ds.parent[v]; !ok {
+		ds.parent[v] = v
+	}
+}
+
+// find returns the representative of the set holding v.
+func (ds *disjointSet) find(v interface{}) interface{} {
+	root := v
+	for ds.parent[root] != root {
+		root = ds.parent[root]
+	}
+
+	for v != root {
+		v, ds.parent[v] = ds.parent[v], root
+	}
+	return root
+}
+
+// union merges the sets holding v and w and returns a bool indicating
+// if they were different sets.
+func (ds *disjointSet) union(v, w interface{}) bool {
+	v, w = ds.find(v), ds.find(w)
+	if v == w {
+		return false
+	}
+
+	if ds.rank[v] < ds.rank[w] {
+		v, w = w, v
+	}
+	ds.parent[w] = v
+	if ds.rank[v] == ds.rank[w] {
+		ds.rank[v]++
+	}
+	return true
+}
+
+func newDisjointSet(hint int) *disjointSet {
+	return &disjointSet{
+		parent: make(map[interface{}]interface{}, hint),
+		rank:   make(map[interface{}]int),
+	}
+}
+
+// ConnectedComponents returns the vertices of the provided graph grouped
+// into connected components, using a union-find over its edges.  The
+// direction of edges is ignored, so for a directed graph these are its
+// weakly connected components.
+func ConnectedComponents(g Graph) ([][]interface{}, error) {
+	vertices := g.Vertices()
+	ds := newDisjointSet(len(vertices))
+	for _, v := range vertices {
+		ds.add(v)
+	}
+
+	for _, v := range vertices {
+		adj, err := g.Adj(v)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range adj {
+			ds.add(w)
+			ds.union(v, w)
+		}
+	}
+
+	indices := make(map[interface{}]int)
+	components := make([][]interface{}, 0)
+	for _, v := range vertices {
+		root := ds.find(v)
+		i, ok := indices[root]
+		if !ok {
+			i = len(components)
+			indices[root] = i
+			components = append(components, nil)
+		}
+		components[i] = append(components[i], v)
+	}
+
+	return components, nil
+}
+
+// StronglyConnectedComponents returns the vertices of the provided
+// directed graph grouped into strongly connected components, in which
+// every vertex has a path to every other, using Tarjan's algorithm.
+// This runs in O(V + E) time.  Components are returned in reverse
+// topological order: no edge leads from a component to one earlier in
+// the list.
+func StronglyConnectedComponents(g Graph) ([][]interface{}, error) {
+	index := make(map[interface{}]int)
+	low := make(map[interface{}]int)
+	onStack := make(map[interface{}]bool)
+	stack := make([]interface{}, 0)
+	components := make([][]interface{}, 0)
+
+	var path []*dfsFrame
+	visit := func(v interface{}) error {
+		adj, err := g.Adj(v)
+		if err != nil {
+			return err
+		}
+
+		index[v] = len(index)
+		low[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		path = append(path, &dfsFrame{v: v, adj: adj})
+		return nil
+	}
+
+	for _, root := range g.Vertices() {
+		if _, ok := index[root]; ok {
+			continue
+		}
+
+		if err := visit(root); err != nil {
+			return nil, err
+		}
+
+		for len(path) > 0 {
+			frame := path[len(path)-1]
+			if frame.i < len(frame.adj) {
+				w := frame.adj[frame.i]
+				frame.i++
+				if _, ok := index[w]; !ok {
+					if err := visit(w); err != nil {
+						return nil, err
+					}
+				} else if onStack[w] {
+					low[frame.v] = min(low[frame.v], index[w])
+				}
+				continue
+			}
+
+			v := frame.v
+			path = path[:len(path)-1]
+			if len(path) > 0 {
+				parent := path[len(path)-1].v
+				low[parent] = min(low[parent], low[v])
+			}
+
+			if low[v] != index[v] {
+				continue
+			}
+
+			// v is the root of a component made up of itself and every
+			// vertex above it on the stack
+			i := len(stack) - 1
+			for stack[i] != v {
+				i--
+			}
+
+			component := make([]interface{}, len(stack)-i)
+			copy(component, stack[i:])
+			for _, w := range component {
+				onStack[w] = false
+			}
+			stack = stack[:i]
+			components = append(components, component)
+		}
+	}
+
+	return components, nil
+}
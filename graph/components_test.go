@@ -0,0 +1,203 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisjointSet(t *testing.T) {
+	assert := assert.New(t)
+	ds := newDisjointSet(0)
+	for i := 0; i < 10; i++ {
+		ds.add(i)
+	}
+
+	assert.True(ds.union(0, 1))
+	assert.True(ds.union(2, 3))
+	assert.True(ds.union(1, 3))
+	assert.False(ds.union(0, 2))
+	assert.Equal(ds.find(0), ds.find(3))
+	assert.NotEqual(ds.find(0), ds.find(4))
+
+	// adding again does not split a set
+	ds.add(3)
+	assert.Equal(ds.find(0), ds.find(3))
+}
+
+func TestConnectedComponents(t *testing.T) {
+	assert := assert.New(t)
+	sgraph := NewSimpleGraph()
+	sgraph.AddEdge("A", "B")
+	sgraph.AddEdge("B", "C")
+	sgraph.AddEdge("D", "E")
+	sgraph.AddEdge("F", "G")
+	sgraph.AddEdge("G", "D")
+
+	components, err := ConnectedComponents(sgraph)
+	require.Nil(t, err)
+	assert.Len(components, 2)
+	assert.ElementsMatch([][]interface{}{
+		{"A", "B", "C"}, {"D", "E", "F", "G"},
+	}, sortComponents(components))
+}
+
+func TestConnectedComponentsDirected(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("C", "B", 1)
+	dgraph.AddEdge("D", "D", 1)
+	dgraph.AddVertex("E")
+
+	components, err := ConnectedComponents(dgraph)
+	require.Nil(t, err)
+	assert.ElementsMatch([][]interface{}{
+		{"A", "B", "C"}, {"D"}, {"E"},
+	}, sortComponents(components))
+
+	components, err = ConnectedComponents(NewDirectedGraph())
+	assert.Nil(err)
+	assert.Empty(components)
+}
+
+// sortComponents sorts the vertices of every component, which must be
+// strings, so components can be compared.
+func sortComponents(components [][]interface{}) [][]interface{} {
+	for _, c := range components {
+		for i := 1; i < len(c); i++ {
+			for j := i; j > 0 && c[j].(string) < c[j-1].(string); j-- {
+				c[j], c[j-1] = c[j-1], c[j]
+			}
+		}
+	}
+	return components
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	// {A, B, C} -> {D, E} -> {F}, {G} -> {F}
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("B", "C", 1)
+	dgraph.AddEdge("C", "A", 1)
+	dgraph.AddEdge("C", "D", 1)
+	dgraph.AddEdge("D", "E", 1)
+	dgraph.AddEdge("E", "D", 1)
+	dgraph.AddEdge("E", "F", 1)
+	dgraph.AddEdge("G", "F", 1)
+
+	components, err := StronglyConnectedComponents(dgraph)
+	require.Nil(t, err)
+	checkSCCOrder(t, dgraph, components)
+	assert.ElementsMatch([][]interface{}{
+		{"A", "B", "C"}, {"D", "E"}, {"F"}, {"G"},
+	}, sortComponents(components))
+}
+
+func TestStronglyConnectedComponentsSimpleGraph(t *testing.T) {
+	sgraph := NewSimpleGraph()
+	sgraph.AddEdge("A", "B")
+	sgraph.AddEdge("B", "C")
+	sgraph.AddEdge("D", "E")
+
+	components, err := StronglyConnectedComponents(sgraph)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, [][]interface{}{
+		{"A", "B", "C"}, {"D", "E"},
+	}, sortComponents(components))
+}
+
+// checkSCCOrder checks that no edge leads from a component to an
+// earlier one.
+func checkSCCOrder(t *testing.T, dgraph *DirectedGraph, components [][]interface{}) {
+	position := make(map[interface{}]int)
+	for i, c := range components {
+		for _, v := range c {
+			position[v] = i
+		}
+	}
+
+	require.Len(t, position, dgraph.V())
+	for _, e := range dgraph.Edges() {
+		assert.True(t, position[e.From] >= position[e.To], "%v -> %v", e.From, e.To)
+	}
+}
+
+// reachable returns the set of vertices reachable from v.
+func reachable(dgraph *DirectedGraph, v interface{}) map[interface{}]bool {
+	seen := make(map[interface{}]bool)
+	DFS(dgraph, v, func(w interface{}) bool {
+		seen[w] = true
+		return true
+	})
+	return seen
+}
+
+func TestStronglyConnectedComponentsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		n := 40
+		dgraph := NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			dgraph.AddVertex(i)
+		}
+		for i := 0; i < 60; i++ {
+			dgraph.AddEdge(r.Intn(n), r.Intn(n), 1)
+		}
+
+		components, err := StronglyConnectedComponents(dgraph)
+		require.Nil(t, err)
+		checkSCCOrder(t, dgraph, components)
+
+		reach := make([]map[interface{}]bool, n)
+		for i := range reach {
+			reach[i] = reachable(dgraph, i)
+		}
+
+		component := make(map[interface{}]int)
+		for i, c := range components {
+			for _, v := range c {
+				component[v] = i
+			}
+		}
+
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				same := reach[i][j] && reach[j][i]
+				assert.Equal(t, same, component[i] == component[j], "%d %d", i, j)
+			}
+		}
+	}
+}
+
+func TestStronglyConnectedComponentsLong(t *testing.T) {
+	dgraph := NewDirectedGraph()
+	for i := 0; i < 10000; i++ {
+		dgraph.AddEdge(i, i+1, 1)
+	}
+	dgraph.AddEdge(10000, 5000, 1)
+
+	components, err := StronglyConnectedComponents(dgraph)
+	require.Nil(t, err)
+	assert.Len(t, components, 5001)
+	checkSCCOrder(t, dgraph, components)
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import "sync"
+
+// Edge is a weighted edge leading from one vertex to another.
+type Edge struct {
+	From, To interface{}
+	Weight   float64
+}
+
+// DirectedGraph is a mutable, non-persistent directed graph whose edges
+// carry a float64 weight.  Self-loops are permitted but parallel edges
+// are not.
+// Additional description: https://en.wikipedia.org/wiki/Directed_graph
+type DirectedGraph struct {
+	mutex sync.RWMutex
+	// out maps each vertex to its successors and in to its predecessors,
+	// both with the weight of the connecting edge.
+	out, in map[interface{}]map[interface{}]float64
+	e       int
+}
+
+// V returns the number of vertices in the DirectedGraph
+func (g *DirectedGraph) V() int {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return len(g.out)
+}
+
+// E returns the number of edges in the DirectedGraph
+func (g *DirectedGraph) E() int {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return g.e
+}
+
+// AddVertex will add the vertex v to the graph if it does not already
+// exist.
+func (g *DirectedGraph) AddVertex(v interface{}) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.addVertex(v)
+}
+
+// AddEdge will create an edge of the given weight leading from vertex v
+// to vertex w, adding either vertex if it does not already exist.
+func (g *DirectedGraph) AddEdge(v, w interface{}, weight float64) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.addVertex(v)
+	g.addVertex(w)
+
+	if _, ok := g.out[v][w]; ok {
+		return ErrParallelEdge
+	}
+
+	g.out[v][w] = weight
+	g.in[w][v] = weight
+	g.e++
+	return nil
+}
+
+// SetWeight changes the weight of the edge leading from v to w.
+func (g *DirectedGraph) SetWeight(v, w interface{}, weight float64) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.out[v][w]; !ok {
+		return ErrEdgeNotFound
+	}
+
+	g.out[v][w] = weight
+	g.in[w][v] = weight
+	return nil
+}
+
+// RemoveEdge will remove the edge leading from v to w.
+func (g *DirectedGraph) RemoveEdge(v, w interface{}) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, ok := g.out[v][w]; !ok {
+		return ErrEdgeNotFound
+	}
+
+	delete(g.out[v], w)
+	delete(g.in[w], v)
+	g.e--
+	return nil
+}
+
+// RemoveVertex will remove the vertex v along with every edge leading
+// to or from it.
+func (g *DirectedGraph) RemoveVertex(v interface{}) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	out, ok := g.out[v]
+	if !ok {
+		return ErrVertexNotFound
+	}
+
+	for w := range out {
+		delete(g.in[w], v)
+		g.e--
+	}
+	// a self-loop has already been removed from in above
+	for u := range g.in[v] {
+		delete(g.out[u], v)
+		g.e--
+	}
+
+	delete(g.out, v)
+	delete(g.in, v)
+	return nil
+}
+
+// HasVertex returns a bool indicating if v is in the graph
+func (g *DirectedGraph) HasVertex(v interface{}) bool {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	_, ok := g.out[v]
+	return ok
+}
+
+// HasEdge returns a bool indicating if there is an edge leading from v
+// to w
+func (g *DirectedGraph) HasEdge(v, w interface{}) bool {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	_, ok := g.out[v][w]
+	return ok
+}
+
+// Weight returns the weight of the edge leading from v to w
+func (g *DirectedGraph) Weight(v, w interface{}) (float64, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	weight, ok := g.out[v][w]
+	if !ok {
+		return 0, ErrEdgeNotFound
+	}
+	return weight, nil
+}
+
+// Vertices returns the list of all vertices in the graph
+func (g *DirectedGraph) Vertices() []interface{} {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	vertices := make([]interface{}, 0, len(g.out))
+	for v := range g.out {
+		vertices = append(vertices, v)
+	}
+	return vertices
+}
+
+// Edges returns the list of all edges in the graph
+func (g *DirectedGraph) Edges() []Edge {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	edges := make([]Edge, 0, g.e)
+	for v, out := range g.out {
+		for w, weight := range out {
+			edges = append(edges, Edge{From: v, To: w, Weight: weight})
+		}
+	}
+	return edges
+}
+
+// Adj returns the list of all vertices that v has an edge leading to
+func (g *DirectedGraph) Adj(v interface{}) ([]interface{}, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return keys(g.out, v)
+}
+
+// Predecessors returns the list of all vertices that have an edge
+// leading to v
+func (g *DirectedGraph) Predecessors(v interface{}) ([]interface{}, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return keys(g.in, v)
+}
+
+// OutDegree returns the number of edges leading from v
+func (g *DirectedGraph) OutDegree(v interface{}) (int, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	out, ok := g.out[v]
+	if !ok {
+		return 0, ErrVertexNotFound
+	}
+	return len(out), nil
+}
+
+// InDegree returns the number of edges leading to v
+func (g *DirectedGraph) InDegree(v interface{}) (int, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	in, ok := g.in[v]
+	if !ok {
+		return 0, ErrVertexNotFound
+	}
+	return len(in), nil
+}
+
+func (g *DirectedGraph) addVertex(v interface{}) {
+	if _, ok := g.out[v]; !ok {
+		g.out[v] = make(map[interface{}]float64)
+		g.in[v] = make(map[interface{}]float64)
+	}
+}
+
+func keys(adjacency map[interface{}]map[interface{}]float64, v interface{}) ([]interface{}, error) {
+	m, ok := adjacency[v]
+	if !ok {
+		return nil, ErrVertexNotFound
+	}
+
+	adj := make([]interface{}, 0, len(m))
+	for w := range m {
+		adj = append(adj, w)
+	}
+	return adj, nil
+}
+
+// NewDirectedGraph creates and returns a DirectedGraph
+func NewDirectedGraph() *DirectedGraph {
+	return &DirectedGraph{
+		out: make(map[interface{}]map[interface{}]float64),
+		in:  make(map[interface{}]map[interface{}]float64),
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectedAddEdge(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+
+	assert.Nil(dgraph.AddEdge("A", "B", 1))
+	assert.Equal(ErrParallelEdge, dgraph.AddEdge("A", "B", 2))
+
+	// The reverse edge is a different edge
+	assert.Nil(dgraph.AddEdge("B", "A", 3))
+
+	// Self loops are allowed
+	assert.Nil(dgraph.AddEdge("C", "C", 4))
+
+	assert.Equal(3, dgraph.V())
+	assert.Equal(3, dgraph.E())
+
+	w, err := dgraph.Weight("A", "B")
+	assert.Nil(err)
+	assert.Equal(1.0, w)
+
+	w, err = dgraph.Weight("B", "A")
+	assert.Nil(err)
+	assert.Equal(3.0, w)
+
+	_, err = dgraph.Weight("A", "C")
+	assert.Equal(ErrEdgeNotFound, err)
+
+	assert.True(dgraph.HasEdge("C", "C"))
+	assert.False(dgraph.HasEdge("A", "C"))
+}
+
+func TestDirectedSetWeight(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+
+	assert.Nil(dgraph.SetWeight("A", "B", 5))
+	w, _ := dgraph.Weight("A", "B")
+	assert.Equal(5.0, w)
+
+	assert.Equal(ErrEdgeNotFound, dgraph.SetWeight("B", "A", 5))
+	assert.Equal(1, dgraph.E())
+}
+
+func TestDirectedAdjAndDegree(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+
+	_, err := dgraph.Adj("A")
+	assert.Equal(ErrVertexNotFound, err)
+	_, err = dgraph.OutDegree("A")
+	assert.Equal(ErrVertexNotFound, err)
+
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("A", "C", 1)
+	dgraph.AddEdge("C", "B", 1)
+
+	adj, err := dgraph.Adj("A")
+	assert.Nil(err)
+	assert.ElementsMatch([]interface{}{"B", "C"}, adj)
+
+	adj, err = dgraph.Adj("B")
+	assert.Nil(err)
+	assert.Empty(adj)
+
+	pred, err := dgraph.Predecessors("B")
+	assert.Nil(err)
+	assert.ElementsMatch([]interface{}{"A", "C"}, pred)
+
+	d, err := dgraph.OutDegree("A")
+	assert.Nil(err)
+	assert.Equal(2, d)
+
+	d, err = dgraph.InDegree("B")
+	assert.Nil(err)
+	assert.Equal(2, d)
+
+	d, err = dgraph.InDegree("A")
+	assert.Nil(err)
+	assert.Equal(0, d)
+}
+
+func TestDirectedVerticesAndEdges(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddVertex("D")
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("B", "C", 2)
+	dgraph.AddVertex("A")
+
+	assert.ElementsMatch([]interface{}{"A", "B", "C", "D"}, dgraph.Vertices())
+	assert.ElementsMatch([]Edge{
+		{From: "A", To: "B", Weight: 1},
+		{From: "B", To: "C", Weight: 2},
+	}, dgraph.Edges())
+	assert.True(dgraph.HasVertex("D"))
+	assert.False(dgraph.HasVertex("E"))
+}
+
+func TestDirectedRemoveEdge(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("B", "A", 1)
+
+	assert.Nil(dgraph.RemoveEdge("A", "B"))
+	assert.Equal(ErrEdgeNotFound, dgraph.RemoveEdge("A", "B"))
+	assert.Equal(ErrEdgeNotFound, dgraph.RemoveEdge("A", "C"))
+
+	assert.Equal(1, dgraph.E())
+	assert.Equal(2, dgraph.V())
+	assert.False(dgraph.HasEdge("A", "B"))
+	assert.True(dgraph.HasEdge("B", "A"))
+
+	pred, _ := dgraph.Predecessors("B")
+	assert.Empty(pred)
+}
+
+func TestDirectedRemoveVertex(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("B", "C", 1)
+	dgraph.AddEdge("C", "B", 1)
+	dgraph.AddEdge("B", "B", 1)
+	dgraph.AddEdge("A", "C", 1)
+
+	assert.Equal(ErrVertexNotFound, dgraph.RemoveVertex("D"))
+	assert.Nil(dgraph.RemoveVertex("B"))
+
+	assert.Equal(2, dgraph.V())
+	assert.Equal(1, dgraph.E())
+	assert.False(dgraph.HasVertex("B"))
+
+	adj, _ := dgraph.Adj("A")
+	assert.Equal([]interface{}{"C"}, adj)
+	pred, _ := dgraph.Predecessors("C")
+	assert.Equal([]interface{}{"A"}, pred)
+	adj, _ = dgraph.Adj("C")
+	assert.Empty(adj)
+
+	// The vertex may be added again
+	assert.Nil(dgraph.AddEdge("C", "B", 1))
+	assert.Equal(2, dgraph.E())
+}
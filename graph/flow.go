@@ -0,0 +1,261 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSourceIsSink is returned when a maximum flow is requested between
+// a vertex and itself.
+var ErrSourceIsSink = errors.New("source and sink must be different vertices")
+
+// Flow is a maximum flow from a source vertex to a sink, where the
+// weight of every edge is its capacity.
+type Flow struct {
+	value float64
+	flows map[interface{}]map[interface{}]float64
+}
+
+// Value returns the total amount of flow leaving the source.
+func (f *Flow) Value() float64 {
+	return f.value
+}
+
+// EdgeFlow returns the amount of flow along the edge leading from v to
+// w.
+func (f *Flow) EdgeFlow(v, w interface{}) float64 {
+	return f.flows[v][w]
+}
+
+// network is the residual network of a graph.  Vertices are numbered
+// and every edge becomes an arc along with a reverse arc of no capacity,
+// arc i^1 being the reverse of arc i.
+type network struct {
+	vertices []interface{}
+	arcs     [][]int
+	to       []int
+	residual []float64
+	capacity []float64
+}
+
+func (n *network) addArc(v, w int, capacity float64) {
+	n.arcs[v] = append(n.arcs[v], len(n.to))
+	n.to = append(n.to, w)
+	n.residual = append(n.residual, capacity)
+	n.capacity = append(n.capacity, capacity)
+
+	n.arcs[w] = append(n.arcs[w], len(n.to))
+	n.to = append(n.to, v)
+	n.residual = append(n.residual, 0)
+	n.capacity = append(n.capacity, 0)
+}
+
+// push sends amount of flow along arc a.
+func (n *network) push(a int, amount float64) {
+	n.residual[a] -= amount
+	n.residual[a^1] += amount
+}
+
+// levels returns the distance in arcs with residual capacity from s to
+// every vertex, -1 for those that cannot be reached.
+func (n *network) levels(s int) []int {
+	level := make([]int, len(n.vertices))
+	for i := range level {
+		level[i] = -1
+	}
+
+	level[s] = 0
+	queue := []int{s}
+	for i := 0; i < len(queue); i++ {
+		v := queue[i]
+		for _, a := range n.arcs[v] {
+			if w := n.to[a]; n.residual[a] > 0 && level[w] < 0 {
+				level[w] = level[v] + 1
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	return level
+}
+
+// flow returns the flow found along every edge of the network.
+func (n *network) flow(s int) *Flow {
+	f := &Flow{flows: make(map[interface{}]map[interface{}]float64)}
+	for v, arcs := range n.arcs {
+		for _, a := range arcs {
+			amount := n.capacity[a] - n.residual[a]
+			if v == s {
+				// flow returning to the source is negative on its reverse arcs
+				f.value += amount
+			}
+
+			if n.capacity[a] == 0 || amount <= 0 {
+				continue
+			}
+
+			from, to := n.vertices[v], n.vertices[n.to[a]]
+			if f.flows[from] == nil {
+				f.flows[from] = make(map[interface{}]float64)
+			}
+			f.flows[from][to] = amount
+		}
+	}
+
+	return f
+}
+
+func newNetwork(g WeightedGraph, source, sink interface{}) (*network, int, int, error) {
+	for _, v := range []interface{}{source, sink} {
+		if _, err := g.Adj(v); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	if source == sink {
+		return nil, 0, 0, ErrSourceIsSink
+	}
+
+	vertices, edges, err := weightedEdges(g)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	n := &network{
+		vertices: vertices,
+		arcs:     make([][]int, len(vertices)),
+		to:       make([]int, 0, 2*len(edges)),
+		residual: make([]float64, 0, 2*len(edges)),
+		capacity: make([]float64, 0, 2*len(edges)),
+	}
+	index := make(map[interface{}]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+
+	for _, e := range edges {
+		if e.Weight < 0 {
+			return nil, 0, 0, ErrNegativeWeight
+		}
+
+		if e.From != e.To {
+			n.addArc(index[e.From], index[e.To], e.Weight)
+		}
+	}
+
+	return n, index[source], index[sink], nil
+}
+
+// EdmondsKarp returns a maximum flow from source to sink, treating the
+// weight of every edge as its capacity.  Flow is repeatedly pushed
+// along a shortest path with capacity left, found by a breadth first
+// search, so this runs in O(VE^2) time.  Returns ErrNegativeWeight if
+// an edge has a negative weight.
+func EdmondsKarp(g WeightedGraph, source, sink interface{}) (*Flow, error) {
+	n, s, t, err := newNetwork(g, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make([]int, len(n.vertices))
+	for {
+		for i := range previous {
+			previous[i] = -1
+		}
+
+		queue := []int{s}
+		for i := 0; i < len(queue) && previous[t] < 0; i++ {
+			v := queue[i]
+			for _, a := range n.arcs[v] {
+				if w := n.to[a]; n.residual[a] > 0 && w != s && previous[w] < 0 {
+					previous[w] = a
+					queue = append(queue, w)
+				}
+			}
+		}
+
+		if previous[t] < 0 {
+			break
+		}
+
+		amount := math.Inf(1)
+		for v := t; v != s; v = n.to[previous[v]^1] {
+			amount = math.Min(amount, n.residual[previous[v]])
+		}
+		for v := t; v != s; v = n.to[previous[v]^1] {
+			n.push(previous[v], amount)
+		}
+	}
+
+	return n.flow(s), nil
+}
+
+// Dinic returns a maximum flow from source to sink, treating the weight
+// of every edge as its capacity.  Vertices are layered by their distance
+// from the source and a blocking flow is pushed through the layers
+// before they are rebuilt, so this runs in O(V^2 E) time and is usually
+// faster than EdmondsKarp.  Returns ErrNegativeWeight if an edge has a
+// negative weight.
+func Dinic(g WeightedGraph, source, sink interface{}) (*Flow, error) {
+	n, s, t, err := newNetwork(g, source, sink)
+	if err != nil {
+		return nil, err
+	}
+
+	next := make([]int, len(n.vertices))
+	var level []int
+	var augment func(v int, limit float64) float64
+	augment = func(v int, limit float64) float64 {
+		if v == t {
+			return limit
+		}
+
+		// arcs that cannot carry more flow are skipped for the rest of
+		// this phase
+		for ; next[v] < len(n.arcs[v]); next[v]++ {
+			a := n.arcs[v][next[v]]
+			w := n.to[a]
+			if n.residual[a] <= 0 || level[w] != level[v]+1 {
+				continue
+			}
+
+			if amount := augment(w, math.Min(limit, n.residual[a])); amount > 0 {
+				n.push(a, amount)
+				return amount
+			}
+		}
+
+		return 0
+	}
+
+	for {
+		level = n.levels(s)
+		if level[t] < 0 {
+			break
+		}
+
+		for i := range next {
+			next[i] = 0
+		}
+		for augment(s, math.Inf(1)) > 0 {
+		}
+	}
+
+	return n.flow(s), nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type maxFlowFunc func(g WeightedGraph, source, sink interface{}) (*Flow, error)
+
+var maxFlowFuncs = map[string]maxFlowFunc{
+	"EdmondsKarp": EdmondsKarp,
+	"Dinic":       Dinic,
+}
+
+// newPipes returns a flow network whose maximum flow from s to t is 23,
+// from "Introduction to Algorithms" by Cormen et al.
+func newPipes() *DirectedGraph {
+	dgraph := NewDirectedGraph()
+	for _, e := range []Edge{
+		{"s", "v1", 16}, {"s", "v2", 13}, {"v1", "v3", 12}, {"v2", "v1", 4},
+		{"v2", "v4", 14}, {"v3", "v2", 9}, {"v3", "t", 20}, {"v4", "v3", 7},
+		{"v4", "t", 4},
+	} {
+		dgraph.AddEdge(e.From, e.To, e.Weight)
+	}
+	return dgraph
+}
+
+// checkFlow checks that the flow respects the capacity of every edge
+// and that flow is conserved at every vertex but the source and sink.
+func checkFlow(t *testing.T, g WeightedGraph, f *Flow, source, sink interface{}) {
+	_, edges, err := weightedEdges(g)
+	require.Nil(t, err)
+
+	net := make(map[interface{}]float64)
+	for _, e := range edges {
+		amount := f.EdgeFlow(e.From, e.To)
+		assert.True(t, amount >= 0 && amount <= e.Weight, "%v -> %v", e.From, e.To)
+		if e.From != e.To {
+			net[e.From] -= amount
+			net[e.To] += amount
+		}
+	}
+
+	for v, amount := range net {
+		switch v {
+		case source:
+			assert.InDelta(t, -f.Value(), amount, 1e-9)
+		case sink:
+			assert.InDelta(t, f.Value(), amount, 1e-9)
+		default:
+			assert.InDelta(t, 0, amount, 1e-9, "%v", v)
+		}
+	}
+}
+
+func TestMaxFlow(t *testing.T) {
+	for name, maxFlow := range maxFlowFuncs {
+		t.Run(name, func(t *testing.T) {
+			dgraph := newPipes()
+			f, err := maxFlow(dgraph, "s", "t")
+			require.Nil(t, err)
+			assert.Equal(t, 23.0, f.Value())
+			checkFlow(t, dgraph, f, "s", "t")
+
+			// the edges into t are saturated
+			assert.Equal(t, 4.0, f.EdgeFlow("v4", "t"))
+			assert.Equal(t, 19.0, f.EdgeFlow("v3", "t"))
+			assert.Equal(t, 0.0, f.EdgeFlow("t", "s"))
+		})
+	}
+}
+
+func TestMaxFlowUnreachable(t *testing.T) {
+	for name, maxFlow := range maxFlowFuncs {
+		t.Run(name, func(t *testing.T) {
+			dgraph := newPipes()
+			dgraph.AddVertex("u")
+
+			f, err := maxFlow(dgraph, "s", "u")
+			require.Nil(t, err)
+			assert.Equal(t, 0.0, f.Value())
+
+			f, err = maxFlow(dgraph, "t", "s")
+			require.Nil(t, err)
+			assert.Equal(t, 0.0, f.Value())
+		})
+	}
+}
+
+func TestMaxFlowErrors(t *testing.T) {
+	for name, maxFlow := range maxFlowFuncs {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+			dgraph := newPipes()
+
+			_, err := maxFlow(dgraph, "s", "x")
+			assert.Equal(ErrVertexNotFound, err)
+			_, err = maxFlow(dgraph, "x", "t")
+			assert.Equal(ErrVertexNotFound, err)
+			_, err = maxFlow(dgraph, "s", "s")
+			assert.Equal(ErrSourceIsSink, err)
+
+			dgraph.SetWeight("v1", "v3", -1)
+			_, err = maxFlow(dgraph, "s", "t")
+			assert.Equal(ErrNegativeWeight, err)
+		})
+	}
+}
+
+func TestMaxFlowSimpleGraph(t *testing.T) {
+	// with unit capacities the maximum flow is the number of edge
+	// disjoint paths
+	sgraph := NewSimpleGraph()
+	sgraph.AddEdge("s", "a")
+	sgraph.AddEdge("s", "b")
+	sgraph.AddEdge("s", "c")
+	sgraph.AddEdge("a", "d")
+	sgraph.AddEdge("b", "d")
+	sgraph.AddEdge("c", "e")
+	sgraph.AddEdge("d", "t")
+	sgraph.AddEdge("e", "t")
+
+	for name, maxFlow := range maxFlowFuncs {
+		t.Run(name, func(t *testing.T) {
+			f, err := maxFlow(sgraph, "s", "t")
+			require.Nil(t, err)
+			assert.Equal(t, 2.0, f.Value())
+			checkFlow(t, sgraph, f, "s", "t")
+		})
+	}
+}
+
+func TestMaxFlowRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		n := 20
+		dgraph := NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			dgraph.AddVertex(i)
+		}
+		for i := 0; i < 70; i++ {
+			dgraph.AddEdge(r.Intn(n), r.Intn(n), float64(r.Intn(10)))
+		}
+
+		ek, err := EdmondsKarp(dgraph, 0, n-1)
+		require.Nil(t, err)
+		checkFlow(t, dgraph, ek, 0, n-1)
+
+		dinic, err := Dinic(dgraph, 0, n-1)
+		require.Nil(t, err)
+		checkFlow(t, dgraph, dinic, 0, n-1)
+
+		assert.Equal(t, ek.Value(), dinic.Value())
+	}
+}
+
+func BenchmarkMaxFlow(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	n := 1000
+	dgraph := NewDirectedGraph()
+	for i := 0; i < n*10; i++ {
+		dgraph.AddEdge(r.Intn(n), r.Intn(n), float64(r.Intn(100)))
+	}
+
+	for name, maxFlow := range maxFlowFuncs {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				maxFlow(dgraph, 0, n-1)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+// Graph defines the read operations the algorithms in this package
+// require.  Undirected graphs are seen as having an edge leading each way.
+type Graph interface {
+	// Vertices returns the list of all vertices in the graph.
+	Vertices() []interface{}
+	// Adj returns the list of all vertices that v has an edge leading
+	// to.  Returns ErrVertexNotFound if v is not in the graph.
+	Adj(v interface{}) ([]interface{}, error)
+}
+
+// WeightedGraph is a Graph whose edges carry a weight.
+type WeightedGraph interface {
+	Graph
+	// Weight returns the weight of the edge leading from v to w.
+	// Returns ErrEdgeNotFound if there is no such edge.
+	Weight(v, w interface{}) (float64, error)
+}
+
+// weightedEdges returns every vertex of the provided graph and every
+// edge along with its weight.
+func weightedEdges(g WeightedGraph) ([]interface{}, []Edge, error) {
+	vertices := g.Vertices()
+	edges := make([]Edge, 0, len(vertices))
+	for _, v := range vertices {
+		adj, err := g.Adj(v)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, w := range adj {
+			weight, err := g.Weight(v, w)
+			if err != nil {
+				return nil, nil, err
+			}
+			edges = append(edges, Edge{From: v, To: w, Weight: weight})
+		}
+	}
+
+	return vertices, edges, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"sort"
+
+	"github.com/Workiva/go-datastructures/fibheap"
+)
+
+// Kruskal returns the edges of a minimum spanning forest of the provided
+// graph, holding a minimum spanning tree of each connected component.
+// Edges are considered in order of weight and kept unless they join two
+// vertices already connected, which a union-find tracks, so this runs in
+// O(E log E) time.  The direction of edges is ignored.
+func Kruskal(g WeightedGraph) ([]Edge, error) {
+	vertices, edges, err := weightedEdges(g)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].Weight < edges[j].Weight
+	})
+
+	ds := newDisjointSet(len(vertices))
+	for _, v := range vertices {
+		ds.add(v)
+	}
+
+	forest := make([]Edge, 0, len(vertices))
+	for _, e := range edges {
+		ds.add(e.To)
+		if ds.union(e.From, e.To) {
+			forest = append(forest, e)
+		}
+	}
+
+	return forest, nil
+}
+
+// Prim returns the edges of a minimum spanning forest of the provided
+// graph, holding a minimum spanning tree of each connected component.
+// Each tree is grown from a single vertex by repeatedly adding the
+// cheapest edge leaving it, found with a Fibonacci heap, so this runs in
+// O(E + V log V) time.  The direction of edges is ignored.
+func Prim(g WeightedGraph) ([]Edge, error) {
+	vertices, edges, err := weightedEdges(g)
+	if err != nil {
+		return nil, err
+	}
+
+	incident := make(map[interface{}][]Edge, len(vertices))
+	for _, e := range edges {
+		incident[e.From] = append(incident[e.From], e)
+		incident[e.To] = append(incident[e.To], e)
+	}
+
+	forest := make([]Edge, 0, len(vertices))
+	inTree := make(map[interface{}]struct{}, len(vertices))
+	cheapest := make(map[interface{}]Edge)
+	heap := fibheap.NewIndexedFibHeap[interface{}]()
+	for _, root := range vertices {
+		if _, ok := inTree[root]; ok {
+			continue
+		}
+
+		heap.Enqueue(root, 0)
+		for !heap.IsEmpty() {
+			min, err := heap.DequeueMin()
+			if err != nil {
+				return nil, err
+			}
+
+			v := min.Value
+			inTree[v] = struct{}{}
+			if e, ok := cheapest[v]; ok {
+				forest = append(forest, e)
+				delete(cheapest, v)
+			}
+
+			for _, e := range incident[v] {
+				w := e.To
+				if w == v {
+					w = e.From
+				}
+
+				if _, ok := inTree[w]; ok {
+					continue
+				}
+
+				entry, ok := heap.Entry(w)
+				switch {
+				case !ok:
+					_, err = heap.Enqueue(w, e.Weight)
+				case e.Weight < entry.Priority:
+					_, err = heap.DecreaseKeyByID(w, e.Weight)
+				default:
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				cheapest[w] = e
+			}
+		}
+	}
+
+	return forest, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCities returns a weighted graph whose minimum spanning tree has a
+// weight of 37, from "Introduction to Algorithms" by Cormen et al.
+func newCities() *DirectedGraph {
+	dgraph := NewDirectedGraph()
+	for _, e := range []Edge{
+		{"a", "b", 4}, {"a", "h", 8}, {"b", "c", 8}, {"b", "h", 11},
+		{"c", "d", 7}, {"c", "f", 4}, {"c", "i", 2}, {"d", "e", 9},
+		{"d", "f", 14}, {"e", "f", 10}, {"f", "g", 2}, {"g", "h", 1},
+		{"g", "i", 6}, {"h", "i", 7},
+	} {
+		dgraph.AddEdge(e.From, e.To, e.Weight)
+	}
+	return dgraph
+}
+
+// checkForest checks that the provided edges form a spanning forest of
+// the graph and returns its total weight.
+func checkForest(t *testing.T, g WeightedGraph, forest []Edge) float64 {
+	components, err := ConnectedComponents(g)
+	require.Nil(t, err)
+	require.Len(t, forest, len(g.Vertices())-len(components))
+
+	ds := newDisjointSet(0)
+	total := 0.0
+	for _, e := range forest {
+		weight, err := g.Weight(e.From, e.To)
+		require.Nil(t, err)
+		assert.Equal(t, weight, e.Weight)
+
+		ds.add(e.From)
+		ds.add(e.To)
+		assert.True(t, ds.union(e.From, e.To), "%v - %v closes a cycle", e.From, e.To)
+		total += e.Weight
+	}
+
+	return total
+}
+
+func TestKruskal(t *testing.T) {
+	dgraph := newCities()
+	forest, err := Kruskal(dgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 37.0, checkForest(t, dgraph, forest))
+}
+
+func TestPrim(t *testing.T) {
+	dgraph := newCities()
+	forest, err := Prim(dgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 37.0, checkForest(t, dgraph, forest))
+}
+
+func TestMSTForest(t *testing.T) {
+	dgraph := newCities()
+	dgraph.AddEdge("x", "y", -3)
+	dgraph.AddEdge("y", "z", 5)
+	dgraph.AddEdge("z", "x", 2)
+	dgraph.AddEdge("z", "z", -10)
+	dgraph.AddVertex("w")
+
+	forest, err := Kruskal(dgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 36.0, checkForest(t, dgraph, forest))
+
+	forest, err = Prim(dgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 36.0, checkForest(t, dgraph, forest))
+}
+
+func TestMSTSimpleGraph(t *testing.T) {
+	sgraph := NewSimpleGraph()
+	sgraph.AddEdge("A", "B")
+	sgraph.AddEdge("B", "C")
+	sgraph.AddEdge("C", "A")
+	sgraph.AddEdge("D", "E")
+
+	forest, err := Kruskal(sgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 3.0, checkForest(t, sgraph, forest))
+
+	forest, err = Prim(sgraph)
+	require.Nil(t, err)
+	assert.Equal(t, 3.0, checkForest(t, sgraph, forest))
+}
+
+func TestMSTRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		n := 30
+		dgraph := NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			dgraph.AddVertex(i)
+		}
+		for i := 0; i < 80; i++ {
+			dgraph.AddEdge(r.Intn(n), r.Intn(n), float64(r.Intn(20)-5))
+		}
+
+		kruskal, err := Kruskal(dgraph)
+		require.Nil(t, err)
+		prim, err := Prim(dgraph)
+		require.Nil(t, err)
+
+		assert.Equal(t, checkForest(t, dgraph, kruskal), checkForest(t, dgraph, prim))
+	}
+}
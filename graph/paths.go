@@ -0,0 +1,190 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"errors"
+
+	"github.com/Workiva/go-datastructures/fibheap"
+)
+
+var (
+	// ErrNegativeWeight is returned by Dijkstra when it reaches an edge
+	// with a negative weight.
+	ErrNegativeWeight = errors.New("negative edge weights not permitted")
+
+	// ErrNegativeCycle is returned by BellmanFord when a cycle whose
+	// weights sum to less than zero is reachable from the source, in
+	// which case shortest paths are undefined.
+	ErrNegativeCycle = errors.New("graph contains a negative cycle")
+)
+
+// ShortestPaths holds the shortest paths from a source vertex to every
+// vertex reachable from it.
+type ShortestPaths struct {
+	source    interface{}
+	distances map[interface{}]float64
+	previous  map[interface{}]interface{}
+}
+
+// Source returns the vertex the paths lead from.
+func (sp *ShortestPaths) Source() interface{} {
+	return sp.source
+}
+
+// DistanceTo returns the sum of the weights along the shortest path to
+// v and a bool indicating if v is reachable from the source.
+func (sp *ShortestPaths) DistanceTo(v interface{}) (float64, bool) {
+	distance, ok := sp.distances[v]
+	return distance, ok
+}
+
+// PathTo returns the vertices along the shortest path to v, starting
+// with the source and ending with v.  Returns nil if v is not reachable
+// from the source.
+func (sp *ShortestPaths) PathTo(v interface{}) []interface{} {
+	if _, ok := sp.distances[v]; !ok {
+		return nil
+	}
+
+	path := []interface{}{v}
+	for v != sp.source {
+		v = sp.previous[v]
+		path = append(path, v)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func newShortestPaths(source interface{}) *ShortestPaths {
+	return &ShortestPaths{
+		source:    source,
+		distances: map[interface{}]float64{source: 0},
+		previous:  make(map[interface{}]interface{}),
+	}
+}
+
+// Dijkstra returns the shortest paths from source to every vertex
+// reachable from it.  Vertices are settled in order of distance using a
+// Fibonacci heap, so this runs in O(E + V log V) time.  Returns
+// ErrNegativeWeight if an edge with a negative weight is reached; use
+// BellmanFord for such graphs.
+func Dijkstra(g WeightedGraph, source interface{}) (*ShortestPaths, error) {
+	if _, err := g.Adj(source); err != nil {
+		return nil, err
+	}
+
+	sp := newShortestPaths(source)
+	heap := fibheap.NewIndexedFibHeap[interface{}]()
+	heap.Enqueue(source, 0)
+	for !heap.IsEmpty() {
+		min, err := heap.DequeueMin()
+		if err != nil {
+			return nil, err
+		}
+
+		v := min.Value
+		adj, err := g.Adj(v)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range adj {
+			weight, err := g.Weight(v, w)
+			if err != nil {
+				return nil, err
+			}
+
+			if weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+
+			// a settled vertex is never closer than this so it is
+			// never enqueued again
+			distance := min.Priority + weight
+			if old, ok := sp.distances[w]; ok && distance >= old {
+				continue
+			}
+
+			sp.distances[w] = distance
+			sp.previous[w] = v
+			if heap.Contains(w) {
+				_, err = heap.DecreaseKeyByID(w, distance)
+			} else {
+				_, err = heap.Enqueue(w, distance)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return sp, nil
+}
+
+// BellmanFord returns the shortest paths from source to every vertex
+// reachable from it.  Unlike Dijkstra, edges may have negative weights.
+// This runs in O(VE) time.  Returns ErrNegativeCycle if a cycle whose
+// weights sum to less than zero is reachable from source.
+func BellmanFord(g WeightedGraph, source interface{}) (*ShortestPaths, error) {
+	if _, err := g.Adj(source); err != nil {
+		return nil, err
+	}
+
+	vertices, edges, err := weightedEdges(g)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := newShortestPaths(source)
+	relax := func() bool {
+		relaxed := false
+		for _, e := range edges {
+			distance, ok := sp.distances[e.From]
+			if !ok {
+				continue
+			}
+
+			distance += e.Weight
+			if old, ok := sp.distances[e.To]; ok && distance >= old {
+				continue
+			}
+
+			sp.distances[e.To] = distance
+			sp.previous[e.To] = e.From
+			relaxed = true
+		}
+		return relaxed
+	}
+
+	for i := 1; i < len(vertices); i++ {
+		if !relax() {
+			return sp, nil
+		}
+	}
+
+	// any edge that can still be relaxed lies on or behind a negative
+	// cycle
+	if relax() {
+		return nil, ErrNegativeCycle
+	}
+
+	return sp, nil
+}
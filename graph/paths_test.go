@@ -0,0 +1,230 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoads returns a small weighted directed graph with a cheap
+// indirect route from A to D.
+func newRoads() *DirectedGraph {
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 4)
+	dgraph.AddEdge("A", "C", 1)
+	dgraph.AddEdge("C", "B", 2)
+	dgraph.AddEdge("B", "D", 1)
+	dgraph.AddEdge("C", "D", 5)
+	dgraph.AddEdge("D", "E", 3)
+	dgraph.AddVertex("F")
+	return dgraph
+}
+
+func checkRoads(t *testing.T, sp *ShortestPaths) {
+	assert := assert.New(t)
+	assert.Equal("A", sp.Source())
+
+	expected := map[interface{}]float64{"A": 0, "B": 3, "C": 1, "D": 4, "E": 7}
+	for v, distance := range expected {
+		d, ok := sp.DistanceTo(v)
+		assert.True(ok)
+		assert.Equal(distance, d, "%v", v)
+	}
+
+	assert.Equal([]interface{}{"A", "C", "B", "D", "E"}, sp.PathTo("E"))
+	assert.Equal([]interface{}{"A"}, sp.PathTo("A"))
+
+	_, ok := sp.DistanceTo("F")
+	assert.False(ok)
+	assert.Nil(sp.PathTo("F"))
+	assert.Nil(sp.PathTo("Z"))
+}
+
+func TestDijkstra(t *testing.T) {
+	sp, err := Dijkstra(newRoads(), "A")
+	require.Nil(t, err)
+	checkRoads(t, sp)
+}
+
+func TestDijkstraNotFound(t *testing.T) {
+	sp, err := Dijkstra(newRoads(), "Z")
+	assert.Equal(t, ErrVertexNotFound, err)
+	assert.Nil(t, sp)
+}
+
+func TestDijkstraNegativeWeight(t *testing.T) {
+	dgraph := newRoads()
+	dgraph.SetWeight("B", "D", -1)
+
+	sp, err := Dijkstra(dgraph, "A")
+	assert.Equal(t, ErrNegativeWeight, err)
+	assert.Nil(t, sp)
+}
+
+func TestBellmanFord(t *testing.T) {
+	sp, err := BellmanFord(newRoads(), "A")
+	require.Nil(t, err)
+	checkRoads(t, sp)
+}
+
+func TestBellmanFordNegativeWeight(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := newRoads()
+	dgraph.SetWeight("C", "D", -2)
+
+	sp, err := BellmanFord(dgraph, "A")
+	require.Nil(t, err)
+
+	d, _ := sp.DistanceTo("E")
+	assert.Equal(2.0, d)
+	assert.Equal([]interface{}{"A", "C", "D", "E"}, sp.PathTo("E"))
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := newRoads()
+	dgraph.AddEdge("E", "C", -8)
+
+	sp, err := BellmanFord(dgraph, "A")
+	assert.Equal(ErrNegativeCycle, err)
+	assert.Nil(sp)
+
+	// A negative cycle not reachable from the source is ignored
+	dgraph.RemoveEdge("A", "C")
+	dgraph.RemoveEdge("A", "B")
+	dgraph.AddEdge("F", "A", 1)
+	sp, err = BellmanFord(dgraph, "F")
+	assert.Nil(err)
+	d, _ := sp.DistanceTo("A")
+	assert.Equal(1.0, d)
+
+	dgraph.AddEdge("F", "F", -1)
+	_, err = BellmanFord(dgraph, "F")
+	assert.Equal(ErrNegativeCycle, err)
+}
+
+func TestDijkstraBidirectional(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	for _, e := range [][2]string{{"A", "B"}, {"B", "C"}, {"A", "D"}, {"D", "C"}, {"C", "E"}} {
+		dgraph.AddEdge(e[0], e[1], 1)
+		dgraph.AddEdge(e[1], e[0], 1)
+	}
+
+	sp, err := Dijkstra(dgraph, "E")
+	require.Nil(t, err)
+	d, _ := sp.DistanceTo("A")
+	assert.Equal(3.0, d)
+	assert.Len(sp.PathTo("A"), 4)
+}
+
+// floydWarshall returns the distances between every pair of vertices
+// in the provided graph, math.Inf(1) where there is no path.
+func floydWarshall(dgraph *DirectedGraph, n int) [][]float64 {
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+		}
+		dist[i][i] = 0
+	}
+
+	for _, e := range dgraph.Edges() {
+		i, j := e.From.(int), e.To.(int)
+		dist[i][j] = math.Min(dist[i][j], e.Weight)
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if dist[i][k]+dist[k][j] < dist[i][j] {
+					dist[i][j] = dist[i][k] + dist[k][j]
+				}
+			}
+		}
+	}
+
+	return dist
+}
+
+func checkShortestPaths(t *testing.T, dgraph *DirectedGraph, sp *ShortestPaths, expected []float64) {
+	for v, distance := range expected {
+		d, ok := sp.DistanceTo(v)
+		if math.IsInf(distance, 1) {
+			assert.False(t, ok)
+			assert.Nil(t, sp.PathTo(v))
+			continue
+		}
+
+		require.True(t, ok)
+		assert.InDelta(t, distance, d, 1e-9)
+
+		path := sp.PathTo(v)
+		sum := 0.0
+		for i := 1; i < len(path); i++ {
+			w, err := dgraph.Weight(path[i-1], path[i])
+			require.Nil(t, err)
+			sum += w
+		}
+		assert.InDelta(t, distance, sum, 1e-9)
+	}
+}
+
+func TestShortestPathsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		n := 30
+		dgraph := NewDirectedGraph()
+		for i := 0; i < n; i++ {
+			dgraph.AddVertex(i)
+		}
+		for i := 0; i < 120; i++ {
+			dgraph.AddEdge(r.Intn(n), r.Intn(n), float64(r.Intn(100)))
+		}
+
+		dist := floydWarshall(dgraph, n)
+		for source := 0; source < n; source += 7 {
+			sp, err := Dijkstra(dgraph, source)
+			require.Nil(t, err)
+			checkShortestPaths(t, dgraph, sp, dist[source])
+
+			sp, err = BellmanFord(dgraph, source)
+			require.Nil(t, err)
+			checkShortestPaths(t, dgraph, sp, dist[source])
+		}
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	n := 10000
+	dgraph := NewDirectedGraph()
+	for i := 0; i < n*5; i++ {
+		dgraph.AddEdge(r.Intn(n), r.Intn(n), r.Float64())
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Dijkstra(dgraph, 0)
+	}
+}
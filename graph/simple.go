@@ -16,7 +16,14 @@ limitations under the License.
 
 /*
 Package graph provides graph implementations. Currently, this includes an
-undirected simple graph.
+undirected simple graph and a weighted directed graph.
+
+Algorithms are provided as functions over the Graph and WeightedGraph
+interfaces, which the graphs in this package implement.  These include
+breadth and depth first traversals, topological sorting, cycle detection,
+single-source shortest paths, connected components, minimum spanning trees
+and maximum flow.  Algorithms read the graph through its methods as they
+run, so the graph should not be modified concurrently.
 */
 package graph
 
@@ -37,6 +44,10 @@ var (
 	// ErrParallelEdge is returned when an operation tries to create a
 	// disallowed parallel edge.
 	ErrParallelEdge = errors.New("parallel edges are not permitted")
+
+	// ErrEdgeNotFound is returned when an operation is requested on a
+	// non-existent edge.
+	ErrEdgeNotFound = errors.New("edge not found")
 )
 
 // SimpleGraph is a mutable, non-persistent undirected graph.
@@ -105,6 +116,31 @@ func (g *SimpleGraph) Adj(v interface{}) ([]interface{}, error) {
 	return adj, nil
 }
 
+// Weight returns the weight of the edge between v and w.  Edges of a
+// SimpleGraph are unweighted so every edge has a weight of 1, which
+// lets a SimpleGraph be used as a WeightedGraph.
+func (g *SimpleGraph) Weight(v, w interface{}) (float64, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if _, ok := g.adjacencyList[v][w]; !ok {
+		return 0, ErrEdgeNotFound
+	}
+	return 1, nil
+}
+
+// Vertices returns the list of all vertices in the SimpleGraph
+func (g *SimpleGraph) Vertices() []interface{} {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	vertices := make([]interface{}, 0, len(g.adjacencyList))
+	for v := range g.adjacencyList {
+		vertices = append(vertices, v)
+	}
+	return vertices
+}
+
 // Degree returns the number of vertices connected to v
 func (g *SimpleGraph) Degree(v interface{}) (int, error) {
 	g.mutex.RLock()
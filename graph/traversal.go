@@ -0,0 +1,222 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import "errors"
+
+// ErrCycle is returned when an operation requires an acyclic graph but
+// the graph contains a cycle.
+var ErrCycle = errors.New("graph contains a cycle")
+
+// BFS visits every vertex reachable from source in breadth first order,
+// calling visit with each vertex and its distance in edges from source.
+// The traversal stops early if visit returns false.
+func BFS(g Graph, source interface{}, visit func(v interface{}, depth int) bool) error {
+	if _, err := g.Adj(source); err != nil {
+		return err
+	}
+
+	if !visit(source, 0) {
+		return nil
+	}
+
+	seen := map[interface{}]struct{}{source: {}}
+	level := []interface{}{source}
+	for depth := 1; len(level) > 0; depth++ {
+		next := make([]interface{}, 0, len(level))
+		for _, v := range level {
+			adj, err := g.Adj(v)
+			if err != nil {
+				return err
+			}
+
+			for _, w := range adj {
+				if _, ok := seen[w]; ok {
+					continue
+				}
+
+				seen[w] = struct{}{}
+				if !visit(w, depth) {
+					return nil
+				}
+				next = append(next, w)
+			}
+		}
+		level = next
+	}
+
+	return nil
+}
+
+// DFS visits every vertex reachable from source in depth first order,
+// calling visit with each vertex before any vertex reached through it.
+// The traversal stops early if visit returns false.
+func DFS(g Graph, source interface{}, visit func(v interface{}) bool) error {
+	if _, err := g.Adj(source); err != nil {
+		return err
+	}
+
+	seen := make(map[interface{}]struct{})
+	stack := []interface{}{source}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := seen[v]; ok {
+			continue
+		}
+
+		seen[v] = struct{}{}
+		if !visit(v) {
+			return nil
+		}
+
+		adj, err := g.Adj(v)
+		if err != nil {
+			return err
+		}
+
+		// push in reverse so vertices are visited in the order of Adj
+		for i := len(adj) - 1; i >= 0; i-- {
+			if _, ok := seen[adj[i]]; !ok {
+				stack = append(stack, adj[i])
+			}
+		}
+	}
+
+	return nil
+}
+
+// TopologicalSort returns the vertices of a directed graph ordered such
+// that every edge leads from a vertex to one later in the list.
+// Returns ErrCycle if the graph contains a cycle, in which case no such
+// order exists.
+func TopologicalSort(g Graph) ([]interface{}, error) {
+	vertices := g.Vertices()
+	adjacency := make(map[interface{}][]interface{}, len(vertices))
+	inDegree := make(map[interface{}]int, len(vertices))
+	for _, v := range vertices {
+		adj, err := g.Adj(v)
+		if err != nil {
+			return nil, err
+		}
+
+		adjacency[v] = adj
+		for _, w := range adj {
+			inDegree[w]++
+		}
+	}
+
+	sorted := make([]interface{}, 0, len(vertices))
+	for _, v := range vertices {
+		if inDegree[v] == 0 {
+			sorted = append(sorted, v)
+		}
+	}
+
+	// sorted doubles as the queue of vertices with no remaining edges
+	// leading to them
+	for i := 0; i < len(sorted); i++ {
+		for _, w := range adjacency[sorted[i]] {
+			inDegree[w]--
+			if inDegree[w] == 0 {
+				sorted = append(sorted, w)
+			}
+		}
+	}
+
+	if len(sorted) < len(vertices) {
+		return nil, ErrCycle
+	}
+
+	return sorted, nil
+}
+
+// dfsFrame is a vertex on the path of FindCycle along with the index of
+// the next of its adjacent vertices to explore.
+type dfsFrame struct {
+	v   interface{}
+	adj []interface{}
+	i   int
+}
+
+// FindCycle returns the vertices of a cycle in a directed graph in the
+// order they are visited, the last vertex having an edge leading back to
+// the first.  Returns nil if the graph is acyclic.
+func FindCycle(g Graph) ([]interface{}, error) {
+	const (
+		onPath = iota + 1
+		done
+	)
+
+	state := make(map[interface{}]int)
+	for _, root := range g.Vertices() {
+		if state[root] != 0 {
+			continue
+		}
+
+		adj, err := g.Adj(root)
+		if err != nil {
+			return nil, err
+		}
+
+		state[root] = onPath
+		path := []*dfsFrame{{v: root, adj: adj}}
+		for len(path) > 0 {
+			frame := path[len(path)-1]
+			if frame.i == len(frame.adj) {
+				state[frame.v] = done
+				path = path[:len(path)-1]
+				continue
+			}
+
+			w := frame.adj[frame.i]
+			frame.i++
+			switch state[w] {
+			case onPath:
+				start := len(path) - 1
+				for path[start].v != w {
+					start--
+				}
+
+				cycle := make([]interface{}, 0, len(path)-start)
+				for _, f := range path[start:] {
+					cycle = append(cycle, f.v)
+				}
+				return cycle, nil
+			case done:
+				continue
+			}
+
+			adj, err := g.Adj(w)
+			if err != nil {
+				return nil, err
+			}
+
+			state[w] = onPath
+			path = append(path, &dfsFrame{v: w, adj: adj})
+		}
+	}
+
+	return nil, nil
+}
+
+// HasCycle returns a bool indicating if a directed graph contains a
+// cycle.
+func HasCycle(g Graph) (bool, error) {
+	cycle, err := FindCycle(g)
+	return cycle != nil, err
+}
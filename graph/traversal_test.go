@@ -0,0 +1,259 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDAG returns a directed acyclic graph of the form
+//
+//	A -> B -> D -> F
+//	A -> C -> D
+//	C -> E
+func newDAG() *DirectedGraph {
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("A", "C", 1)
+	dgraph.AddEdge("B", "D", 1)
+	dgraph.AddEdge("C", "D", 1)
+	dgraph.AddEdge("C", "E", 1)
+	dgraph.AddEdge("D", "F", 1)
+	return dgraph
+}
+
+func TestBFS(t *testing.T) {
+	assert := assert.New(t)
+	depths := make(map[interface{}]int)
+	err := BFS(newDAG(), "A", func(v interface{}, depth int) bool {
+		depths[v] = depth
+		return true
+	})
+
+	assert.Nil(err)
+	assert.Equal(map[interface{}]int{
+		"A": 0, "B": 1, "C": 1, "D": 2, "E": 2, "F": 3,
+	}, depths)
+}
+
+func TestBFSFromMiddle(t *testing.T) {
+	assert := assert.New(t)
+	visited := make([]interface{}, 0)
+	err := BFS(newDAG(), "C", func(v interface{}, depth int) bool {
+		visited = append(visited, v)
+		return true
+	})
+
+	assert.Nil(err)
+	assert.Equal("C", visited[0])
+	assert.ElementsMatch([]interface{}{"C", "D", "E", "F"}, visited)
+}
+
+func TestBFSStop(t *testing.T) {
+	assert := assert.New(t)
+	count := 0
+	err := BFS(newDAG(), "A", func(v interface{}, depth int) bool {
+		count++
+		return depth < 1
+	})
+
+	assert.Nil(err)
+	assert.Equal(2, count)
+}
+
+func TestBFSNotFound(t *testing.T) {
+	err := BFS(newDAG(), "Z", func(v interface{}, depth int) bool {
+		return true
+	})
+	assert.Equal(t, ErrVertexNotFound, err)
+}
+
+func TestBFSSimpleGraph(t *testing.T) {
+	assert := assert.New(t)
+	sgraph := NewSimpleGraph()
+	sgraph.AddEdge("A", "B")
+	sgraph.AddEdge("B", "C")
+	sgraph.AddEdge("D", "E")
+
+	depths := make(map[interface{}]int)
+	err := BFS(sgraph, "C", func(v interface{}, depth int) bool {
+		depths[v] = depth
+		return true
+	})
+
+	assert.Nil(err)
+	assert.Equal(map[interface{}]int{"C": 0, "B": 1, "A": 2}, depths)
+}
+
+func TestDFS(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := NewDirectedGraph()
+	dgraph.AddEdge("A", "B", 1)
+	dgraph.AddEdge("B", "C", 1)
+	dgraph.AddEdge("C", "A", 1)
+	dgraph.AddEdge("D", "A", 1)
+
+	visited := make([]interface{}, 0)
+	err := DFS(dgraph, "A", func(v interface{}) bool {
+		visited = append(visited, v)
+		return true
+	})
+
+	assert.Nil(err)
+	assert.Equal([]interface{}{"A", "B", "C"}, visited)
+}
+
+func TestDFSOrder(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := newDAG()
+	position := make(map[interface{}]int)
+	err := DFS(dgraph, "A", func(v interface{}) bool {
+		position[v] = len(position)
+		return true
+	})
+
+	assert.Nil(err)
+	assert.Len(position, 6)
+
+	// Each branch is explored fully before the next one starts, so F
+	// directly follows D and, if B's branch went first, E directly
+	// follows C.
+	assert.Equal(position["D"]+1, position["F"])
+	if position["B"] < position["C"] {
+		assert.Equal(position["C"]+1, position["E"])
+	}
+}
+
+func TestDFSStopAndNotFound(t *testing.T) {
+	assert := assert.New(t)
+	count := 0
+	err := DFS(newDAG(), "A", func(v interface{}) bool {
+		count++
+		return count < 3
+	})
+
+	assert.Nil(err)
+	assert.Equal(3, count)
+
+	err = DFS(newDAG(), "Z", func(v interface{}) bool {
+		return true
+	})
+	assert.Equal(ErrVertexNotFound, err)
+}
+
+func checkTopological(t *testing.T, dgraph *DirectedGraph, sorted []interface{}) {
+	require.Len(t, sorted, dgraph.V())
+	position := make(map[interface{}]int, len(sorted))
+	for i, v := range sorted {
+		position[v] = i
+	}
+
+	for _, e := range dgraph.Edges() {
+		assert.True(t, position[e.From] < position[e.To], "%v -> %v", e.From, e.To)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	dgraph := newDAG()
+	dgraph.AddVertex("G")
+
+	sorted, err := TopologicalSort(dgraph)
+	require.Nil(t, err)
+	checkTopological(t, dgraph, sorted)
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := newDAG()
+	dgraph.AddEdge("F", "C", 1)
+
+	sorted, err := TopologicalSort(dgraph)
+	assert.Equal(ErrCycle, err)
+	assert.Nil(sorted)
+
+	dgraph.RemoveEdge("F", "C")
+	sorted, err = TopologicalSort(dgraph)
+	assert.Nil(err)
+	checkTopological(t, dgraph, sorted)
+}
+
+func TestTopologicalSortEmpty(t *testing.T) {
+	sorted, err := TopologicalSort(NewDirectedGraph())
+	assert.Nil(t, err)
+	assert.Empty(t, sorted)
+}
+
+func checkCycle(t *testing.T, dgraph *DirectedGraph, cycle []interface{}) {
+	require.NotEmpty(t, cycle)
+	for i, v := range cycle {
+		w := cycle[(i+1)%len(cycle)]
+		assert.True(t, dgraph.HasEdge(v, w), "%v -> %v", v, w)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	assert := assert.New(t)
+	dgraph := newDAG()
+
+	cycle, err := FindCycle(dgraph)
+	assert.Nil(err)
+	assert.Nil(cycle)
+
+	ok, err := HasCycle(dgraph)
+	assert.Nil(err)
+	assert.False(ok)
+
+	dgraph.AddEdge("F", "C", 1)
+	cycle, err = FindCycle(dgraph)
+	assert.Nil(err)
+	assert.Len(cycle, 3)
+	assert.ElementsMatch([]interface{}{"C", "D", "F"}, cycle)
+	checkCycle(t, dgraph, cycle)
+
+	ok, err = HasCycle(dgraph)
+	assert.Nil(err)
+	assert.True(ok)
+}
+
+func TestFindCycleSelfLoop(t *testing.T) {
+	dgraph := newDAG()
+	dgraph.AddEdge("E", "E", 1)
+
+	cycle, err := FindCycle(dgraph)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"E"}, cycle)
+}
+
+func TestFindCycleLong(t *testing.T) {
+	dgraph := NewDirectedGraph()
+	for i := 0; i < 10000; i++ {
+		dgraph.AddEdge(i, i+1, 1)
+	}
+
+	cycle, err := FindCycle(dgraph)
+	assert.Nil(t, err)
+	assert.Nil(t, cycle)
+
+	dgraph.AddEdge(10000, 0, 1)
+	cycle, err = FindCycle(dgraph)
+	assert.Nil(t, err)
+	assert.Len(t, cycle, 10001)
+	checkCycle(t, dgraph, cycle)
+}
@@ -24,22 +24,32 @@ import "errors"
 
 var (
 	// Empty is an empty PersistentList.
-	Empty PersistentList = &emptyList{}
+	Empty PersistentList = &emptyList[interface{}]{}
 
 	// ErrEmptyList is returned when an invalid operation is performed on an
 	// empty list.
 	ErrEmptyList = errors.New("Empty list")
 )
 
-// PersistentList is an immutable, persistent linked list.
-type PersistentList interface {
+// PersistentList is an immutable, persistent linked list of interface{}
+// items.
+type PersistentList = PersistentListOf[interface{}]
+
+// EmptyOf returns an empty PersistentListOf holding items of type T.
+func EmptyOf[T any]() PersistentListOf[T] {
+	return &emptyList[T]{}
+}
+
+// PersistentListOf is an immutable, persistent linked list of items of
+// type T.
+type PersistentListOf[T any] interface {
 	// Head returns the head of the list. The bool will be false if the list is
 	// empty.
-	Head() (interface{}, bool)
+	Head() (T, bool)
 
 	// Tail returns the tail of the list. The bool will be false if the list is
 	// empty.
-	Tail() (PersistentList, bool)
+	Tail() (PersistentListOf[T], bool)
 
 	// IsEmpty indicates if the list is empty.
 	IsEmpty() bool
@@ -48,65 +58,66 @@ type PersistentList interface {
 	Length() uint
 
 	// Add will add the item to the list, returning the new list.
-	Add(head interface{}) PersistentList
+	Add(head T) PersistentListOf[T]
 
 	// Insert will insert the item at the given position, returning the new
 	// list or an error if the position is invalid.
-	Insert(val interface{}, pos uint) (PersistentList, error)
+	Insert(val T, pos uint) (PersistentListOf[T], error)
 
 	// Get returns the item at the given position or an error if the position
 	// is invalid.
-	Get(pos uint) (interface{}, bool)
+	Get(pos uint) (T, bool)
 
 	// Remove will remove the item at the given position, returning the new
 	// list or an error if the position is invalid.
-	Remove(pos uint) (PersistentList, error)
+	Remove(pos uint) (PersistentListOf[T], error)
 
 	// Find applies the predicate function to the list and returns the first
 	// item which matches.
-	Find(func(interface{}) bool) (interface{}, bool)
+	Find(func(T) bool) (T, bool)
 
 	// FindIndex applies the predicate function to the list and returns the
 	// index of the first item which matches or -1 if there is no match.
-	FindIndex(func(interface{}) bool) int
+	FindIndex(func(T) bool) int
 
 	// Map applies the function to each entry in the list and returns the
 	// resulting slice.
-	Map(func(interface{}) interface{}) []interface{}
+	Map(func(T) T) []T
 }
 
-type emptyList struct{}
+type emptyList[T any] struct{}
 
 // Head returns the head of the list. The bool will be false if the list is
 // empty.
-func (e *emptyList) Head() (interface{}, bool) {
-	return nil, false
+func (e *emptyList[T]) Head() (T, bool) {
+	var zero T
+	return zero, false
 }
 
 // Tail returns the tail of the list. The bool will be false if the list is
 // empty.
-func (e *emptyList) Tail() (PersistentList, bool) {
+func (e *emptyList[T]) Tail() (PersistentListOf[T], bool) {
 	return nil, false
 }
 
 // IsEmpty indicates if the list is empty.
-func (e *emptyList) IsEmpty() bool {
+func (e *emptyList[T]) IsEmpty() bool {
 	return true
 }
 
 // Length returns the number of items in the list.
-func (e *emptyList) Length() uint {
+func (e *emptyList[T]) Length() uint {
 	return 0
 }
 
 // Add will add the item to the list, returning the new list.
-func (e *emptyList) Add(head interface{}) PersistentList {
-	return &list{head, e}
+func (e *emptyList[T]) Add(head T) PersistentListOf[T] {
+	return &list[T]{head, e}
 }
 
 // Insert will insert the item at the given position, returning the new list or
 // an error if the position is invalid.
-func (e *emptyList) Insert(val interface{}, pos uint) (PersistentList, error) {
+func (e *emptyList[T]) Insert(val T, pos uint) (PersistentListOf[T], error) {
 	if pos == 0 {
 		return e.Add(val), nil
 	}
@@ -115,58 +126,60 @@ func (e *emptyList) Insert(val interface{}, pos uint) (PersistentList, error) {
 
 // Get returns the item at the given position or an error if the position is
 // invalid.
-func (e *emptyList) Get(pos uint) (interface{}, bool) {
-	return nil, false
+func (e *emptyList[T]) Get(pos uint) (T, bool) {
+	var zero T
+	return zero, false
 }
 
 // Remove will remove the item at the given position, returning the new list or
 // an error if the position is invalid.
-func (e *emptyList) Remove(pos uint) (PersistentList, error) {
+func (e *emptyList[T]) Remove(pos uint) (PersistentListOf[T], error) {
 	return nil, ErrEmptyList
 }
 
 // Find applies the predicate function to the list and returns the first item
 // which matches.
-func (e *emptyList) Find(func(interface{}) bool) (interface{}, bool) {
-	return nil, false
+func (e *emptyList[T]) Find(func(T) bool) (T, bool) {
+	var zero T
+	return zero, false
 }
 
 // FindIndex applies the predicate function to the list and returns the index
 // of the first item which matches or -1 if there is no match.
-func (e *emptyList) FindIndex(func(interface{}) bool) int {
+func (e *emptyList[T]) FindIndex(func(T) bool) int {
 	return -1
 }
 
 // Map applies the function to each entry in the list and returns the resulting
 // slice.
-func (e *emptyList) Map(func(interface{}) interface{}) []interface{} {
+func (e *emptyList[T]) Map(func(T) T) []T {
 	return nil
 }
 
-type list struct {
-	head interface{}
-	tail PersistentList
+type list[T any] struct {
+	head T
+	tail PersistentListOf[T]
 }
 
 // Head returns the head of the list. The bool will be false if the list is
 // empty.
-func (l *list) Head() (interface{}, bool) {
+func (l *list[T]) Head() (T, bool) {
 	return l.head, true
 }
 
 // Tail returns the tail of the list. The bool will be false if the list is
 // empty.
-func (l *list) Tail() (PersistentList, bool) {
+func (l *list[T]) Tail() (PersistentListOf[T], bool) {
 	return l.tail, true
 }
 
 // IsEmpty indicates if the list is empty.
-func (l *list) IsEmpty() bool {
+func (l *list[T]) IsEmpty() bool {
 	return false
 }
 
 // Length returns the number of items in the list.
-func (l *list) Length() uint {
+func (l *list[T]) Length() uint {
 	curr := l
 	length := uint(0)
 	for {
@@ -175,18 +188,18 @@ func (l *list) Length() uint {
 		if tail.IsEmpty() {
 			return length
 		}
-		curr = tail.(*list)
+		curr = tail.(*list[T])
 	}
 }
 
 // Add will add the item to the list, returning the new list.
-func (l *list) Add(head interface{}) PersistentList {
-	return &list{head, l}
+func (l *list[T]) Add(head T) PersistentListOf[T] {
+	return &list[T]{head, l}
 }
 
 // Insert will insert the item at the given position, returning the new list or
 // an error if the position is invalid.
-func (l *list) Insert(val interface{}, pos uint) (PersistentList, error) {
+func (l *list[T]) Insert(val T, pos uint) (PersistentListOf[T], error) {
 	if pos == 0 {
 		return l.Add(val), nil
 	}
@@ -199,7 +212,7 @@ func (l *list) Insert(val interface{}, pos uint) (PersistentList, error) {
 
 // Get returns the item at the given position or an error if the position is
 // invalid.
-func (l *list) Get(pos uint) (interface{}, bool) {
+func (l *list[T]) Get(pos uint) (T, bool) {
 	if pos == 0 {
 		return l.head, true
 	}
@@ -208,7 +221,7 @@ func (l *list) Get(pos uint) (interface{}, bool) {
 
 // Remove will remove the item at the given position, returning the new list or
 // an error if the position is invalid.
-func (l *list) Remove(pos uint) (PersistentList, error) {
+func (l *list[T]) Remove(pos uint) (PersistentListOf[T], error) {
 	if pos == 0 {
 		nl, _ := l.Tail()
 		return nl, nil
@@ -218,12 +231,12 @@ func (l *list) Remove(pos uint) (PersistentList, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &list{l.head, nl}, nil
+	return &list[T]{l.head, nl}, nil
 }
 
 // Find applies the predicate function to the list and returns the first item
 // which matches.
-func (l *list) Find(pred func(interface{}) bool) (interface{}, bool) {
+func (l *list[T]) Find(pred func(T) bool) (T, bool) {
 	if pred(l.head) {
 		return l.head, true
 	}
@@ -232,7 +245,7 @@ func (l *list) Find(pred func(interface{}) bool) (interface{}, bool) {
 
 // FindIndex applies the predicate function to the list and returns the index
 // of the first item which matches or -1 if there is no match.
-func (l *list) FindIndex(pred func(interface{}) bool) int {
+func (l *list[T]) FindIndex(pred func(T) bool) int {
 	curr := l
 	idx := 0
 	for {
@@ -243,13 +256,13 @@ func (l *list) FindIndex(pred func(interface{}) bool) int {
 		if tail.IsEmpty() {
 			return -1
 		}
-		curr = tail.(*list)
+		curr = tail.(*list[T])
 		idx += 1
 	}
 }
 
 // Map applies the function to each entry in the list and returns the resulting
 // slice.
-func (l *list) Map(f func(interface{}) interface{}) []interface{} {
+func (l *list[T]) Map(f func(T) T) []T {
 	return append(l.tail.Map(f), f(l.head))
 }
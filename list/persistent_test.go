@@ -287,3 +287,34 @@ func TestMap(t *testing.T) {
 	l := Empty.Add(1).Add(2).Add(3).Add(4)
 	assert.Equal([]interface{}{1, 4, 9, 16}, l.Map(f))
 }
+
+func TestPersistentListOf(t *testing.T) {
+	assert := assert.New(t)
+	empty := EmptyOf[string]()
+	head, ok := empty.Head()
+	assert.Equal(``, head)
+	assert.False(ok)
+
+	l, err := empty.Add(`c`).Add(`a`).Insert(`b`, 1)
+	assert.Nil(err)
+	assert.Equal(uint(3), l.Length())
+
+	item, ok := l.Get(1)
+	assert.True(ok)
+	assert.Equal(`b`, item)
+
+	found, ok := l.Find(func(item string) bool {
+		return item > `a`
+	})
+	assert.True(ok)
+	assert.Equal(`b`, found)
+	assert.Equal(2, l.FindIndex(func(item string) bool {
+		return item == `c`
+	}))
+
+	l, err = l.Remove(0)
+	assert.Nil(err)
+	assert.Equal([]string{`c`, `b`}, l.Map(func(item string) string {
+		return item
+	}))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package mock
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 
 	"github.com/Workiva/go-datastructures/batcher"
@@ -37,16 +39,34 @@ func (m *Batcher) Put(items interface{}) error {
 	return args.Error(0)
 }
 
+func (m *Batcher) PutContext(ctx context.Context, items interface{}) error {
+	args := m.Called(ctx, items)
+	if m.PutChan != nil {
+		m.PutChan <- true
+	}
+	return args.Error(0)
+}
+
 func (m *Batcher) Get() ([]interface{}, error) {
 	args := m.Called()
 	return args.Get(0).([]interface{}), args.Error(1)
 }
 
+func (m *Batcher) GetContext(ctx context.Context) ([]interface{}, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]interface{}), args.Error(1)
+}
+
 func (m *Batcher) Flush() error {
 	args := m.Called()
 	return args.Error(0)
 }
 
+func (m *Batcher) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 func (m *Batcher) Dispose() {
 	m.Called()
 }
@@ -25,6 +25,10 @@ http://en.wikipedia.org/wiki/Hilbert_curve
 This expects coordinates in the range [0, 0] to [MaxInt32, MaxInt32].
 Using negative values for x and y will have undefinied behavior.
 
+EncodeN and DecodeN generalize this to any number of dimensions as long
+as the number of dimensions multiplied by the bits used per coordinate
+fits in 64 bits.
+
 Benchmarks:
 BenchmarkEncode-8	10000000	       181 ns/op
 BenchmarkDecode-8	10000000	       191 ns/op
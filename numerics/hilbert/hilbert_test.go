@@ -67,3 +67,65 @@ func BenchmarkDecode(b *testing.B) {
 		Decode(int64(i))
 	}
 }
+
+func TestHilbertN(t *testing.T) {
+	// every point of a small cube is visited exactly once and every
+	// step along the curve moves to an adjacent point
+	bits := uint(3)
+	dims := 3
+	total := uint64(1) << (bits * uint(dims))
+	seen := make(map[[3]uint32]bool, total)
+
+	var last []uint32
+	for h := uint64(0); h < total; h++ {
+		coords := DecodeN(h, bits, dims)
+		assert.Equal(t, h, EncodeN(bits, coords...))
+
+		key := [3]uint32{coords[0], coords[1], coords[2]}
+		assert.False(t, seen[key])
+		seen[key] = true
+
+		if last != nil {
+			steps := 0
+			for i := range coords {
+				d := int64(coords[i]) - int64(last[i])
+				if d < 0 {
+					d = -d
+				}
+				steps += int(d)
+			}
+			assert.Equal(t, 1, steps)
+		}
+		last = coords
+	}
+
+	assert.Len(t, seen, int(total))
+}
+
+func TestHilbertNAtMaxRange(t *testing.T) {
+	coords := []uint32{math.MaxUint32, 0}
+	h := EncodeN(32, coords...)
+	assert.Equal(t, coords, DecodeN(h, 32, 2))
+
+	coords = []uint32{math.MaxUint16, 12345, 1, 0}
+	h = EncodeN(16, coords...)
+	assert.Equal(t, coords, DecodeN(h, 16, 4))
+}
+
+func TestHilbertNOneDimension(t *testing.T) {
+	assert.Equal(t, uint64(42), EncodeN(8, 42))
+	assert.Equal(t, []uint32{42}, DecodeN(42, 8, 1))
+}
+
+func TestHilbertNInvalid(t *testing.T) {
+	assert.Panics(t, func() { EncodeN(32, 1, 2, 3) })
+	assert.Panics(t, func() { EncodeN(0, 1) })
+	assert.Panics(t, func() { EncodeN(8) })
+	assert.Panics(t, func() { DecodeN(0, 33, 1) })
+}
+
+func BenchmarkEncodeN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		EncodeN(21, uint32(i), uint32(i), uint32(i))
+	}
+}
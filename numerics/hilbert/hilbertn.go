@@ -0,0 +1,134 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbert
+
+/*
+This file extends the encoder to any number of dimensions using the
+transpose algorithm described by John Skilling in "Programming the
+Hilbert curve" (2004).  Coordinates are transformed in place into the
+transposed form of the Hilbert index, whose bits are then interleaved
+into a single integer.  As the result is a uint64, dims*bits may not
+exceed 64.
+*/
+
+// checkN panics if the provided dimensions and bits cannot be encoded
+// into a single uint64.
+func checkN(dims int, bits uint) {
+	if dims < 1 || bits < 1 || bits > 32 || uint(dims)*bits > 64 {
+		panic(`hilbert: dims * bits must be in the range [1, 64] with at most 32 bits`)
+	}
+}
+
+// EncodeN will encode the provided coordinates into a distance along an
+// N-dimensional Hilbert curve where N is the number of coordinates.  Only
+// the lowest bits of every coordinate are used.  Panics if the number of
+// coordinates multiplied by bits is greater than 64.
+func EncodeN(bits uint, coords ...uint32) uint64 {
+	checkN(len(coords), bits)
+
+	x := make([]uint64, len(coords))
+	mask := uint64(1)<<bits - 1
+	for i, c := range coords {
+		x[i] = uint64(c) & mask
+	}
+
+	n := len(x)
+	m := uint64(1) << (bits - 1)
+
+	// inverse undo
+	for q := m; q > 1; q >>= 1 {
+		p := q - 1
+		for i := 0; i < n; i++ {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+
+	// gray encode
+	for i := 1; i < n; i++ {
+		x[i] ^= x[i-1]
+	}
+	var t uint64
+	for q := m; q > 1; q >>= 1 {
+		if x[n-1]&q != 0 {
+			t ^= q - 1
+		}
+	}
+	for i := 0; i < n; i++ {
+		x[i] ^= t
+	}
+
+	// interleave the transposed form, most significant bits first
+	var h uint64
+	for j := int(bits) - 1; j >= 0; j-- {
+		for i := 0; i < n; i++ {
+			h = h<<1 | (x[i]>>uint(j))&1
+		}
+	}
+
+	return h
+}
+
+// DecodeN will decode the provided distance along a Hilbert curve of the
+// provided number of dimensions back into its coordinates.  Panics if
+// dims multiplied by bits is greater than 64.
+func DecodeN(h uint64, bits uint, dims int) []uint32 {
+	checkN(dims, bits)
+
+	n := dims
+	x := make([]uint64, n)
+	for j := int(bits) - 1; j >= 0; j-- {
+		for i := 0; i < n; i++ {
+			shift := uint(j*n + n - 1 - i)
+			x[i] |= ((h >> shift) & 1) << uint(j)
+		}
+	}
+
+	// gray decode
+	t := x[n-1] >> 1
+	for i := n - 1; i > 0; i-- {
+		x[i] ^= x[i-1]
+	}
+	x[0] ^= t
+
+	// undo excess work
+	end := uint64(2) << (bits - 1)
+	for q := uint64(2); q != end; q <<= 1 {
+		p := q - 1
+		for i := n - 1; i >= 0; i-- {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+
+	coords := make([]uint32, n)
+	for i := range x {
+		coords[i] = uint32(x[i])
+	}
+
+	return coords
+}
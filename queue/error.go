@@ -29,4 +29,8 @@ var (
 	// ErrEmptyQueue is returned when an non-applicable queue operation was called
 	// due to the queue's empty item state
 	ErrEmptyQueue = errors.New(`queue: empty queue`)
+
+	// ErrExceedsCapacity is returned when more items are put to a bounded
+	// queue at once than it can ever hold.
+	ErrExceedsCapacity = errors.New(`queue: items exceed capacity`)
 )
@@ -18,13 +18,25 @@ limitations under the License.
 The priority queue is almost a spitting image of the logic
 used for a regular queue.  In order to keep the logic fast,
 this code is repeated instead of using casts to cast to interface{}
-back and forth.  If Go had inheritance and generics, this problem
-would be easier to solve.
+back and forth.  The queue is parameterized over its item type and
+ordered by a common.CompareFunc; PriorityQueue is the instantiation
+over the Item interface.
+
+The heap index of every item is tracked so an item can be reprioritized
+with Update or withdrawn with Remove in O(log n) time.  When duplicates
+are allowed nothing is tracked until Update or Remove is first called,
+so a queue only used with Put and Get pays nothing for them.
 */
 
 package queue
 
-import "sync"
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
 
 // Item is an item that can be added to the priority queue.
 type Item interface {
@@ -37,85 +49,194 @@ type Item interface {
 	Compare(other Item) int
 }
 
-type priorityItems []Item
+// compareItems is the CompareFunc used to order Items in a PriorityQueue.
+func compareItems(a, b Item) int {
+	return a.Compare(b)
+}
+
+type priorityItems[T any] []T
 
-func (items *priorityItems) swap(i, j int) {
-	(*items)[i], (*items)[j] = (*items)[j], (*items)[i]
+// slot records the index in the heap of an item.  When duplicates are
+// allowed, the slots of an item put more than once are linked through
+// prev and next.  Slots are kept in a slice and refer to each other by
+// their position in it, with -1 for none, so that tracking an item
+// does not allocate.
+type slot struct {
+	index      int
+	prev, next int
 }
 
-func (items *priorityItems) pop() Item {
-	size := len(*items)
-
-	// Move last leaf to root, and 'pop' the last item.
-	items.swap(size-1, 0)
-	item := (*items)[size-1] // Item to return.
-	(*items)[size-1], *items = nil, (*items)[:size-1]
-
-	// 'Bubble down' to restore heap property.
-	index := 0
-	childL, childR := 2*index+1, 2*index+2
-	for len(*items) > childL {
-		child := childL
-		if len(*items) > childR && (*items)[childR].Compare((*items)[childL]) < 0 {
-			child = childR
-		}
+// PriorityQueueOf is similar to queue except that it takes
+// items of type T and adds them to the queue in the priority
+// order defined by its CompareFunc.
+type PriorityQueueOf[T comparable] struct {
+	waiters         waiters
+	items           priorityItems[T]
+	handles         []int     // Slot of the item at each index of items
+	slots           []slot    // Slots in use and free slots
+	free            int       // First free slot, linked through next
+	positions       map[T]int // First slot of each item, nil if untracked
+	compare         common.CompareFunc[T]
+	lock            sync.Mutex
+	disposeLock     sync.Mutex
+	disposed        bool
+	allowDuplicates bool
+}
 
-		if (*items)[child].Compare((*items)[index]) < 0 {
-			items.swap(index, child)
+// PriorityQueue is a priority queue of items that implement
+// the Item interface.
+type PriorityQueue = PriorityQueueOf[Item]
 
-			index = child
-			childL, childR = 2*index+1, 2*index+2
-		} else {
+func (pq *PriorityQueueOf[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	if pq.positions != nil {
+		pq.handles[i], pq.handles[j] = pq.handles[j], pq.handles[i]
+		pq.slots[pq.handles[i]].index, pq.slots[pq.handles[j]].index = i, j
+	}
+}
+
+// up bubbles the item at index i up to restore the heap property.
+func (pq *PriorityQueueOf[T]) up(i int) {
+	items, compare := pq.items, pq.compare
+	for i > 0 {
+		parent := (i - 1) / 2
+		if compare(items[parent], items[i]) <= 0 {
 			break
 		}
-	}
 
-	return item
+		pq.swap(i, parent)
+		i = parent
+	}
 }
 
-func (items *priorityItems) get(number int) []Item {
-	returnItems := make([]Item, 0, number)
-	for i := 0; i < number; i++ {
-		if len(*items) == 0 {
+// down bubbles the item at index i down to restore the heap property
+// and returns a bool indicating if it moved.
+func (pq *PriorityQueueOf[T]) down(i int) bool {
+	items, compare := pq.items, pq.compare
+	start := i
+	for {
+		child := 2*i + 1
+		if child >= len(items) {
+			break
+		}
+
+		if right := child + 1; right < len(items) && compare(items[right], items[child]) < 0 {
+			child = right
+		}
+
+		if compare(items[child], items[i]) >= 0 {
 			break
 		}
 
-		returnItems = append(returnItems, items.pop())
+		pq.swap(i, child)
+		i = child
 	}
 
-	return returnItems
+	return i > start
+}
+
+// track starts tracking the index of every item, if it is not already.
+func (pq *PriorityQueueOf[T]) track() {
+	if pq.positions != nil {
+		return
+	}
+
+	pq.positions = make(map[T]int, len(pq.items))
+	pq.handles = make([]int, 0, len(pq.items))
+	for i, item := range pq.items {
+		pq.link(item, i)
+	}
 }
 
-func (items *priorityItems) push(item Item) {
+// link tracks the item at index i with a new slot, placed first among
+// the slots of the item.
+func (pq *PriorityQueueOf[T]) link(item T, i int) {
+	s := pq.free
+	if s >= 0 {
+		pq.free = pq.slots[s].next
+	} else {
+		s = len(pq.slots)
+		pq.slots = append(pq.slots, slot{})
+	}
+
+	pq.slots[s] = slot{index: i, prev: -1, next: -1}
+	if head, ok := pq.positions[item]; ok {
+		pq.slots[s].next = head
+		pq.slots[head].prev = s
+	}
+	pq.positions[item] = s
+	pq.handles = append(pq.handles, s)
+}
+
+// unlink stops tracking the item with the provided slot and frees it.
+func (pq *PriorityQueueOf[T]) unlink(item T, s int) {
+	sl := pq.slots[s]
+	switch {
+	case sl.prev >= 0:
+		pq.slots[sl.prev].next = sl.next
+	case sl.next >= 0:
+		pq.positions[item] = sl.next
+	default:
+		delete(pq.positions, item)
+	}
+	if sl.next >= 0 {
+		pq.slots[sl.next].prev = sl.prev
+	}
+
+	pq.slots[s] = slot{index: -1, prev: -1, next: pq.free}
+	pq.free = s
+}
+
+func (pq *PriorityQueueOf[T]) push(item T) {
 	// Stick the item as the end of the last level.
-	*items = append(*items, item)
+	i := len(pq.items)
+	pq.items = append(pq.items, item)
+	if pq.positions != nil {
+		pq.link(item, i)
+	}
 
 	// 'Bubble up' to restore heap property.
-	index := len(*items) - 1
-	parent := int((index - 1) / 2)
-	for parent >= 0 && (*items)[parent].Compare(item) > 0 {
-		items.swap(index, parent)
+	pq.up(i)
+}
+
+func (pq *PriorityQueueOf[T]) removeAt(i int) T {
+	var zero T
+	last := len(pq.items) - 1
+
+	// Move last leaf to the removed index, and 'pop' the last item.
+	pq.swap(i, last)
+	item := pq.items[last]
+	pq.items[last], pq.items = zero, pq.items[:last]
+	if pq.positions != nil {
+		s := pq.handles[last]
+		pq.handles = pq.handles[:last]
+		pq.unlink(item, s)
+	}
 
-		index = parent
-		parent = int((index - 1) / 2)
+	// 'Bubble down', or up, to restore heap property.
+	if i < last && !pq.down(i) {
+		pq.up(i)
 	}
+
+	return item
 }
 
-// PriorityQueue is similar to queue except that it takes
-// items that implement the Item interface and adds them
-// to the queue in priority order.
-type PriorityQueue struct {
-	waiters         waiters
-	items           priorityItems
-	itemMap         map[Item]struct{}
-	lock            sync.Mutex
-	disposeLock     sync.Mutex
-	disposed        bool
-	allowDuplicates bool
+// pop removes and returns up to number items in priority order.
+func (pq *PriorityQueueOf[T]) pop(number int) []T {
+	returnItems := make([]T, 0, number)
+	for i := 0; i < number; i++ {
+		if len(pq.items) == 0 {
+			break
+		}
+
+		returnItems = append(returnItems, pq.removeAt(0))
+	}
+
+	return returnItems
 }
 
 // Put adds items to the queue.
-func (pq *PriorityQueue) Put(items ...Item) error {
+func (pq *PriorityQueueOf[T]) Put(items ...T) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -128,11 +249,8 @@ func (pq *PriorityQueue) Put(items ...Item) error {
 	}
 
 	for _, item := range items {
-		if pq.allowDuplicates {
-			pq.items.push(item)
-		} else if _, ok := pq.itemMap[item]; !ok {
-			pq.itemMap[item] = struct{}{}
-			pq.items.push(item)
+		if _, ok := pq.positions[item]; pq.allowDuplicates || !ok {
+			pq.push(item)
 		}
 	}
 
@@ -143,8 +261,12 @@ func (pq *PriorityQueue) Put(items ...Item) error {
 		}
 
 		sema.response.Add(1)
-		sema.ready <- true
-		sema.response.Wait()
+		select {
+		case sema.ready <- true:
+			sema.response.Wait()
+		default:
+			// This waiter's context was canceled.
+		}
 		if len(pq.items) == 0 {
 			break
 		}
@@ -153,10 +275,35 @@ func (pq *PriorityQueue) Put(items ...Item) error {
 	return nil
 }
 
+// PutContext adds items to the queue unless the provided context is
+// already done, in which case ctx.Err() is returned and no items are
+// added.
+func (pq *PriorityQueueOf[T]) PutContext(ctx context.Context, items ...T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return pq.Put(items...)
+}
+
 // Get retrieves items from the queue.  If the queue is empty,
 // this call blocks until the next item is added to the queue.  This
 // will attempt to retrieve number of items.
-func (pq *PriorityQueue) Get(number int) ([]Item, error) {
+func (pq *PriorityQueueOf[T]) Get(number int) ([]T, error) {
+	return pq.get(context.Background(), number)
+}
+
+// GetContext is like Get but will also return ctx.Err() if the
+// provided context is done before items are added to the queue.
+func (pq *PriorityQueueOf[T]) GetContext(ctx context.Context, number int) ([]T, error) {
+	return pq.get(ctx, number)
+}
+
+func (pq *PriorityQueueOf[T]) get(ctx context.Context, number int) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if number < 1 {
 		return nil, nil
 	}
@@ -168,53 +315,131 @@ func (pq *PriorityQueue) Get(number int) ([]Item, error) {
 		return nil, ErrDisposed
 	}
 
-	var items []Item
-
-	// Remove references to popped items.
-	deleteItems := func(items []Item) {
-		for _, item := range items {
-			delete(pq.itemMap, item)
-		}
-	}
+	var items []T
 
 	if len(pq.items) == 0 {
 		sema := newSema()
 		pq.waiters.put(sema)
 		pq.lock.Unlock()
 
-		<-sema.ready
+		select {
+		case <-sema.ready:
+		case <-ctx.Done():
+			pq.waiters.abandon(sema, &pq.lock)
+			return nil, ctx.Err()
+		}
 
 		if pq.Disposed() {
 			return nil, ErrDisposed
 		}
 
-		items = pq.items.get(number)
-		if !pq.allowDuplicates {
-			deleteItems(items)
-		}
+		items = pq.pop(number)
 		sema.response.Done()
 		return items, nil
 	}
 
-	items = pq.items.get(number)
-	deleteItems(items)
+	items = pq.pop(number)
 	pq.lock.Unlock()
 	return items, nil
 }
 
+// Update restores the order of the queue after the priority of the
+// provided item, which is already in the queue, has changed.  This takes
+// O(log n) time.  Items are found by equality so this is meant for items
+// which act as handles, like pointers, whose priority can change while
+// they remain equal.  If duplicates are allowed every occurrence of the
+// item is updated.  A bool is returned indicating if the item was found
+// in the queue.
+func (pq *PriorityQueueOf[T]) Update(item T) (bool, error) {
+	pq.lock.Lock()
+	defer pq.lock.Unlock()
+
+	if pq.disposed {
+		return false, ErrDisposed
+	}
+
+	pq.track()
+	s, ok := pq.positions[item]
+	if !ok {
+		return false, nil
+	}
+
+	if i := pq.slots[s].index; pq.slots[s].next < 0 {
+		if !pq.down(i) {
+			pq.up(i)
+		}
+		return true, nil
+	}
+
+	// Every occurrence shares the new priority so they all need to move
+	// the same way.  Occurrences are moved up from the top of the heap
+	// down, or down from the bottom up, so each sees a valid heap apart
+	// from the occurrences yet to be moved.
+	slots := make([]int, 0, 2)
+	for ; s >= 0; s = pq.slots[s].next {
+		slots = append(slots, s)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return pq.slots[slots[i]].index < pq.slots[slots[j]].index
+	})
+
+	decreased := false
+	for _, s := range slots {
+		if i := pq.slots[s].index; i > 0 && pq.compare(pq.items[i], pq.items[(i-1)/2]) < 0 {
+			decreased = true
+			break
+		}
+	}
+
+	if decreased {
+		for _, s := range slots {
+			pq.up(pq.slots[s].index)
+		}
+	} else {
+		for i := len(slots) - 1; i >= 0; i-- {
+			pq.down(pq.slots[slots[i]].index)
+		}
+	}
+
+	return true, nil
+}
+
+// Remove removes the provided item from the queue in O(log n) time.
+// If duplicates are allowed and the item was put more than once, a
+// single occurrence is removed.  A bool is returned indicating if the
+// item was found in the queue.
+func (pq *PriorityQueueOf[T]) Remove(item T) (bool, error) {
+	pq.lock.Lock()
+	defer pq.lock.Unlock()
+
+	if pq.disposed {
+		return false, ErrDisposed
+	}
+
+	pq.track()
+	s, ok := pq.positions[item]
+	if !ok {
+		return false, nil
+	}
+
+	pq.removeAt(pq.slots[s].index)
+	return true, nil
+}
+
 // Peek will look at the next item without removing it from the queue.
-func (pq *PriorityQueue) Peek() Item {
+func (pq *PriorityQueueOf[T]) Peek() T {
 	pq.lock.Lock()
 	defer pq.lock.Unlock()
 	if len(pq.items) > 0 {
 		return pq.items[0]
 	}
-	return nil
+	var zero T
+	return zero
 }
 
 // Empty returns a bool indicating if there are any items left
 // in the queue.
-func (pq *PriorityQueue) Empty() bool {
+func (pq *PriorityQueueOf[T]) Empty() bool {
 	pq.lock.Lock()
 	defer pq.lock.Unlock()
 
@@ -222,7 +447,7 @@ func (pq *PriorityQueue) Empty() bool {
 }
 
 // Len returns a number indicating how many items are in the queue.
-func (pq *PriorityQueue) Len() int {
+func (pq *PriorityQueueOf[T]) Len() int {
 	pq.lock.Lock()
 	defer pq.lock.Unlock()
 
@@ -230,7 +455,7 @@ func (pq *PriorityQueue) Len() int {
 }
 
 // Disposed returns a bool indicating if this queue has been disposed.
-func (pq *PriorityQueue) Disposed() bool {
+func (pq *PriorityQueueOf[T]) Disposed() bool {
 	pq.disposeLock.Lock()
 	defer pq.disposeLock.Unlock()
 
@@ -239,7 +464,7 @@ func (pq *PriorityQueue) Disposed() bool {
 
 // Dispose will prevent any further reads/writes to this queue
 // and frees available resources.
-func (pq *PriorityQueue) Dispose() {
+func (pq *PriorityQueueOf[T]) Dispose() {
 	pq.lock.Lock()
 	defer pq.lock.Unlock()
 
@@ -249,18 +474,44 @@ func (pq *PriorityQueue) Dispose() {
 	pq.disposed = true
 	for _, waiter := range pq.waiters {
 		waiter.response.Add(1)
-		waiter.ready <- true
+		select {
+		case waiter.ready <- true:
+			// release Get immediately
+		default:
+			// ignore if its context was canceled
+		}
 	}
 
 	pq.items = nil
+	pq.handles = nil
+	pq.slots = nil
+	pq.free = -1
+	pq.positions = nil
 	pq.waiters = nil
 }
 
 // NewPriorityQueue is the constructor for a priority queue.
 func NewPriorityQueue(hint int, allowDuplicates bool) *PriorityQueue {
-	return &PriorityQueue{
-		items:           make(priorityItems, 0, hint),
-		itemMap:         make(map[Item]struct{}, hint),
+	return NewPriorityQueueOf[Item](hint, allowDuplicates, compareItems)
+}
+
+// NewPriorityQueueOf is the constructor for a priority queue holding
+// items of type T.  Items are returned in ascending order as defined
+// by compare.
+func NewPriorityQueueOf[T comparable](hint int, allowDuplicates bool, compare common.CompareFunc[T]) *PriorityQueueOf[T] {
+	pq := &PriorityQueueOf[T]{
+		items:           make(priorityItems[T], 0, hint),
+		free:            -1,
+		compare:         compare,
 		allowDuplicates: allowDuplicates,
 	}
+	// Without duplicates positions are needed to find items already in
+	// the queue, so they are tracked from the start
+	if !allowDuplicates {
+		pq.handles = make([]int, 0, hint)
+		pq.slots = make([]slot, 0, hint)
+		pq.positions = make(map[T]int, hint)
+	}
+
+	return pq
 }
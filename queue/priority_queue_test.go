@@ -17,10 +17,15 @@ limitations under the License.
 package queue
 
 import (
+	"context"
+	"math/rand"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func TestPriorityPut(t *testing.T) {
@@ -231,6 +236,315 @@ func BenchmarkPriorityQueue(b *testing.B) {
 	wg.Wait()
 }
 
+func BenchmarkPriorityQueuePutGet(b *testing.B) {
+	for _, allowDuplicates := range []bool{false, true} {
+		name := "unique"
+		if allowDuplicates {
+			name = "duplicates"
+		}
+		b.Run(name, func(b *testing.B) {
+			items := make([]Item, 1000)
+			for i := range items {
+				items[i] = mockItem(rand.Intn(1000))
+			}
+			q := NewPriorityQueue(len(items), allowDuplicates)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q.Put(items...)
+				q.Get(len(items))
+			}
+		})
+	}
+}
+
+func BenchmarkPriorityQueueRemoveDuplicates(b *testing.B) {
+	jobs := []*job{{3}, {1}, {4}, {2}}
+	q := NewPriorityQueueOf(b.N, true, compareJobs)
+	for i := 0; i < b.N; i++ {
+		q.Put(jobs[i%len(jobs)])
+	}
+	q.Update(jobs[0])
+
+	b.ResetTimer()
+	q.Get(b.N)
+}
+
+func TestPriorityGetContext(t *testing.T) {
+	q := NewPriorityQueue(1, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	result, err := q.GetContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, result)
+	assert.Len(t, q.waiters, 0)
+
+	// puts must not block on the canceled waiter
+	assert.Nil(t, q.Put(mockItem(1)))
+	_, err = q.GetContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+
+	result, err = q.GetContext(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []Item{mockItem(1)}, result)
+}
+
+func TestPriorityPutContext(t *testing.T) {
+	q := NewPriorityQueue(1, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, context.Canceled, q.PutContext(ctx, mockItem(1)))
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestPriorityGetContextDoesNotLoseItems(t *testing.T) {
+	q := NewPriorityQueueOf(0, true, common.Ordered[int]())
+	numItems := 1000
+	var lock sync.Mutex
+	received := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Microsecond)
+				items, err := q.GetContext(ctx, 1)
+				cancel()
+				if err == ErrDisposed {
+					return
+				}
+				lock.Lock()
+				received += len(items)
+				lock.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < numItems; i++ {
+		assert.Nil(t, q.Put(i))
+	}
+	for {
+		lock.Lock()
+		done := received == numItems
+		lock.Unlock()
+		if done {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	q.Dispose()
+	wg.Wait()
+}
+
+type job struct {
+	priority int
+}
+
+func compareJobs(a, b *job) int {
+	return a.priority - b.priority
+}
+
+func TestPriorityUpdate(t *testing.T) {
+	q := NewPriorityQueueOf(3, false, compareJobs)
+	a, b, c := &job{5}, &job{3}, &job{8}
+	q.Put(a, b, c)
+
+	a.priority = 1
+	ok, err := q.Update(a)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, a, q.Peek())
+
+	a.priority = 10
+	ok, err = q.Update(a)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = q.Update(&job{3})
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	result, err := q.Get(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []*job{b, c, a}, result)
+
+	// items that have been retrieved are no longer in the queue
+	ok, err = q.Update(a)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestPriorityRemove(t *testing.T) {
+	q := NewPriorityQueueOf(3, false, compareJobs)
+	a, b, c := &job{5}, &job{3}, &job{8}
+	q.Put(a, b, c)
+
+	ok, err := q.Remove(b)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, q.Len())
+
+	ok, err = q.Remove(b)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// a removed item may be put again
+	q.Put(b)
+	result, err := q.Get(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []*job{b, a, c}, result)
+}
+
+func TestPriorityUpdateRemoveDuplicates(t *testing.T) {
+	q := NewPriorityQueueOf(3, true, compareJobs)
+	a, b := &job{5}, &job{3}
+	q.Put(a, b, a)
+	assert.Equal(t, 3, q.Len())
+
+	a.priority = 1
+	ok, err := q.Update(a)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = q.Remove(a)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	result, err := q.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*job{a, b}, result)
+
+	ok, err = q.Remove(a)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+}
+
+func TestPriorityUpdateRemoveRandom(t *testing.T) {
+	for _, allowDuplicates := range []bool{false, true} {
+		q := NewPriorityQueueOf(0, allowDuplicates, compareJobs)
+		jobs := make([]*job, 0, 200)
+		for i := 0; i < 200; i++ {
+			j := &job{rand.Intn(1000)}
+			jobs = append(jobs, j)
+			q.Put(j)
+			if allowDuplicates && i%10 == 0 {
+				q.Put(j)
+			}
+		}
+
+		removed := make(map[*job]bool)
+		for i := 0; i < 500; i++ {
+			j := jobs[rand.Intn(len(jobs))]
+			if rand.Intn(4) == 0 {
+				ok, err := q.Remove(j)
+				assert.Nil(t, err)
+				// duplicates remain until every occurrence is removed
+				if _, present := q.positions[j]; ok && !present {
+					removed[j] = true
+				}
+			} else {
+				j.priority = rand.Intn(1000)
+				ok, err := q.Update(j)
+				assert.Nil(t, err)
+				assert.Equal(t, !removed[j], ok)
+			}
+		}
+
+		result, err := q.Get(q.Len())
+		assert.Nil(t, err)
+		for i := 1; i < len(result); i++ {
+			assert.True(t, result[i-1].priority <= result[i].priority)
+		}
+		for _, j := range result {
+			assert.False(t, removed[j])
+		}
+		assert.Len(t, q.positions, 0)
+	}
+}
+
+func TestPriorityUpdateRemoveDisposed(t *testing.T) {
+	q := NewPriorityQueueOf(1, false, compareJobs)
+	a := &job{1}
+	q.Put(a)
+	q.Dispose()
+
+	_, err := q.Update(a)
+	assert.Equal(t, ErrDisposed, err)
+	_, err = q.Remove(a)
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestPriorityDisposeReleasesItems(t *testing.T) {
+	q := NewPriorityQueueOf(1, false, compareJobs)
+	q.Put(&job{1}, &job{2})
+	q.Dispose()
+
+	assert.Nil(t, q.items)
+	assert.Nil(t, q.handles)
+	assert.Nil(t, q.slots)
+	assert.Nil(t, q.positions)
+}
+
+func TestPriorityTracksDuplicatesOnDemand(t *testing.T) {
+	q := NewPriorityQueueOf(0, true, compareJobs)
+	a, b := &job{2}, &job{1}
+	q.Put(a, b, a)
+	assert.Nil(t, q.positions)
+
+	ok, err := q.Remove(a)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Len(t, q.positions, 2)
+
+	// items put once tracked are tracked too
+	c := &job{0}
+	q.Put(c)
+	ok, err = q.Remove(c)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	result, err := q.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []*job{b, a}, result)
+	assert.Len(t, q.positions, 0)
+}
+
+func TestPriorityManyDuplicates(t *testing.T) {
+	q := NewPriorityQueueOf(0, true, compareJobs)
+	a, b := &job{1}, &job{2}
+	for i := 0; i < 1000; i++ {
+		q.Put(a, b)
+	}
+	ok, _ := q.Remove(b)
+	assert.True(t, ok)
+	assert.Len(t, q.slots, 2000)
+
+	result, err := q.Get(1500)
+	assert.Nil(t, err)
+	for i, j := range result {
+		if i < 1000 {
+			assert.Equal(t, a, j)
+		} else {
+			assert.Equal(t, b, j)
+		}
+	}
+
+	// freed slots are reused
+	for i := 0; i < 1000; i++ {
+		q.Put(a)
+	}
+	assert.Equal(t, 1499, q.Len())
+	assert.Len(t, q.slots, 2000)
+}
+
 func TestPriorityPeek(t *testing.T) {
 	q := NewPriorityQueue(1, false)
 	q.Put(mockItem(1))
@@ -253,3 +567,16 @@ func TestAllowDuplicates(t *testing.T) {
 
 	assert.Equal(t, 2, q.Len())
 }
+
+func TestPriorityQueueOf(t *testing.T) {
+	q := NewPriorityQueueOf(3, false, common.Reverse(common.Ordered[string]()))
+	q.Put(`b`, `a`, `c`, `a`)
+
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, `c`, q.Peek())
+
+	result, err := q.Get(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{`c`, `b`, `a`}, result)
+	assert.Equal(t, ``, q.Peek())
+}
@@ -34,6 +34,22 @@ the capability to release blocked threads.  This works for both puts
 and gets, either will return an error if they are blocked and the buffer
 is disposed.  This could serve as a signal to kill a goroutine.  All threadsafety
 is acheived using CAS operations, making this buffer pretty quick.
+PutMany, OfferMany and GetMany move a run of items with a single
+reservation, which amortizes that cost over the whole batch.
+
+Each queue is generic over its item type: QueueOf, RingBufferOf and
+PriorityQueueOf can be constructed with NewOf, NewRingBufferOf and
+NewPriorityQueueOf respectively.  Queue, RingBuffer and PriorityQueue are
+the interface{} (or Item) instantiations of those types and remain the
+default for compatibility.
+
+Queues created with NewBounded or NewBoundedOf hold a limited number of
+items.  Puts to a full bounded queue block, or fail with a timeout or
+context error, until consumers make room, and are served in the order
+they were made.
+
+Blocking operations have context aware variants, GetContext, PollContext
+and PutContext, which return ctx.Err() once the provided context is done.
 
 Benchmarks:
 BenchmarkPriorityQueue-8	 		2000000	       782 ns/op
@@ -52,6 +68,7 @@ TODO: Unify the types of queue to the same interface.
 package queue
 
 import (
+	"context"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -91,10 +108,30 @@ func (w *waiters) remove(sema *sema) {
 	*w = newWs
 }
 
-type items []interface{}
+// abandon is called by a waiter that stops waiting on the provided
+// sema, due to a timeout or a canceled context, before receiving any
+// items.  If the sema has not been signaled yet it is removed from the
+// waiters, otherwise the signaling Put is waiting on a response and is
+// released so it may move on to the next waiter.
+func (w *waiters) abandon(sema *sema, lock sync.Locker) {
+	select {
+	case sema.ready <- true:
+		// we called this before Put() could
+		// remove sema from waiters.
+		lock.Lock()
+		w.remove(sema)
+		lock.Unlock()
+	default:
+		// Put() got it already, we need to call Done() so Put() can move on
+		sema.response.Done()
+	}
+}
+
+type items[T any] []T
 
-func (items *items) get(number int64) []interface{} {
-	returnItems := make([]interface{}, 0, number)
+func (items *items[T]) get(number int64) []T {
+	var zero T
+	returnItems := make([]T, 0, number)
 	index := int64(0)
 	for i := int64(0); i < number; i++ {
 		if i >= int64(len(*items)) {
@@ -102,7 +139,7 @@ func (items *items) get(number int64) []interface{} {
 		}
 
 		returnItems = append(returnItems, (*items)[i])
-		(*items)[i] = nil
+		(*items)[i] = zero
 		index++
 	}
 
@@ -110,26 +147,28 @@ func (items *items) get(number int64) []interface{} {
 	return returnItems
 }
 
-func (items *items) peek() (interface{}, bool) {
+func (items *items[T]) peek() (T, bool) {
 	length := len(*items)
 
 	if length == 0 {
-		return nil, false
+		var zero T
+		return zero, false
 	}
 
 	return (*items)[0], true
 }
 
-func (items *items) getUntil(checker func(item interface{}) bool) []interface{} {
+func (items *items[T]) getUntil(checker func(item T) bool) []T {
 	length := len(*items)
 
 	if len(*items) == 0 {
 		// returning nil here actually wraps that nil in a list
 		// of interfaces... thanks go
-		return []interface{}{}
+		return []T{}
 	}
 
-	returnItems := make([]interface{}, 0, length)
+	var zero T
+	returnItems := make([]T, 0, length)
 	index := -1
 	for i, item := range *items {
 		if !checker(item) {
@@ -138,7 +177,7 @@ func (items *items) getUntil(checker func(item interface{}) bool) []interface{}
 
 		returnItems = append(returnItems, item)
 		index = i
-		(*items)[i] = nil // prevent memory leak
+		(*items)[i] = zero // prevent memory leak
 	}
 
 	*items = (*items)[index+1:]
@@ -157,33 +196,71 @@ func newSema() *sema {
 	}
 }
 
-// Queue is the struct responsible for tracking the state
-// of the queue.
-type Queue struct {
+// putter is a Put blocked on a full bounded queue.  Its items are
+// added by whichever call makes room for them, after which done is
+// closed.  If the queue is disposed first, err is set before done
+// is closed.
+type putter[T any] struct {
+	items []T
+	done  chan struct{}
+	err   error
+}
+
+// QueueOf is the struct responsible for tracking the state
+// of a queue holding items of type T.
+type QueueOf[T any] struct {
 	waiters  waiters
-	items    items
+	putters  []*putter[T]
+	items    items[T]
 	lock     sync.Mutex
+	capacity int64
 	disposed bool
 }
 
-// Put will add the specified items to the queue.
-func (q *Queue) Put(items ...interface{}) error {
-	if len(items) == 0 {
-		return nil
+// Queue is a queue of interface{} items.
+type Queue = QueueOf[interface{}]
+
+// fits returns a bool indicating if the provided number of items can
+// be added to the queue without waiting.  Blocked puts are served
+// first so no items fit while any are waiting.  Must be called with
+// the lock held.
+func (q *QueueOf[T]) fits(number int) bool {
+	if q.capacity <= 0 {
+		return true
 	}
 
-	q.lock.Lock()
+	return len(q.putters) == 0 && int64(len(q.items)+number) <= q.capacity
+}
 
-	if q.disposed {
-		q.lock.Unlock()
-		return ErrDisposed
+// admit adds the items of blocked puts, in the order they arrived, for
+// as long as there is room in the queue.  Must be called with the lock
+// held.
+func (q *QueueOf[T]) admit() {
+	for len(q.putters) > 0 {
+		p := q.putters[0]
+		if int64(len(q.items)+len(p.items)) > q.capacity {
+			return
+		}
+
+		q.items = append(q.items, p.items...)
+		q.putters[0] = nil
+		q.putters = q.putters[1:]
+		close(p.done)
 	}
+}
 
-	q.items = append(q.items, items...)
+// notify admits blocked puts and hands items to waiting gets until
+// either runs out.  Must be called with the lock held.
+func (q *QueueOf[T]) notify() {
 	for {
+		q.admit()
+		if len(q.items) == 0 {
+			return
+		}
+
 		sema := q.waiters.get()
 		if sema == nil {
-			break
+			return
 		}
 		sema.response.Add(1)
 		select {
@@ -192,21 +269,140 @@ func (q *Queue) Put(items ...interface{}) error {
 		default:
 			// This semaphore timed out.
 		}
-		if len(q.items) == 0 {
-			break
-		}
+	}
+}
+
+// Put will add the specified items to the queue.  If the queue is
+// bounded and there is not enough room for all of the items, this call
+// will block until there is.  Blocked puts are completed in the order
+// they were made.
+func (q *QueueOf[T]) Put(items ...T) error {
+	return q.put(context.Background(), items, 0)
+}
+
+// PutTimeout is like Put but will return ErrTimeout if there is still
+// not enough room for the items once the provided timeout is reached.
+// A non-positive timeout will block until there is.
+func (q *QueueOf[T]) PutTimeout(timeout time.Duration, items ...T) error {
+	return q.put(context.Background(), items, timeout)
+}
+
+// PutContext is like Put but will return ctx.Err() if the provided
+// context is done before there is enough room for the items.  On any
+// error no items are added.
+func (q *QueueOf[T]) PutContext(ctx context.Context, items ...T) error {
+	return q.put(ctx, items, 0)
+}
+
+// Offer will add the specified items to the queue if there is room for
+// all of them without blocking and returns a bool indicating if they
+// were added.
+func (q *QueueOf[T]) Offer(items ...T) (bool, error) {
+	if len(items) == 0 {
+		return true, nil
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.disposed {
+		return false, ErrDisposed
+	}
+
+	if q.capacity > 0 && int64(len(items)) > q.capacity {
+		return false, ErrExceedsCapacity
+	}
+
+	if !q.fits(len(items)) {
+		return false, nil
+	}
+
+	q.items = append(q.items, items...)
+	q.notify()
+	return true, nil
+}
+
+func (q *QueueOf[T]) put(ctx context.Context, items []T, timeout time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	q.lock.Lock()
+
+	if q.disposed {
+		q.lock.Unlock()
+		return ErrDisposed
 	}
 
+	if q.fits(len(items)) {
+		q.items = append(q.items, items...)
+		q.notify()
+		q.lock.Unlock()
+		return nil
+	}
+
+	if int64(len(items)) > q.capacity {
+		q.lock.Unlock()
+		return ErrExceedsCapacity
+	}
+
+	p := &putter[T]{items: items, done: make(chan struct{})}
+	q.putters = append(q.putters, p)
 	q.lock.Unlock()
-	return nil
+
+	var timeoutC <-chan time.Time
+	if timeout > 0 {
+		timeoutC = time.After(timeout)
+	}
+
+	var err error
+	select {
+	case <-p.done:
+		return p.err
+	case <-timeoutC:
+		err = ErrTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	select {
+	case <-p.done:
+		// the items were added, or the queue disposed, before
+		// we could give up
+		return p.err
+	default:
+	}
+
+	for i := range q.putters {
+		if q.putters[i] == p {
+			q.putters = append(q.putters[:i], q.putters[i+1:]...)
+			break
+		}
+	}
+	// the puts behind this one may now fit
+	q.notify()
+	return err
 }
 
 // Get retrieves items from the queue.  If there are some items in the
 // queue, get will return a number UP TO the number passed in as a
 // parameter.  If no items are in the queue, this method will pause
 // until items are added to the queue.
-func (q *Queue) Get(number int64) ([]interface{}, error) {
-	return q.Poll(number, 0)
+func (q *QueueOf[T]) Get(number int64) ([]T, error) {
+	return q.poll(context.Background(), number, 0)
+}
+
+// GetContext is like Get but will also return ctx.Err() if the
+// provided context is done before items are added to the queue.
+func (q *QueueOf[T]) GetContext(ctx context.Context, number int64) ([]T, error) {
+	return q.poll(ctx, number, 0)
 }
 
 // Poll retrieves items from the queue.  If there are some items in the queue,
@@ -214,10 +410,25 @@ func (q *Queue) Get(number int64) ([]interface{}, error) {
 // items are in the queue, this method will pause until items are added to the
 // queue or the provided timeout is reached.  A non-positive timeout will block
 // until items are added.  If a timeout occurs, ErrTimeout is returned.
-func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error) {
+func (q *QueueOf[T]) Poll(number int64, timeout time.Duration) ([]T, error) {
+	return q.poll(context.Background(), number, timeout)
+}
+
+// PollContext is like Poll but will also return ctx.Err() if the
+// provided context is done before items are added to the queue or the
+// timeout is reached.
+func (q *QueueOf[T]) PollContext(ctx context.Context, number int64, timeout time.Duration) ([]T, error) {
+	return q.poll(ctx, number, timeout)
+}
+
+func (q *QueueOf[T]) poll(ctx context.Context, number int64, timeout time.Duration) ([]T, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if number < 1 {
 		// thanks again go
-		return []interface{}{}, nil
+		return []T{}, nil
 	}
 
 	q.lock.Lock()
@@ -227,7 +438,7 @@ func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error)
 		return nil, ErrDisposed
 	}
 
-	var items []interface{}
+	var items []T
 
 	if len(q.items) == 0 {
 		sema := newSema()
@@ -249,39 +460,36 @@ func (q *Queue) Poll(number int64, timeout time.Duration) ([]interface{}, error)
 			return items, nil
 		case <-timeoutC:
 			// cleanup the sema that was added to waiters
-			select {
-			case sema.ready <- true:
-				// we called this before Put() could
-				// Remove sema from waiters.
-				q.lock.Lock()
-				q.waiters.remove(sema)
-				q.lock.Unlock()
-			default:
-				// Put() got it already, we need to call Done() so Put() can move on
-				sema.response.Done()
-			}
+			q.waiters.abandon(sema, &q.lock)
 			return nil, ErrTimeout
+		case <-ctx.Done():
+			q.waiters.abandon(sema, &q.lock)
+			return nil, ctx.Err()
 		}
 	}
 
 	items = q.items.get(number)
+	if len(q.putters) > 0 {
+		q.notify()
+	}
 	q.lock.Unlock()
 	return items, nil
 }
 
 // Peek returns a the first item in the queue by value
 // without modifying the queue.
-func (q *Queue) Peek() (interface{}, error) {
+func (q *QueueOf[T]) Peek() (T, error) {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
 	if q.disposed {
-		return nil, ErrDisposed
+		var zero T
+		return zero, ErrDisposed
 	}
 
 	peekItem, ok := q.items.peek()
 	if !ok {
-		return nil, ErrEmptyQueue
+		return peekItem, ErrEmptyQueue
 	}
 
 	return peekItem, nil
@@ -290,7 +498,7 @@ func (q *Queue) Peek() (interface{}, error) {
 // TakeUntil takes a function and returns a list of items that
 // match the checker until the checker returns false.  This does not
 // wait if there are no items in the queue.
-func (q *Queue) TakeUntil(checker func(item interface{}) bool) ([]interface{}, error) {
+func (q *QueueOf[T]) TakeUntil(checker func(item T) bool) ([]T, error) {
 	if checker == nil {
 		return nil, nil
 	}
@@ -303,12 +511,15 @@ func (q *Queue) TakeUntil(checker func(item interface{}) bool) ([]interface{}, e
 	}
 
 	result := q.items.getUntil(checker)
+	if len(q.putters) > 0 {
+		q.notify()
+	}
 	q.lock.Unlock()
 	return result, nil
 }
 
 // Empty returns a bool indicating if this bool is empty.
-func (q *Queue) Empty() bool {
+func (q *QueueOf[T]) Empty() bool {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -316,7 +527,7 @@ func (q *Queue) Empty() bool {
 }
 
 // Len returns the number of items in this queue.
-func (q *Queue) Len() int64 {
+func (q *QueueOf[T]) Len() int64 {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -325,7 +536,7 @@ func (q *Queue) Len() int64 {
 
 // Disposed returns a bool indicating if this queue
 // has had disposed called on it.
-func (q *Queue) Disposed() bool {
+func (q *QueueOf[T]) Disposed() bool {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -335,7 +546,7 @@ func (q *Queue) Disposed() bool {
 // Dispose will dispose of this queue and returns
 // the items disposed. Any subsequent calls to Get
 // or Put will return an error.
-func (q *Queue) Dispose() []interface{} {
+func (q *QueueOf[T]) Dispose() []T {
 	q.lock.Lock()
 	defer q.lock.Unlock()
 
@@ -350,18 +561,55 @@ func (q *Queue) Dispose() []interface{} {
 		}
 	}
 
+	for _, p := range q.putters {
+		p.err = ErrDisposed
+		close(p.done)
+	}
+
 	disposedItems := q.items
 
 	q.items = nil
 	q.waiters = nil
+	q.putters = nil
 
 	return disposedItems
 }
 
+// Cap returns the capacity of this queue or 0 if it is unbounded.
+func (q *QueueOf[T]) Cap() int64 {
+	return q.capacity
+}
+
 // New is a constructor for a new threadsafe queue.
 func New(hint int64) *Queue {
-	return &Queue{
-		items: make([]interface{}, 0, hint),
+	return NewOf[interface{}](hint)
+}
+
+// NewOf is a constructor for a new threadsafe queue holding
+// items of type T.
+func NewOf[T any](hint int64) *QueueOf[T] {
+	return &QueueOf[T]{
+		items: make([]T, 0, hint),
+	}
+}
+
+// NewBounded is a constructor for a new threadsafe queue holding at
+// most capacity items.  Puts to a full queue will block until items
+// are removed.  A non-positive capacity results in an unbounded queue.
+func NewBounded(capacity int64) *Queue {
+	return NewBoundedOf[interface{}](capacity)
+}
+
+// NewBoundedOf is a constructor for a new threadsafe queue holding at
+// most capacity items of type T.
+func NewBoundedOf[T any](capacity int64) *QueueOf[T] {
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	return &QueueOf[T]{
+		items:    make([]T, 0, capacity),
+		capacity: capacity,
 	}
 }
 
@@ -17,6 +17,8 @@ limitations under the License.
 package queue
 
 import (
+	"context"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -137,6 +139,87 @@ func TestPollNoMemoryLeak(t *testing.T) {
 	}
 }
 
+func TestGetContext(t *testing.T) {
+	q := New(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	result, err := q.GetContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, result)
+	assert.Len(t, q.waiters, 0)
+
+	// a done context fails fast even with items present
+	q.Put(`a`)
+	_, err = q.GetContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+
+	result, err = q.GetContext(context.Background(), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{`a`}, result)
+}
+
+func TestPollContext(t *testing.T) {
+	q := New(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	_, err := q.PollContext(ctx, 1, time.Second)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, q.waiters, 0)
+
+	_, err = q.PollContext(context.Background(), 1, time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+	assert.Len(t, q.waiters, 0)
+}
+
+func TestPutContext(t *testing.T) {
+	q := New(10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	assert.Nil(t, q.PutContext(ctx, `a`))
+	cancel()
+	assert.Equal(t, context.Canceled, q.PutContext(ctx, `b`))
+	assert.Equal(t, int64(1), q.Len())
+}
+
+func TestGetContextDoesNotLoseItems(t *testing.T) {
+	q := NewOf[int](0)
+	numItems := 1000
+	var received int64
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				timeout := time.Duration(rand.Intn(100)) * time.Microsecond
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				items, err := q.GetContext(ctx, 1)
+				cancel()
+				if err == ErrDisposed {
+					return
+				}
+				atomic.AddInt64(&received, int64(len(items)))
+			}
+		}()
+	}
+
+	for i := 0; i < numItems; i++ {
+		assert.Nil(t, q.Put(i))
+	}
+	for atomic.LoadInt64(&received) < int64(numItems) {
+		time.Sleep(time.Millisecond)
+	}
+
+	q.Dispose()
+	wg.Wait()
+	assert.Equal(t, int64(numItems), received)
+}
+
 func TestAddEmptyPut(t *testing.T) {
 	q := New(10)
 
@@ -519,6 +602,235 @@ func TestExecuteInParallelEmptyQueue(t *testing.T) {
 	})
 }
 
+func TestQueueOf(t *testing.T) {
+	q := NewOf[int](10)
+
+	q.Put(1, 2, 3)
+	assert.Equal(t, int64(3), q.Len())
+
+	peek, err := q.Peek()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, peek)
+
+	result, err := q.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, result)
+
+	result, err = q.TakeUntil(func(item int) bool {
+		return item < 3
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, result)
+
+	assert.Equal(t, []int{3}, q.Dispose())
+	_, err = q.Peek()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+// waitForPutters blocks until the provided number of puts are blocked
+// on the queue.
+func waitForPutters[T any](q *QueueOf[T], number int) {
+	for {
+		q.lock.Lock()
+		blocked := len(q.putters)
+		q.lock.Unlock()
+		if blocked == number {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBoundedPut(t *testing.T) {
+	q := NewBoundedOf[int](2)
+	assert.Equal(t, int64(2), q.Cap())
+	assert.Nil(t, q.Put(1, 2))
+
+	ok, err := q.Offer(3)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	done := make(chan error)
+	go func() {
+		done <- q.Put(3)
+	}()
+	waitForPutters(q, 1)
+	assert.Equal(t, int64(2), q.Len())
+
+	result, err := q.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, result)
+	assert.Nil(t, <-done)
+
+	result, err = q.Get(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2, 3}, result)
+
+	ok, err = q.Offer(4, 5)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestBoundedPutFIFO(t *testing.T) {
+	q := NewBoundedOf[int](1)
+	assert.Nil(t, q.Put(0))
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.Nil(t, q.Put(i))
+		}(i)
+		waitForPutters(q, i)
+	}
+
+	// a put that would fit must still wait its turn
+	ok, err := q.Offer(6)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	for i := 0; i <= 5; i++ {
+		result, err := q.Get(1)
+		assert.Nil(t, err)
+		assert.Equal(t, []int{i}, result)
+	}
+	wg.Wait()
+}
+
+func TestBoundedPutTimeout(t *testing.T) {
+	q := NewBoundedOf[int](3)
+	assert.Nil(t, q.Put(1, 2))
+
+	err := q.PutTimeout(time.Millisecond, 3, 4)
+	assert.Equal(t, ErrTimeout, err)
+	assert.Len(t, q.putters, 0)
+	assert.Equal(t, int64(2), q.Len())
+
+	// a put behind one that gives up is served once it fits
+	timedOut, done := make(chan error), make(chan error)
+	go func() {
+		timedOut <- q.PutTimeout(20*time.Millisecond, 3, 4)
+	}()
+	waitForPutters(q, 1)
+	go func() {
+		done <- q.Put(5)
+	}()
+	waitForPutters(q, 2)
+
+	assert.Equal(t, ErrTimeout, <-timedOut)
+	assert.Nil(t, <-done)
+	result, err := q.Get(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 5}, result)
+}
+
+func TestBoundedPutContext(t *testing.T) {
+	q := NewBoundedOf[int](1)
+	assert.Nil(t, q.PutContext(context.Background(), 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitForPutters(q, 1)
+		cancel()
+	}()
+	assert.Equal(t, context.Canceled, q.PutContext(ctx, 2))
+	assert.Len(t, q.putters, 0)
+	assert.Equal(t, int64(1), q.Len())
+}
+
+func TestBoundedExceedsCapacity(t *testing.T) {
+	q := NewBoundedOf[int](2)
+
+	assert.Equal(t, ErrExceedsCapacity, q.Put(1, 2, 3))
+	_, err := q.Offer(1, 2, 3)
+	assert.Equal(t, ErrExceedsCapacity, err)
+	assert.True(t, q.Empty())
+}
+
+func TestBoundedTakeUntil(t *testing.T) {
+	q := NewBoundedOf[int](2)
+	assert.Nil(t, q.Put(1, 2))
+
+	done := make(chan error)
+	go func() {
+		done <- q.Put(3)
+	}()
+	waitForPutters(q, 1)
+
+	result, err := q.TakeUntil(func(item int) bool {
+		return item < 2
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, result)
+	assert.Nil(t, <-done)
+	assert.Equal(t, int64(2), q.Len())
+}
+
+func TestBoundedPutHandsOffToGet(t *testing.T) {
+	q := NewBoundedOf[int](1)
+	assert.Nil(t, q.Put(1))
+
+	done := make(chan error)
+	go func() {
+		done <- q.Put(2)
+	}()
+	waitForPutters(q, 1)
+
+	result, err := q.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1}, result)
+	assert.Nil(t, <-done)
+
+	// a blocked get receives the admitted item
+	result, err = q.Get(1)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{2}, result)
+}
+
+func TestBoundedDispose(t *testing.T) {
+	q := NewBoundedOf[int](1)
+	assert.Nil(t, q.Put(1))
+
+	done := make(chan error)
+	go func() {
+		done <- q.Put(2)
+	}()
+	waitForPutters(q, 1)
+
+	assert.Equal(t, []int{1}, q.Dispose())
+	assert.Equal(t, ErrDisposed, <-done)
+	assert.Equal(t, ErrDisposed, q.Put(3))
+	_, err := q.Offer(3)
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestBoundedProducersAndConsumers(t *testing.T) {
+	q := NewBoundedOf[int](10)
+	numProducers, numItems := 5, 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < numProducers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < numItems; j++ {
+				assert.Nil(t, q.Put(j))
+			}
+		}()
+	}
+
+	received := 0
+	for received < numProducers*numItems {
+		result, err := q.Get(3)
+		assert.Nil(t, err)
+		assert.True(t, q.Len() <= q.Cap())
+		received += len(result)
+	}
+	wg.Wait()
+	assert.True(t, q.Empty())
+}
+
 func BenchmarkQueuePut(b *testing.B) {
 	numItems := int64(1000)
 
@@ -17,6 +17,7 @@ limitations under the License.
 package queue
 
 import (
+	"context"
 	"runtime"
 	"sync/atomic"
 	"time"
@@ -36,20 +37,20 @@ func roundUp(v uint64) uint64 {
 	return v
 }
 
-type node struct {
+type node[T any] struct {
 	position uint64
-	data     interface{}
+	data     T
 }
 
-type nodes []node
+type nodes[T any] []node[T]
 
-// RingBuffer is a MPMC buffer that achieves threadsafety with CAS operations
+// RingBufferOf is a MPMC buffer that achieves threadsafety with CAS operations
 // only.  A put on full or get on empty call will block until an item
 // is put or retrieved.  Calling Dispose on the RingBuffer will unblock
 // any blocked threads with an error.  This buffer is similar to the buffer
 // described here: http://www.1024cores.net/home/lock-free-algorithms/queues/bounded-mpmc-queue
 // with some minor additions.
-type RingBuffer struct {
+type RingBufferOf[T any] struct {
 	_padding0      [8]uint64
 	queue          uint64
 	_padding1      [8]uint64
@@ -57,14 +58,17 @@ type RingBuffer struct {
 	_padding2      [8]uint64
 	mask, disposed uint64
 	_padding3      [8]uint64
-	nodes          nodes
+	nodes          nodes[T]
 }
 
-func (rb *RingBuffer) init(size uint64) {
+// RingBuffer is a ring buffer of interface{} items.
+type RingBuffer = RingBufferOf[interface{}]
+
+func (rb *RingBufferOf[T]) init(size uint64) {
 	size = roundUp(size)
-	rb.nodes = make(nodes, size)
+	rb.nodes = make(nodes[T], size)
 	for i := uint64(0); i < size; i++ {
-		rb.nodes[i] = node{position: i}
+		rb.nodes[i] = node[T]{position: i}
 	}
 	rb.mask = size - 1 // so we don't have to do this with every put/get operation
 }
@@ -72,27 +76,41 @@ func (rb *RingBuffer) init(size uint64) {
 // Put adds the provided item to the queue.  If the queue is full, this
 // call will block until an item is added to the queue or Dispose is called
 // on the queue.  An error will be returned if the queue is disposed.
-func (rb *RingBuffer) Put(item interface{}) error {
-	_, err := rb.put(item, false)
+func (rb *RingBufferOf[T]) Put(item T) error {
+	_, err := rb.put(context.Background(), item, false)
+	return err
+}
+
+// PutContext is like Put but will also return ctx.Err() if the
+// provided context is done before there is space in the queue.
+func (rb *RingBufferOf[T]) PutContext(ctx context.Context, item T) error {
+	_, err := rb.put(ctx, item, false)
 	return err
 }
 
 // Offer adds the provided item to the queue if there is space.  If the queue
 // is full, this call will return false.  An error will be returned if the
 // queue is disposed.
-func (rb *RingBuffer) Offer(item interface{}) (bool, error) {
-	return rb.put(item, true)
+func (rb *RingBufferOf[T]) Offer(item T) (bool, error) {
+	return rb.put(context.Background(), item, true)
 }
 
-func (rb *RingBuffer) put(item interface{}, offer bool) (bool, error) {
-	var n *node
+func (rb *RingBufferOf[T]) put(ctx context.Context, item T, offer bool) (bool, error) {
+	var n *node[T]
 	pos := atomic.LoadUint64(&rb.queue)
+	done := ctx.Done()
 L:
 	for {
 		if atomic.LoadUint64(&rb.disposed) == 1 {
 			return false, ErrDisposed
 		}
 
+		select {
+		case <-done:
+			return false, ctx.Err()
+		default:
+		}
+
 		n = &rb.nodes[pos&rb.mask]
 		seq := atomic.LoadUint64(&n.position)
 		switch dif := seq - pos; {
@@ -118,12 +136,147 @@ L:
 	return true, nil
 }
 
+// PutMany adds the provided items to the queue in order.  Every free
+// slot available, up to the number of items remaining, is reserved
+// at once so the items of a single call are contiguous in the queue
+// unless it runs out of room.  In that case this call blocks, adding
+// the remaining items as slots are freed, and items from other
+// producers may be interleaved between those runs.  The number of items
+// added is returned along with an error if the queue is disposed before
+// all of them were added.
+func (rb *RingBufferOf[T]) PutMany(items ...T) (int, error) {
+	return rb.putMany(items, false)
+}
+
+// OfferMany adds as many of the provided items, in order, as there is
+// room for in the queue without blocking and returns the number added.
+// The items added are contiguous in the queue.  An error will be
+// returned if the queue is disposed.
+func (rb *RingBufferOf[T]) OfferMany(items ...T) (int, error) {
+	return rb.putMany(items, true)
+}
+
+func (rb *RingBufferOf[T]) putMany(items []T, offer bool) (int, error) {
+	added := 0
+	pos := atomic.LoadUint64(&rb.queue)
+	for added < len(items) {
+		if atomic.LoadUint64(&rb.disposed) == 1 {
+			return added, ErrDisposed
+		}
+
+		// count the free slots following pos
+		remaining := uint64(len(items) - added)
+		free := uint64(0)
+		for free < remaining && free < uint64(len(rb.nodes)) {
+			if atomic.LoadUint64(&rb.nodes[(pos+free)&rb.mask].position) != pos+free {
+				break
+			}
+			free++
+		}
+
+		if free > 0 {
+			if !atomic.CompareAndSwapUint64(&rb.queue, pos, pos+free) {
+				pos = atomic.LoadUint64(&rb.queue)
+				continue
+			}
+
+			for i := uint64(0); i < free; i++ {
+				n := &rb.nodes[(pos+i)&rb.mask]
+				n.data = items[added]
+				atomic.StoreUint64(&n.position, pos+i+1)
+				added++
+			}
+			pos += free
+			continue
+		}
+
+		seq := atomic.LoadUint64(&rb.nodes[pos&rb.mask].position)
+		if int64(seq-pos) > 0 { // another producer took this slot
+			pos = atomic.LoadUint64(&rb.queue)
+			continue
+		}
+
+		if seq == pos { // freed since it was checked
+			continue
+		}
+
+		if offer {
+			return added, nil
+		}
+
+		runtime.Gosched() // free up the cpu before the next iteration
+	}
+
+	return added, nil
+}
+
+// GetMany will return up to number items from the queue, in order,
+// reserving them all at once.  This call will block if the queue is
+// empty until an item is added to the queue or Dispose is called on
+// the queue.  An error will be returned if the queue is disposed.
+func (rb *RingBufferOf[T]) GetMany(number uint64) ([]T, error) {
+	if number == 0 {
+		return []T{}, nil
+	}
+
+	var zero T
+	pos := atomic.LoadUint64(&rb.dequeue)
+	for {
+		if atomic.LoadUint64(&rb.disposed) == 1 {
+			return nil, ErrDisposed
+		}
+
+		// count the filled slots following pos
+		ready := uint64(0)
+		for ready < number && ready < uint64(len(rb.nodes)) {
+			if atomic.LoadUint64(&rb.nodes[(pos+ready)&rb.mask].position) != pos+ready+1 {
+				break
+			}
+			ready++
+		}
+
+		if ready > 0 {
+			if !atomic.CompareAndSwapUint64(&rb.dequeue, pos, pos+ready) {
+				pos = atomic.LoadUint64(&rb.dequeue)
+				continue
+			}
+
+			items := make([]T, 0, ready)
+			for i := uint64(0); i < ready; i++ {
+				n := &rb.nodes[(pos+i)&rb.mask]
+				items = append(items, n.data)
+				n.data = zero
+				atomic.StoreUint64(&n.position, pos+i+rb.mask+1)
+			}
+			return items, nil
+		}
+
+		seq := atomic.LoadUint64(&rb.nodes[pos&rb.mask].position)
+		if int64(seq-(pos+1)) > 0 { // another consumer took this slot
+			pos = atomic.LoadUint64(&rb.dequeue)
+			continue
+		}
+
+		if seq == pos+1 { // filled since it was checked
+			continue
+		}
+
+		runtime.Gosched() // free up the cpu before the next iteration
+	}
+}
+
 // Get will return the next item in the queue.  This call will block
 // if the queue is empty.  This call will unblock when an item is added
 // to the queue or Dispose is called on the queue.  An error will be returned
 // if the queue is disposed.
-func (rb *RingBuffer) Get() (interface{}, error) {
-	return rb.Poll(0)
+func (rb *RingBufferOf[T]) Get() (T, error) {
+	return rb.poll(context.Background(), 0)
+}
+
+// GetContext is like Get but will also return ctx.Err() if the
+// provided context is done before an item is added to the queue.
+func (rb *RingBufferOf[T]) GetContext(ctx context.Context) (T, error) {
+	return rb.poll(ctx, 0)
 }
 
 // Poll will return the next item in the queue.  This call will block
@@ -131,11 +284,24 @@ func (rb *RingBuffer) Get() (interface{}, error) {
 // to the queue, Dispose is called on the queue, or the timeout is reached. An
 // error will be returned if the queue is disposed or a timeout occurs. A
 // non-positive timeout will block indefinitely.
-func (rb *RingBuffer) Poll(timeout time.Duration) (interface{}, error) {
+func (rb *RingBufferOf[T]) Poll(timeout time.Duration) (T, error) {
+	return rb.poll(context.Background(), timeout)
+}
+
+// PollContext is like Poll but will also return ctx.Err() if the
+// provided context is done before an item is added to the queue or
+// the timeout is reached.
+func (rb *RingBufferOf[T]) PollContext(ctx context.Context, timeout time.Duration) (T, error) {
+	return rb.poll(ctx, timeout)
+}
+
+func (rb *RingBufferOf[T]) poll(ctx context.Context, timeout time.Duration) (T, error) {
 	var (
-		n     *node
+		zero  T
+		n     *node[T]
 		pos   = atomic.LoadUint64(&rb.dequeue)
 		start time.Time
+		done  = ctx.Done()
 	)
 	if timeout > 0 {
 		start = time.Now()
@@ -143,7 +309,13 @@ func (rb *RingBuffer) Poll(timeout time.Duration) (interface{}, error) {
 L:
 	for {
 		if atomic.LoadUint64(&rb.disposed) == 1 {
-			return nil, ErrDisposed
+			return zero, ErrDisposed
+		}
+
+		select {
+		case <-done:
+			return zero, ctx.Err()
+		default:
 		}
 
 		n = &rb.nodes[pos&rb.mask]
@@ -160,44 +332,50 @@ L:
 		}
 
 		if timeout > 0 && time.Since(start) >= timeout {
-			return nil, ErrTimeout
+			return zero, ErrTimeout
 		}
 
 		runtime.Gosched() // free up the cpu before the next iteration
 	}
 	data := n.data
-	n.data = nil
+	n.data = zero
 	atomic.StoreUint64(&n.position, pos+rb.mask+1)
 	return data, nil
 }
 
 // Len returns the number of items in the queue.
-func (rb *RingBuffer) Len() uint64 {
+func (rb *RingBufferOf[T]) Len() uint64 {
 	return atomic.LoadUint64(&rb.queue) - atomic.LoadUint64(&rb.dequeue)
 }
 
 // Cap returns the capacity of this ring buffer.
-func (rb *RingBuffer) Cap() uint64 {
+func (rb *RingBufferOf[T]) Cap() uint64 {
 	return uint64(len(rb.nodes))
 }
 
 // Dispose will dispose of this queue and free any blocked threads
 // in the Put and/or Get methods.  Calling those methods on a disposed
 // queue will return an error.
-func (rb *RingBuffer) Dispose() {
+func (rb *RingBufferOf[T]) Dispose() {
 	atomic.CompareAndSwapUint64(&rb.disposed, 0, 1)
 }
 
 // IsDisposed will return a bool indicating if this queue has been
 // disposed.
-func (rb *RingBuffer) IsDisposed() bool {
+func (rb *RingBufferOf[T]) IsDisposed() bool {
 	return atomic.LoadUint64(&rb.disposed) == 1
 }
 
 // NewRingBuffer will allocate, initialize, and return a ring buffer
 // with the specified size.
 func NewRingBuffer(size uint64) *RingBuffer {
-	rb := &RingBuffer{}
+	return NewRingBufferOf[interface{}](size)
+}
+
+// NewRingBufferOf will allocate, initialize, and return a ring buffer
+// holding items of type T with the specified size.
+func NewRingBufferOf[T any](size uint64) *RingBufferOf[T] {
+	rb := &RingBufferOf[T]{}
 	rb.init(size)
 	return rb
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package queue
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -42,6 +43,31 @@ func TestRingInsert(t *testing.T) {
 	assert.Equal(t, 5, result)
 }
 
+func TestRingBufferOf(t *testing.T) {
+	rb := NewRingBufferOf[int](2)
+
+	ok, err := rb.Offer(1)
+	assert.True(t, ok)
+	assert.Nil(t, err)
+	assert.Nil(t, rb.Put(2))
+
+	ok, err = rb.Offer(3)
+	assert.False(t, ok)
+	assert.Nil(t, err)
+
+	result, err := rb.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+
+	result, err = rb.Poll(time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result)
+
+	result, err = rb.Poll(time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+	assert.Equal(t, 0, result)
+}
+
 func TestRingMultipleInserts(t *testing.T) {
 	rb := NewRingBuffer(5)
 
@@ -184,6 +210,52 @@ func TestRingPollEmpty(t *testing.T) {
 	assert.Equal(t, ErrTimeout, err)
 }
 
+func TestRingGetContext(t *testing.T) {
+	rb := NewRingBuffer(3)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := rb.GetContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	// the buffer remains usable
+	assert.Nil(t, rb.Put(1))
+	result, err := rb.GetContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+}
+
+func TestRingPollContext(t *testing.T) {
+	rb := NewRingBuffer(3)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	_, err := rb.PollContext(ctx, time.Second)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = rb.PollContext(context.Background(), time.Millisecond)
+	assert.Equal(t, ErrTimeout, err)
+}
+
+func TestRingPutContext(t *testing.T) {
+	rb := NewRingBuffer(2)
+	assert.Nil(t, rb.Put(1))
+	assert.Nil(t, rb.Put(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, rb.PutContext(ctx, 3))
+	assert.Equal(t, uint64(2), rb.Len())
+
+	result, err := rb.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result)
+	assert.Nil(t, rb.PutContext(context.Background(), 3))
+}
+
 func TestRingPoll(t *testing.T) {
 	rb := NewRingBuffer(10)
 
@@ -300,6 +372,180 @@ func TestDisposeOnPut(t *testing.T) {
 	assert.True(t, rb.IsDisposed())
 }
 
+func TestRingPutManyGetMany(t *testing.T) {
+	rb := NewRingBufferOf[int](8)
+
+	added, err := rb.PutMany(1, 2, 3, 4, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, added)
+	assert.Equal(t, uint64(5), rb.Len())
+
+	result, err := rb.GetMany(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, result)
+
+	// single and batch operations interleave
+	assert.Nil(t, rb.Put(6))
+	item, err := rb.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, item)
+
+	result, err = rb.GetMany(10)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{5, 6}, result)
+
+	result, err = rb.GetMany(0)
+	assert.Nil(t, err)
+	assert.Len(t, result, 0)
+}
+
+func TestRingOfferMany(t *testing.T) {
+	rb := NewRingBufferOf[int](4)
+	assert.Nil(t, rb.Put(0))
+
+	// only the items that fit are added
+	added, err := rb.OfferMany(1, 2, 3, 4, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, added)
+
+	added, err = rb.OfferMany(6)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, added)
+
+	result, err := rb.GetMany(4)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, result)
+
+	// wrapping around the end of the buffer
+	added, err = rb.OfferMany(4, 5, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, added)
+	result, err = rb.GetMany(4)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{4, 5, 6}, result)
+}
+
+func TestRingPutManyLargerThanBuffer(t *testing.T) {
+	rb := NewRingBufferOf[int](4)
+	items := make([]int, 0, 100)
+	for i := 0; i < 100; i++ {
+		items = append(items, i)
+	}
+
+	done := make(chan int)
+	go func() {
+		added, err := rb.PutMany(items...)
+		assert.Nil(t, err)
+		done <- added
+	}()
+
+	result := make([]int, 0, len(items))
+	for len(result) < len(items) {
+		batch, err := rb.GetMany(3)
+		assert.Nil(t, err)
+		assert.True(t, len(batch) <= 3)
+		result = append(result, batch...)
+	}
+
+	assert.Equal(t, len(items), <-done)
+	assert.Equal(t, items, result)
+}
+
+func TestRingManyDispose(t *testing.T) {
+	rb := NewRingBufferOf[int](2)
+
+	done := make(chan int)
+	go func() {
+		added, err := rb.PutMany(1, 2, 3)
+		assert.Equal(t, ErrDisposed, err)
+		done <- added
+	}()
+
+	for rb.Len() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	rb.Dispose()
+	assert.Equal(t, 2, <-done)
+
+	_, err := rb.GetMany(1)
+	assert.Equal(t, ErrDisposed, err)
+	_, err = rb.OfferMany(1)
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestRingGetManyDispose(t *testing.T) {
+	rb := NewRingBufferOf[int](2)
+
+	done := make(chan error)
+	go func() {
+		_, err := rb.GetMany(2)
+		done <- err
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	rb.Dispose()
+	assert.Equal(t, ErrDisposed, <-done)
+}
+
+func TestRingManyProducersAndConsumers(t *testing.T) {
+	rb := NewRingBufferOf[int](16)
+	numProducers, numBatches, batchSize := 4, 100, 7
+
+	var wg sync.WaitGroup
+	for i := 0; i < numProducers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < numBatches; j++ {
+				items := make([]int, 0, batchSize)
+				for k := 0; k < batchSize; k++ {
+					items = append(items, (i*numBatches+j)*batchSize+k)
+				}
+				added, err := rb.PutMany(items...)
+				assert.Nil(t, err)
+				assert.Equal(t, batchSize, added)
+			}
+		}(i)
+	}
+
+	total := numProducers * numBatches * batchSize
+	var lock sync.Mutex
+	seen := make([]bool, total)
+	received := int64(0)
+	var rwg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		rwg.Add(1)
+		go func() {
+			defer rwg.Done()
+			for atomic.LoadInt64(&received) < int64(total) {
+				result, err := rb.GetMany(5)
+				if err == ErrDisposed {
+					return
+				}
+				assert.Nil(t, err)
+				lock.Lock()
+				for _, item := range result {
+					assert.False(t, seen[item])
+					seen[item] = true
+				}
+				lock.Unlock()
+				atomic.AddInt64(&received, int64(len(result)))
+			}
+		}()
+	}
+
+	wg.Wait()
+	for atomic.LoadInt64(&received) < int64(total) {
+		time.Sleep(time.Millisecond)
+	}
+	rb.Dispose()
+	rwg.Wait()
+
+	for _, ok := range seen {
+		assert.True(t, ok)
+	}
+}
+
 func BenchmarkRBLifeCycle(b *testing.B) {
 	rb := NewRingBuffer(64)
 
@@ -397,6 +643,33 @@ func BenchmarkRBGet(b *testing.B) {
 	}
 }
 
+func BenchmarkRBPutMany(b *testing.B) {
+	rb := NewRingBuffer(uint64(b.N))
+	items := make([]interface{}, 64)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i += len(items) {
+		if _, err := rb.OfferMany(items[:min(len(items), b.N-i)]...); err != nil {
+			b.Fail()
+		}
+	}
+}
+
+func BenchmarkRBGetMany(b *testing.B) {
+	rb := NewRingBuffer(uint64(b.N))
+
+	for i := 0; i < b.N; i++ {
+		rb.Offer(i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i += 64 {
+		rb.GetMany(64)
+	}
+}
+
 func BenchmarkRBAllocation(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		NewRingBuffer(1024)
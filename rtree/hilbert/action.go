@@ -75,6 +75,46 @@ func newGetAction(rect rtree.Rectangle) *getAction {
 	return ga
 }
 
+type nearestAction struct {
+	result    rtree.Rectangles
+	completer *sync.WaitGroup
+	x, y      int32
+	k         int
+}
+
+func (na *nearestAction) complete() {
+	na.completer.Done()
+}
+
+func (na *nearestAction) operation() operation {
+	return nearest
+}
+
+func (na *nearestAction) keys() hilberts {
+	return nil
+}
+
+func (na *nearestAction) addNode(i int64, n *node) {}
+
+func (na *nearestAction) nodes() []*node {
+	return nil
+}
+
+func (na *nearestAction) rects() []*hilbertBundle {
+	return []*hilbertBundle{&hilbertBundle{}}
+}
+
+func newNearestAction(x, y int32, k int) *nearestAction {
+	na := &nearestAction{
+		completer: new(sync.WaitGroup),
+		x:         x,
+		y:         y,
+		k:         k,
+	}
+	na.completer.Add(1)
+	return na
+}
+
 type insertAction struct {
 	rs        []*hilbertBundle
 	completer *sync.WaitGroup
@@ -0,0 +1,158 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbert
+
+/*
+This file contains the logic for building a packed tree from a known
+set of rectangles.  Rectangles are sorted by Hilbert value once and
+packed into leaves holding as many keys as a node can hold before it
+would split.  Each level above is packed the same way until a single
+root remains.  Nodes have the same layout as those built by insertion:
+a leaf holds a key per rectangle while an internal node holds one more
+child than keys, each key being the largest Hilbert value of the child
+to its left.
+*/
+
+import (
+	"sort"
+
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+// dedupeBundles removes rectangles equal to a rectangle appearing later
+// in the provided bundles, which must be sorted by Hilbert value, to
+// match insertion where the last equal rectangle wins.
+func dedupeBundles(bundles []*hilbertBundle) []*hilbertBundle {
+	result := bundles[:0]
+	for i := 0; i < len(bundles); {
+		j := i + 1
+		for j < len(bundles) && bundles[j].hilbert == bundles[i].hilbert {
+			j++
+		}
+
+		for k := i; k < j; k++ {
+			duplicate := false
+			for l := k + 1; l < j; l++ {
+				if equal(bundles[k].rect, bundles[l].rect) {
+					duplicate = true
+					break
+				}
+			}
+
+			if !duplicate {
+				result = append(result, bundles[k])
+			}
+		}
+
+		i = j
+	}
+
+	return result
+}
+
+// packLeaves packs the provided sorted bundles into linked leaves.
+func packLeaves(bundles []*hilbertBundle, capacity, ary uint64) []*node {
+	leaves := make([]*node, 0, uint64(len(bundles))/capacity+1)
+	for i := uint64(0); i < uint64(len(bundles)); i += capacity {
+		end := i + capacity
+		if end > uint64(len(bundles)) {
+			end = uint64(len(bundles))
+		}
+
+		n := newNode(true, newKeys(ary), newNodes(ary))
+		for _, hb := range bundles[i:end] {
+			n.keys.list = append(n.keys.list, hb.hilbert)
+			n.nodes.push(hb.rect)
+		}
+		n.mbr = newRectangleFromRects(n.nodes.list)
+		n.maxHilbert = n.keys.last()
+
+		if len(leaves) > 0 {
+			leaves[len(leaves)-1].right = n
+		}
+		leaves = append(leaves, n)
+	}
+
+	return leaves
+}
+
+// packInternal packs the provided children into linked internal nodes
+// of at most capacity+1 children each.
+func packInternal(children []*node, capacity, ary uint64) []*node {
+	width := capacity + 1
+	parents := make([]*node, 0, uint64(len(children))/width+1)
+	for i := uint64(0); i < uint64(len(children)); i += width {
+		end := i + width
+		if end > uint64(len(children)) {
+			end = uint64(len(children))
+		}
+
+		n := newNode(false, newKeys(ary), newNodes(ary))
+		for j, child := range children[i:end] {
+			if j > 0 {
+				n.keys.list = append(n.keys.list, children[i+uint64(j)-1].maxHilbert)
+			}
+			child.parent = n
+			n.nodes.push(child)
+			if child.maxHilbert > n.maxHilbert {
+				n.maxHilbert = child.maxHilbert
+			}
+		}
+		n.mbr = newRectangleFromRects(n.nodes.list)
+
+		if len(parents) > 0 {
+			parents[len(parents)-1].right = n
+		}
+		parents = append(parents, n)
+	}
+
+	return parents
+}
+
+// BulkLoad will construct a new Hilbert R-Tree containing the provided
+// rectangles.  Rather than inserting rectangles one at a time, they are
+// sorted by Hilbert value and packed into fully filled nodes from the
+// bottom up.  The resulting tree holds the same rectangles as one built
+// by inserting them in the provided order and can be used in the same
+// way afterwards.
+func BulkLoad(bufferSize, ary uint64, rects ...rtree.Rectangle) rtree.RTree {
+	tree := newTree(bufferSize, ary)
+	if len(rects) == 0 {
+		return tree
+	}
+
+	bundles := bundlesFromRects(rects...)
+	sort.SliceStable(bundles, func(i, j int) bool {
+		return bundles[i].hilbert < bundles[j].hilbert
+	})
+	bundles = dedupeBundles(bundles)
+
+	// a node splits once it holds ary keys
+	capacity := ary - 1
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	level := packLeaves(bundles, capacity, ary)
+	for len(level) > 1 {
+		level = packInternal(level, capacity, ary)
+	}
+
+	tree.root = level[0]
+	tree.number = uint64(len(bundles))
+	return tree
+}
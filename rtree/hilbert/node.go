@@ -242,11 +242,16 @@ func (n *node) insert(kb *keyBundle) rtree.Rectangle {
 			n.nodes.push(kb.left)
 			n.nodes.push(kb.right)
 		} else {
+			// children may share a key so find the child that split
+			for j := i; j < n.nodes.len(); j++ {
+				if n.nodes.list[j] == kb.left {
+					i = j
+					break
+				}
+			}
 			n.nodes.replaceAt(i, kb.left)
 			n.nodes.insertAt(i+1, kb.right)
 		}
-		n.mbr.adjust(kb.left)
-		n.mbr.adjust(kb.right)
 		if kb.right.(*node).maxHilbert > n.maxHilbert {
 			n.maxHilbert = kb.right.(*node).maxHilbert
 		}
@@ -279,6 +284,28 @@ func (n *node) UpperRight() (int32, int32) {
 	return n.mbr.xhigh, n.mbr.yhigh
 }
 
+// bounds returns the smallest rectangle containing all of this node's
+// children, which is empty if they contain no rectangles.
+func (n *node) bounds() rectangle {
+	r := newEmptyRectangle()
+	for _, child := range n.nodes.list {
+		r.adjust(child)
+	}
+
+	return *r
+}
+
+// height returns the number of levels below this node, 0 for a leaf.
+func (n *node) height() int {
+	height := 0
+	for !n.isLeaf {
+		n = n.nodes.list[0].(*node)
+		height++
+	}
+
+	return height
+}
+
 func (n *node) needsSplit(ary uint64) bool {
 	return n.keys.len() >= ary
 }
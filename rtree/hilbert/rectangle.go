@@ -16,7 +16,11 @@ limitations under the License.
 
 package hilbert
 
-import "github.com/Workiva/go-datastructures/rtree"
+import (
+	"math"
+
+	"github.com/Workiva/go-datastructures/rtree"
+)
 
 type rectangle struct {
 	xlow, xhigh, ylow, yhigh int32
@@ -58,6 +62,42 @@ func intersect(rect1 *rectangle, rect2 rtree.Rectangle) bool {
 	return xhigh2 >= rect1.xlow && xlow2 <= rect1.xhigh && yhigh2 >= rect1.ylow && ylow2 <= rect1.yhigh
 }
 
+// axisDistance returns the distance from the provided value to the
+// range [low, high] or 0 if the value falls within it.
+func axisDistance(v, low, high int32) float64 {
+	if v < low {
+		return float64(low) - float64(v)
+	}
+	if v > high {
+		return float64(v) - float64(high)
+	}
+
+	return 0
+}
+
+// distance returns the squared euclidean distance from the provided
+// point to the closest point of the rectangle.
+func distance(x, y int32, rect rtree.Rectangle) float64 {
+	xlow, ylow := rect.LowerLeft()
+	xhigh, yhigh := rect.UpperRight()
+	dx := axisDistance(x, xlow, xhigh)
+	dy := axisDistance(y, ylow, yhigh)
+	return dx*dx + dy*dy
+}
+
+// newEmptyRectangle returns a rectangle that contains nothing, with its
+// lower left above and to the right of its upper right, so that it is
+// replaced by the first rectangle it is adjusted by and adjusting other
+// rectangles by it changes nothing.
+func newEmptyRectangle() *rectangle {
+	return &rectangle{
+		xlow:  math.MaxInt32,
+		xhigh: math.MinInt32,
+		ylow:  math.MaxInt32,
+		yhigh: math.MinInt32,
+	}
+}
+
 func newRectangeFromRect(rect rtree.Rectangle) *rectangle {
 	r := &rectangle{}
 	x, y := rect.LowerLeft()
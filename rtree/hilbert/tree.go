@@ -46,6 +46,7 @@ const (
 	get operation = iota
 	add
 	remove
+	nearest
 )
 
 const multiThreadAt = 1000 // number of keys before we multithread lookups
@@ -99,6 +100,11 @@ func (tree *tree) checkAndRun(action action) {
 				ga.result = result
 				action.complete()
 				tree.reset()
+			case nearest:
+				na := action.(*nearestAction)
+				na.result = tree.nearest(na.x, na.y, na.k)
+				action.complete()
+				tree.reset()
 			case add, remove:
 				if len(action.keys()) > multiThreadAt {
 					tree.operationRunner(interfaces{action}, true)
@@ -124,7 +130,8 @@ func (tree *tree) init(bufferSize, ary uint64) {
 
 func (tree *tree) operationRunner(xns interfaces, threaded bool) {
 	writeOperations, deleteOperations, toComplete := tree.fetchKeys(xns, threaded)
-	tree.recursiveMutate(writeOperations, deleteOperations, false, threaded)
+	changed := tree.recursiveMutate(writeOperations, deleteOperations, false, threaded)
+	tree.adjustBounds(changed)
 	for _, a := range toComplete {
 		a.complete()
 	}
@@ -155,7 +162,7 @@ func (tree *tree) fetchKeys(xns interfaces, inParallel bool) (map[*node][]*keyBu
 				deleteOperations[n] = append(deleteOperations[n], &keyBundle{key: action.rects()[i].hilbert, left: action.rects()[i].rect})
 			}
 			toComplete = append(toComplete, action)
-		case get:
+		case get, nearest:
 			action.complete()
 		}
 	}
@@ -176,6 +183,9 @@ func (tree *tree) fetchKeysInSerial(xns interfaces) {
 			ga := action.(*getAction)
 			rects := tree.search(ga.lookup)
 			ga.result = rects
+		case nearest:
+			na := action.(*nearestAction)
+			na.result = tree.nearest(na.x, na.y, na.k)
 		}
 	}
 }
@@ -233,6 +243,9 @@ func (tree *tree) fetchKeysInParallel(xns []interface{}) {
 					ga := action.(*getAction)
 					result := tree.search(ga.lookup)
 					ga.result = result
+				case nearest:
+					na := action.(*nearestAction)
+					na.result = tree.nearest(na.x, na.y, na.k)
 				}
 			}
 			wg.Done()
@@ -280,9 +293,11 @@ func (tree *tree) applyNode(n *node, adds, deletes []*keyBundle) {
 	}
 }
 
-func (tree *tree) recursiveMutate(adds, deletes map[*node][]*keyBundle, setRoot, inParallel bool) {
+// recursiveMutate applies the provided adds and deletes a layer at a time,
+// leaves first, and returns the nodes it changed or created by splitting.
+func (tree *tree) recursiveMutate(adds, deletes map[*node][]*keyBundle, setRoot, inParallel bool) []*node {
 	if len(adds) == 0 && len(deletes) == 0 {
-		return
+		return nil
 	}
 
 	if setRoot && len(adds) > 1 {
@@ -319,6 +334,10 @@ func (tree *tree) recursiveMutate(adds, deletes map[*node][]*keyBundle, setRoot,
 	var write sync.Mutex
 	nextLayerWrite := make(map[*node][]*keyBundle)
 	nextLayerDelete := make(map[*node][]*keyBundle)
+	changed := make([]*node, 0, len(ifs))
+	for _, ifc := range ifs {
+		changed = append(changed, ifc.(*node))
+	}
 
 	var mutate func(interfaces, func(interface{}))
 	if inParallel {
@@ -355,12 +374,47 @@ func (tree *tree) recursiveMutate(adds, deletes map[*node][]*keyBundle, setRoot,
 			write.Lock()
 			for i, k := range keys {
 				nextLayerWrite[parent] = append(nextLayerWrite[parent], &keyBundle{key: k, left: nodes[i*2], right: nodes[i*2+1]})
+				changed = append(changed, nodes[i*2+1])
 			}
 			write.Unlock()
 		}
 	})
 
-	tree.recursiveMutate(nextLayerWrite, nextLayerDelete, setRoot, inParallel)
+	return append(changed, tree.recursiveMutate(nextLayerWrite, nextLayerDelete, setRoot, inParallel)...)
+}
+
+// adjustBounds recomputes the bounds of the provided nodes and then of
+// their ancestors, a level at a time from the leaves up, so that they
+// grow with inserted rectangles and shrink as rectangles are deleted.
+// Ancestors are only revisited for nodes whose bounds changed, so a node
+// listed again after it was adjusted stops there.
+func (tree *tree) adjustBounds(changed []*node) {
+	levels := make([][]*node, 0, 8)
+	for _, n := range changed {
+		height := n.height()
+		for len(levels) <= height {
+			levels = append(levels, nil)
+		}
+		levels[height] = append(levels[height], n)
+	}
+
+	for height := 0; height < len(levels); height++ {
+		for _, n := range levels[height] {
+			bounds := n.bounds()
+			if bounds == *n.mbr {
+				continue
+			}
+
+			*n.mbr = bounds
+			if n.parent == nil {
+				continue
+			}
+			if len(levels) <= height+1 {
+				levels = append(levels, nil)
+			}
+			levels[height+1] = append(levels[height+1], n.parent)
+		}
+	}
 }
 
 // Insert will add the provided keys to the tree.
@@ -398,6 +452,67 @@ func (tree *tree) search(r *rectangle) rtree.Rectangles {
 	return result
 }
 
+// nearestEntry is an entry in the best-first search, either a node
+// or a rectangle stored in the tree, along with its squared distance
+// from the query point.
+type nearestEntry struct {
+	rect     rtree.Rectangle
+	distance float64
+}
+
+func compareNearestEntries(a, b *nearestEntry) int {
+	switch {
+	case a.distance < b.distance:
+		return -1
+	case a.distance > b.distance:
+		return 1
+	}
+
+	return 0
+}
+
+// nearest performs a best-first traversal from the root.  Nodes and
+// rectangles are visited in order of their distance from the provided
+// point and, as a node is never farther than anything it contains, the
+// first k rectangles visited are the k nearest.
+func (tree *tree) nearest(x, y int32, k int) rtree.Rectangles {
+	if k <= 0 || tree.root == nil || tree.root.nodes.len() == 0 {
+		return rtree.Rectangles{}
+	}
+
+	result := make(rtree.Rectangles, 0, k)
+	pq := queue.NewPriorityQueueOf(int(tree.ary), true, compareNearestEntries)
+	pq.Put(&nearestEntry{rect: tree.root, distance: distance(x, y, tree.root)})
+
+	for !pq.Empty() && len(result) < k {
+		entries, err := pq.Get(1)
+		if err != nil {
+			break
+		}
+
+		if n, ok := entries[0].rect.(*node); ok {
+			for _, child := range n.nodes.list {
+				pq.Put(&nearestEntry{rect: child, distance: distance(x, y, child)})
+			}
+		} else {
+			result = append(result, entries[0].rect)
+		}
+	}
+
+	return result
+}
+
+// NearestNeighbors will return up to k rectangles ordered by their
+// distance from the provided point, nearest first.  Distance is measured
+// to the closest point of each rectangle so any rectangle containing
+// the point has a distance of 0.
+func (tree *tree) NearestNeighbors(x, y int32, k int) rtree.Rectangles {
+	na := newNearestAction(x, y, k)
+	tree.checkAndRun(na)
+	na.completer.Wait()
+	return na.result
+}
+
 // Search will return a list of rectangles that intersect the provided
 // rectangle.
 func (tree *tree) Search(rect rtree.Rectangle) rtree.Rectangles {
@@ -425,7 +540,10 @@ func newTree(bufferSize, ary uint64) *tree {
 	return tree
 }
 
-// New will construct a new Hilbert R-Tree and return it.
+var _ rtree.NearestNeighborer = (*tree)(nil)
+
+// New will construct a new Hilbert R-Tree and return it.  The tree
+// also implements rtree.NearestNeighborer.
 func New(bufferSize, ary uint64) rtree.RTree {
 	return newTree(bufferSize, ary)
 }
@@ -21,6 +21,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -367,6 +368,25 @@ func TestInsertDuplicateHilbert(t *testing.T) {
 	assert.Contains(t, result, r4)
 }
 
+func TestInsertManyDuplicateHilbert(t *testing.T) {
+	// nested rectangles share a center and so a hilbert value, which
+	// leaves internal nodes with several children under the same key
+	rects := make(rtree.Rectangles, 0, 50)
+	tree := newTree(3, 3)
+	for i := int32(0); i < 50; i++ {
+		r := newMockRectangle(100-i, 100-i, 100+i, 100+i)
+		rects = append(rects, r)
+		tree.Insert(r)
+	}
+
+	assert.Equal(t, uint64(50), tree.Len())
+	result := tree.Search(constructInfiniteRect())
+	assert.Len(t, result, 50)
+	for _, r := range rects {
+		assert.Contains(t, result, r)
+	}
+}
+
 func TestDeleteAllDuplicateHilbert(t *testing.T) {
 	r1 := newMockRectangle(0, 0, 20, 20)
 	r2 := newMockRectangle(1, 1, 19, 19)
@@ -396,6 +416,236 @@ func TestInsertDuplicateRect(t *testing.T) {
 	assert.Equal(t, rtree.Rectangles{r2}, result)
 }
 
+func constructRandomMockRects(num int, max int32) rtree.Rectangles {
+	rects := make(rtree.Rectangles, 0, num)
+	for i := 0; i < num; i++ {
+		x, y := rand.Int31n(max), rand.Int31n(max)
+		rects = append(rects, newMockRectangle(x, y, x+rand.Int31n(10), y+rand.Int31n(10)))
+	}
+
+	return rects
+}
+
+// checkBounds verifies that every node below n is bounded by exactly
+// the smallest rectangle containing its children.
+func checkBounds(t *testing.T, n *node) {
+	assert.Equal(t, n.bounds(), *n.mbr)
+	if n.isLeaf {
+		return
+	}
+
+	for _, child := range n.nodes.list {
+		checkBounds(t, child.(*node))
+	}
+}
+
+func TestSearchAfterIncrementalInserts(t *testing.T) {
+	rects := constructRandomMockRects(500, 1000)
+	tree := newTree(3, 3)
+	for _, r := range rects {
+		tree.Insert(r)
+	}
+
+	checkBounds(t, tree.root)
+	for _, r := range rects {
+		assert.Contains(t, tree.Search(r), r)
+	}
+}
+
+func TestBoundsShrinkAfterDelete(t *testing.T) {
+	rects := constructRandomMockRects(500, 1000)
+	tree := newTree(3, 3)
+	tree.Insert(rects...)
+
+	far := newMockRectangle(5000, 5000, 5001, 5001)
+	tree.Insert(far)
+	tree.Delete(far)
+	for _, r := range rects[:250] {
+		tree.Delete(r)
+	}
+
+	checkBounds(t, tree.root)
+	assert.Equal(t, newRectangleFromRects(rects[250:]), tree.root.mbr)
+	for _, r := range rects[250:] {
+		assert.Contains(t, tree.Search(r), r)
+	}
+}
+
+func TestNearestNeighbors(t *testing.T) {
+	rects := constructRandomMockRects(1000, 1000)
+	tree := newTree(3, 3)
+	for _, r := range rects[:500] {
+		tree.Insert(r)
+	}
+	tree.Insert(rects[500:]...)
+
+	for i := 0; i < 50; i++ {
+		x, y := rand.Int31n(1000), rand.Int31n(1000)
+		result := tree.NearestNeighbors(x, y, 10)
+		if !assert.Len(t, result, 10) {
+			continue
+		}
+
+		expected := make([]float64, 0, len(rects))
+		for _, r := range rects {
+			expected = append(expected, distance(x, y, r))
+		}
+		sort.Float64s(expected)
+
+		for j, r := range result {
+			assert.Equal(t, expected[j], distance(x, y, r))
+		}
+	}
+}
+
+func TestNearestNeighborsContainingRect(t *testing.T) {
+	r1 := newMockRectangle(0, 0, 10, 10)
+	r2 := newMockRectangle(12, 12, 13, 13)
+	r3 := newMockRectangle(20, 20, 30, 30)
+	tree := newTree(3, 3)
+	tree.Insert(r1, r2, r3)
+
+	assert.Equal(t, rtree.Rectangles{r3, r2, r1}, tree.NearestNeighbors(25, 25, 3))
+	assert.Equal(t, rtree.Rectangles{r1, r2}, tree.NearestNeighbors(5, 5, 2))
+	assert.Equal(t, rtree.Rectangles{r1, r2, r3}, tree.NearestNeighbors(-5, -5, 10))
+}
+
+func TestNearestNeighborsEmpty(t *testing.T) {
+	assert.Implements(t, (*rtree.NearestNeighborer)(nil), New(3, 3))
+
+	tree := newTree(3, 3)
+	assert.Len(t, tree.NearestNeighbors(0, 0, 5), 0)
+
+	tree.Insert(newMockRectangle(0, 0, 1, 1))
+	assert.Len(t, tree.NearestNeighbors(0, 0, 0), 0)
+
+	tree.Delete(newMockRectangle(0, 0, 1, 1))
+	assert.Len(t, tree.NearestNeighbors(0, 0, 5), 0)
+}
+
+// checkPacked verifies the links, keys and bounds of a bulk loaded
+// tree and returns its leaves from left to right.
+func checkPacked(t *testing.T, n *node, ary uint64) []*node {
+	if n.isLeaf {
+		assert.True(t, n.keys.len() < ary)
+		assert.Equal(t, n.keys.len(), n.nodes.len())
+		assert.Equal(t, n.keys.last(), n.maxHilbert)
+		assert.Equal(t, newRectangleFromRects(n.nodes.list), n.mbr)
+		return []*node{n}
+	}
+
+	assert.True(t, n.keys.len() < ary)
+	assert.Equal(t, n.keys.len()+1, n.nodes.len())
+	assert.Equal(t, newRectangleFromRects(n.nodes.list), n.mbr)
+
+	leaves := make([]*node, 0, ary)
+	for i, r := range n.nodes.list {
+		child := r.(*node)
+		assert.Equal(t, n, child.parent)
+		if i < len(n.keys.list) {
+			assert.Equal(t, child.maxHilbert, n.keys.list[i])
+		}
+		leaves = append(leaves, checkPacked(t, child, ary)...)
+	}
+
+	return leaves
+}
+
+func TestBulkLoad(t *testing.T) {
+	rects := constructRandomMockRects(1000, 1000)
+	// include rectangles sharing a Hilbert value and exact duplicates
+	for i := int32(0); i < 20; i++ {
+		rects = append(rects, newMockRectangle(500-i, 500-i, 500+i, 500+i))
+	}
+	dup := *rects[3].(*mockRectangle)
+	rects = append(rects, &dup, rects[3])
+	for _, ary := range []uint64{3, 4, 8} {
+		inserted := newTree(3, ary)
+		for _, r := range rects {
+			inserted.Insert(r)
+		}
+		loaded := BulkLoad(3, ary, rects...).(*tree)
+
+		assert.Equal(t, inserted.Len(), loaded.Len())
+		leaves := checkPacked(t, loaded.root, ary)
+		for i, leaf := range leaves {
+			if i < len(leaves)-1 {
+				assert.Equal(t, leaves[i+1], leaf.right)
+				assert.Equal(t, ary-1, leaf.keys.len())
+			} else {
+				assert.Nil(t, leaf.right)
+			}
+		}
+
+		for i := 0; i < 50; i++ {
+			x, y := rand.Int31n(1000), rand.Int31n(1000)
+			q := newMockRectangle(x, y, x+rand.Int31n(100), y+rand.Int31n(100))
+			expected := inserted.Search(q)
+			result := loaded.Search(q)
+			assert.Len(t, result, len(expected))
+			for _, r := range expected {
+				assert.Contains(t, result, r)
+			}
+
+			expected = inserted.NearestNeighbors(x, y, 10)
+			result = loaded.NearestNeighbors(x, y, 10)
+			if assert.Len(t, result, len(expected)) {
+				for j := range result {
+					assert.Equal(t, distance(x, y, expected[j]), distance(x, y, result[j]))
+				}
+			}
+		}
+	}
+}
+
+func TestBulkLoadDuplicateRect(t *testing.T) {
+	r1 := newMockRectangle(0, 0, 20, 20)
+	r2 := newMockRectangle(0, 0, 20, 20)
+	tree := BulkLoad(3, 3, r1, r2)
+
+	assert.Equal(t, uint64(1), tree.Len())
+	result := tree.Search(constructInfiniteRect())
+	assert.Equal(t, rtree.Rectangles{r2}, result)
+}
+
+func TestBulkLoadEmpty(t *testing.T) {
+	tree := BulkLoad(3, 3)
+	assert.Equal(t, uint64(0), tree.Len())
+	assert.Len(t, tree.Search(constructInfiniteRect()), 0)
+
+	r1 := newMockRectangle(0, 0, 10, 10)
+	tree.Insert(r1)
+	assert.Equal(t, rtree.Rectangles{r1}, tree.Search(constructInfiniteRect()))
+}
+
+func TestBulkLoadThenMutate(t *testing.T) {
+	rects := constructRandomMockRects(500, 1000)
+	tree := BulkLoad(3, 4, rects[:250]...)
+	for _, r := range rects[250:] {
+		tree.Insert(r)
+	}
+
+	for _, r := range rects {
+		assert.Contains(t, tree.Search(r), r)
+	}
+
+	tree.Delete(rects[:100]...)
+	for _, r := range rects[100:] {
+		assert.Contains(t, tree.Search(r), r)
+	}
+}
+
+func BenchmarkBulkLoad(b *testing.B) {
+	numItems := 10000
+	points := constructRandomMockPoints(numItems)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		BulkLoad(8, 8, points...)
+	}
+}
+
 func BenchmarkBulkAddPoints(b *testing.B) {
 	numItems := 1000
 	points := constructMockPoints(numItems)
@@ -459,6 +709,20 @@ func BenchmarkQueryBulkPoints(b *testing.B) {
 	}
 }
 
+func BenchmarkNearestNeighbors(b *testing.B) {
+	numItems := 10000
+	points := constructRandomMockPoints(numItems)
+	tree := newTree(8, 8)
+	tree.Insert(points...)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r := rand.Int31()
+		tree.NearestNeighbors(r, r, 10)
+	}
+}
+
 func BenchmarkDelete(b *testing.B) {
 	numItems := b.N
 	points := constructMockPoints(numItems)
@@ -0,0 +1,125 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbertnd
+
+import (
+	"math"
+
+	h "github.com/Workiva/go-datastructures/numerics/hilbert"
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+// bounds is the minimum bounding box of a node.
+type bounds struct {
+	min, max []float64
+}
+
+func (b *bounds) adjust(box rtree.Box) {
+	min, max := box.Min(), box.Max()
+	for i := range b.min {
+		if min[i] < b.min[i] {
+			b.min[i] = min[i]
+		}
+		if max[i] > b.max[i] {
+			b.max[i] = max[i]
+		}
+	}
+}
+
+func newBoundsFromBoxes(boxes rtree.Boxes) *bounds {
+	if len(boxes) == 0 {
+		panic(`Cannot construct bounds with no dimensions.`)
+	}
+
+	b := &bounds{
+		min: append([]float64(nil), boxes[0].Min()...),
+		max: append([]float64(nil), boxes[0].Max()...),
+	}
+
+	for i := 1; i < len(boxes); i++ {
+		b.adjust(boxes[i])
+	}
+
+	return b
+}
+
+func equal(b1, b2 rtree.Box) bool {
+	min1, max1 := b1.Min(), b1.Max()
+	min2, max2 := b2.Min(), b2.Max()
+	for i := range min1 {
+		if min1[i] != min2[i] || max1[i] != max2[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func intersect(b1, b2 rtree.Box) bool {
+	min1, max1 := b1.Min(), b1.Max()
+	min2, max2 := b2.Min(), b2.Max()
+	for i := range min1 {
+		if max2[i] < min1[i] || min2[i] > max1[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// distance returns the squared euclidean distance from the provided
+// point to the closest point of the box.
+func distance(point []float64, box rtree.Box) float64 {
+	min, max := box.Min(), box.Max()
+	var d float64
+	for i, v := range point {
+		var axis float64
+		if v < min[i] {
+			axis = min[i] - v
+		} else if v > max[i] {
+			axis = v - max[i]
+		}
+		d += axis * axis
+	}
+
+	return d
+}
+
+// orderedBits maps a float64 to a uint64 such that the order of the
+// floats is preserved.  This allows any float to be placed on the
+// Hilbert curve without knowing the extent of the data up front.
+func orderedBits(f float64) uint64 {
+	b := math.Float64bits(f)
+	if b>>63 == 1 {
+		return ^b
+	}
+
+	return b | 1<<63
+}
+
+// encode returns the Hilbert value of the center of the provided box
+// using the most significant bits of every coordinate.
+func encode(box rtree.Box, bits uint) uint64 {
+	min, max := box.Min(), box.Max()
+	coords := make([]uint32, len(min))
+	for i := range min {
+		center := min[i]/2 + max[i]/2
+		coords[i] = uint32(orderedBits(center) >> (64 - bits))
+	}
+
+	return h.EncodeN(bits, coords...)
+}
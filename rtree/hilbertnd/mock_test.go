@@ -0,0 +1,40 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbertnd
+
+type mockBox struct {
+	min, max []float64
+}
+
+func (mb *mockBox) Min() []float64 {
+	return mb.min
+}
+
+func (mb *mockBox) Max() []float64 {
+	return mb.max
+}
+
+func newMockBox(min, max []float64) *mockBox {
+	return &mockBox{
+		min: min,
+		max: max,
+	}
+}
+
+func newMockPoint(coords ...float64) *mockBox {
+	return newMockBox(coords, coords)
+}
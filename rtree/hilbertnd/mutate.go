@@ -0,0 +1,310 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbertnd
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+const multiThreadAt = 1000 // number of boxes before we multithread mutations
+
+// leafBatch holds the entries a single mutation adds to or deletes
+// from one leaf along with the path from the root to that leaf.
+type leafBatch struct {
+	path    []*node
+	entries []*entry
+}
+
+func (b *leafBatch) leaf() *node {
+	return b.path[len(b.path)-1]
+}
+
+// executeInParallel calls fn with every index in [0, n), spreading the
+// calls between the available CPUs.
+func executeInParallel(n int, fn func(int)) {
+	if n == 0 {
+		return
+	}
+
+	done := int64(-1)
+	numCPU := runtime.NumCPU()
+	if numCPU > 1 {
+		numCPU--
+	}
+	if numCPU > n {
+		numCPU = n
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(numCPU)
+
+	for i := 0; i < numCPU; i++ {
+		go func() {
+			defer wg.Done()
+
+			for {
+				i := atomic.AddInt64(&done, 1)
+				if i >= int64(n) {
+					return
+				}
+
+				fn(int(i))
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func executeInSerial(n int, fn func(int)) {
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+}
+
+// locate returns the path from the root to the leaf the provided entry
+// belongs in.  That is the leaf holding an equal box if there is one,
+// otherwise nil for a delete and for an add the leaf its key orders it
+// into.
+func (tree *tree) locate(e *entry, add bool) []*node {
+	if path, i := tree.find(tree.root, e.key, e.box, nil); i >= 0 {
+		return path
+	}
+
+	if !add {
+		return nil
+	}
+
+	path := make([]*node, 0, 8)
+	n := tree.root
+	for !n.isLeaf {
+		path = append(path, n)
+		n = n.child(n.searchChild(e.key))
+	}
+
+	return append(path, n)
+}
+
+// mutate adds or deletes the provided boxes as a batch.  The boxes are
+// grouped by the leaf they belong in and every one of those leaves is
+// changed, then the parents of the changed nodes rebalance their
+// children a level at a time up to the root.  Nodes on one level are
+// independent of each other so large batches change them in parallel.
+func (tree *tree) mutate(boxes rtree.Boxes, add bool) {
+	execute := executeInSerial
+	if len(boxes) > multiThreadAt {
+		execute = executeInParallel
+	}
+
+	entries := make([]*entry, len(boxes))
+	paths := make([][]*node, len(boxes))
+	execute(len(boxes), func(i int) {
+		entries[i] = &entry{key: encode(boxes[i], tree.bits), box: boxes[i]}
+		paths[i] = tree.locate(entries[i], add)
+	})
+
+	batches := make([]*leafBatch, 0, len(boxes))
+	byLeaf := make(map[*node]*leafBatch)
+	for i, path := range paths {
+		if path == nil {
+			continue
+		}
+
+		leaf := path[len(path)-1]
+		batch, ok := byLeaf[leaf]
+		if !ok {
+			batch = &leafBatch{path: path}
+			byLeaf[leaf] = batch
+			batches = append(batches, batch)
+		}
+		batch.entries = append(batch.entries, entries[i])
+	}
+
+	if len(batches) == 0 {
+		return
+	}
+
+	var added int64
+	execute(len(batches), func(i int) {
+		leaf := batches[i].leaf()
+		if add {
+			atomic.AddInt64(&added, int64(leaf.addAll(batches[i].entries)))
+		} else {
+			atomic.AddInt64(&added, -int64(leaf.deleteAll(batches[i].entries)))
+		}
+		leaf.recalculate()
+	})
+	tree.number += uint64(added)
+
+	// every leaf is at the same depth, so the paths to the nodes changed
+	// on one level all have the same length
+	paths = paths[:0]
+	for _, batch := range batches {
+		paths = append(paths, batch.path)
+	}
+
+	for level := len(paths[0]) - 2; level >= 0; level-- {
+		parents := make(map[*node]struct{}, len(paths))
+		next := paths[:0]
+		for _, path := range paths {
+			if _, ok := parents[path[level]]; !ok {
+				parents[path[level]] = struct{}{}
+				next = append(next, path[:level+1])
+			}
+		}
+		paths = next
+
+		execute(len(paths), func(i int) {
+			tree.rebalance(paths[i][level])
+		})
+	}
+
+	tree.adjustRoot()
+}
+
+// rebalance restores the fill of the children of the provided node once
+// they have changed.  Emptied children are removed, children holding
+// more than ary entries are split and children holding fewer than the
+// minimum are merged with, or take entries from, a neighbouring sibling.
+// Keys and bounds are refreshed along the way.
+func (tree *tree) rebalance(n *node) {
+	entries := make([]*entry, 0, len(n.entries)+1)
+	for _, e := range n.entries {
+		child := e.box.(*node)
+		switch {
+		case len(child.entries) == 0:
+			continue
+		case len(child.entries) > tree.ary:
+			for _, piece := range child.splitInto(tree.ary) {
+				entries = append(entries, &entry{key: piece.maxKey(), box: piece})
+			}
+		default:
+			e.key = child.maxKey()
+			entries = append(entries, e)
+		}
+	}
+	n.entries = entries
+
+	for i := 0; i < len(n.entries) && len(n.entries) > 1; i++ {
+		if len(n.child(i).entries) >= tree.min {
+			continue
+		}
+
+		left := i
+		if left == len(n.entries)-1 {
+			left--
+		}
+		n.join(left, tree.ary)
+
+		// joining children brings their own children together, any of
+		// which may be left underfull by an earlier merge
+		for j := left; j < len(n.entries) && j <= left+1; j++ {
+			if !n.child(j).isLeaf {
+				tree.rebalance(n.child(j))
+			}
+		}
+		i = left - 1
+	}
+
+	n.recalculate()
+}
+
+// adjustRoot adds levels above the root while it holds more than ary
+// entries and removes them while it is an internal node with a single
+// child.
+func (tree *tree) adjustRoot() {
+	for len(tree.root.entries) > tree.ary {
+		root := newNode(false, tree.ary)
+		for _, piece := range tree.root.splitInto(tree.ary) {
+			root.entries = append(root.entries, &entry{key: piece.maxKey(), box: piece})
+		}
+		root.recalculate()
+		tree.root = root
+	}
+
+	for !tree.root.isLeaf && len(tree.root.entries) == 1 {
+		tree.root = tree.root.child(0)
+	}
+	if !tree.root.isLeaf && len(tree.root.entries) == 0 {
+		tree.root = newNode(true, tree.ary)
+	}
+}
+
+// addAll adds the provided entries to this leaf.  An entry replaces the
+// entry with an equal box if there is one, including an earlier entry
+// in the same call, and otherwise goes after any entries with an equal
+// key.  Returns the number of entries added rather than replacing one.
+func (n *node) addAll(entries []*entry) int {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	fresh := make([]*entry, 0, len(entries))
+	for i, e := range entries {
+		if n.replace(e) || replacedLater(entries[i+1:], e) {
+			continue
+		}
+		fresh = append(fresh, e)
+	}
+
+	merged := make([]*entry, 0, len(n.entries)+len(fresh))
+	i := 0
+	for _, e := range fresh {
+		for i < len(n.entries) && n.entries[i].key <= e.key {
+			merged = append(merged, n.entries[i])
+			i++
+		}
+		merged = append(merged, e)
+	}
+	n.entries = append(merged, n.entries[i:]...)
+
+	return len(fresh)
+}
+
+// replacedLater returns whether an entry among the provided entries,
+// which are sorted by key, has a box equal to that of the given entry.
+func replacedLater(entries []*entry, e *entry) bool {
+	for _, later := range entries {
+		if later.key != e.key {
+			return false
+		}
+		if equal(later.box, e.box) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deleteAll deletes the entries with boxes equal to those of the
+// provided entries from this leaf and returns the number deleted.
+func (n *node) deleteAll(entries []*entry) int {
+	deleted := 0
+	for _, e := range entries {
+		if i := n.indexOf(e); i >= 0 {
+			n.deleteAt(i)
+			deleted++
+		}
+	}
+
+	return deleted
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbertnd
+
+import (
+	"sort"
+
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+// entry is a single slot in a node.  In a leaf the box is one provided
+// by the consumer and key is the Hilbert value of its center.  In an
+// internal node the box is a child node and key is the largest Hilbert
+// value found in that child.
+type entry struct {
+	key uint64
+	box rtree.Box
+}
+
+type node struct {
+	isLeaf  bool
+	entries []*entry
+	mbr     *bounds
+}
+
+func (n *node) Min() []float64 {
+	return n.mbr.min
+}
+
+func (n *node) Max() []float64 {
+	return n.mbr.max
+}
+
+func (n *node) child(i int) *node {
+	return n.entries[i].box.(*node)
+}
+
+func (n *node) maxKey() uint64 {
+	return n.entries[len(n.entries)-1].key
+}
+
+// search returns the index of the first entry with a key greater
+// than or equal to the provided key.
+func (n *node) search(key uint64) int {
+	return sort.Search(len(n.entries), func(i int) bool {
+		return n.entries[i].key >= key
+	})
+}
+
+// searchChild returns the index of the child an entry with the provided
+// key should be inserted into.
+func (n *node) searchChild(key uint64) int {
+	i := n.search(key)
+	if i == len(n.entries) {
+		i--
+	}
+
+	return i
+}
+
+func (n *node) insertAt(i int, e *entry) {
+	n.entries = append(n.entries, nil)
+	copy(n.entries[i+1:], n.entries[i:])
+	n.entries[i] = e
+}
+
+func (n *node) deleteAt(i int) {
+	copy(n.entries[i:], n.entries[i+1:])
+	n.entries[len(n.entries)-1] = nil
+	n.entries = n.entries[:len(n.entries)-1]
+}
+
+func (n *node) boxes() rtree.Boxes {
+	boxes := make(rtree.Boxes, 0, len(n.entries))
+	for _, e := range n.entries {
+		boxes = append(boxes, e.box)
+	}
+
+	return boxes
+}
+
+// recalculate rebuilds the bounds of this node from its entries.
+func (n *node) recalculate() {
+	if len(n.entries) == 0 {
+		return
+	}
+
+	n.mbr = newBoundsFromBoxes(n.boxes())
+}
+
+// indexOf returns the index of the entry in this leaf with a box equal
+// to that of the provided entry, or -1 if there is none.
+func (n *node) indexOf(e *entry) int {
+	for i := n.search(e.key); i < len(n.entries) && n.entries[i].key == e.key; i++ {
+		if equal(n.entries[i].box, e.box) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// replace swaps the provided entry's box into the entry in this leaf
+// with an equal box and returns whether there was one.
+func (n *node) replace(e *entry) bool {
+	i := n.indexOf(e)
+	if i < 0 {
+		return false
+	}
+
+	n.entries[i].box = e.box
+	return true
+}
+
+// splitInto divides this node's entries evenly between as few nodes as
+// can hold them with at most ary entries each.  This node becomes the
+// first of those nodes.
+func (n *node) splitInto(ary int) []*node {
+	pieces := (len(n.entries) + ary - 1) / ary
+	nodes := make([]*node, 0, pieces)
+	entries := n.entries
+	for i := 0; i < pieces; i++ {
+		size := len(entries) / (pieces - i)
+		nn := n
+		if i > 0 {
+			nn = newNode(n.isLeaf, ary)
+		}
+		nn.entries = append(make([]*entry, 0, ary+1), entries[:size]...)
+		nn.recalculate()
+		nodes = append(nodes, nn)
+		entries = entries[size:]
+	}
+
+	return nodes
+}
+
+// join brings together the children at i and i+1, moving every entry
+// into the left child if they fit and otherwise sharing the entries
+// evenly between the two.
+func (n *node) join(i, ary int) {
+	left, right := n.child(i), n.child(i+1)
+	entries := append(append(make([]*entry, 0, ary*2+1), left.entries...), right.entries...)
+
+	if len(entries) <= ary {
+		left.entries = entries
+		left.recalculate()
+		n.entries[i].key = left.maxKey()
+		n.deleteAt(i + 1)
+		return
+	}
+
+	half := len(entries) / 2
+	left.entries = append(make([]*entry, 0, ary+1), entries[:half]...)
+	right.entries = append(make([]*entry, 0, ary+1), entries[half:]...)
+	left.recalculate()
+	right.recalculate()
+	n.entries[i].key = left.maxKey()
+	n.entries[i+1].key = right.maxKey()
+}
+
+func newNode(isLeaf bool, ary int) *node {
+	return &node{
+		isLeaf:  isLeaf,
+		entries: make([]*entry, 0, ary+1),
+	}
+}
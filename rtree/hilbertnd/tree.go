@@ -0,0 +1,257 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package hilbertnd implements an N-dimensional Hilbert R-tree over
+float64 coordinates.  It is the counterpart of the hilbert package for
+data that cannot be quantized to 2-D int32 coordinates, like geographic
+or 3-D data.
+
+Boxes are ordered by the Hilbert value of their centers.  Coordinates
+are mapped onto the curve through an order preserving transformation of
+their bits, so no bounds need to be known in advance and the boxes
+themselves are stored and searched at full precision; the Hilbert value
+only determines where a box is placed in the tree.  With d dimensions
+64/d bits of every coordinate (at most 32) contribute to the Hilbert
+value.
+
+Like the hilbert package, the boxes given to a single Insert or Delete
+are applied as a batch: every leaf they touch is changed first and then
+the tree is rebalanced a level at a time up to the root, in parallel
+for large batches.  Nodes left with fewer than half of ary entries are
+merged with or refilled from a neighbouring sibling.  Unlike the hilbert
+package, separate calls are not queued; reads take a shared lock and may
+proceed in parallel while writes are serialized.
+*/
+package hilbertnd
+
+import (
+	"sync"
+
+	"github.com/Workiva/go-datastructures/queue"
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+type tree struct {
+	lock   sync.RWMutex
+	root   *node
+	number uint64
+	dims   int
+	bits   uint
+	ary    int
+	min    int // fewest entries a node other than the root may hold
+}
+
+func (tree *tree) check(box rtree.Box) {
+	if len(box.Min()) != tree.dims || len(box.Max()) != tree.dims {
+		panic(`box does not match the dimensions of the tree`)
+	}
+}
+
+// find returns the path from the root to the leaf holding a box equal
+// to the provided box along with its index in that leaf.  As boxes with
+// equal Hilbert values may span several children, every child whose
+// range of keys could hold the key is visited.
+func (tree *tree) find(n *node, key uint64, box rtree.Box, path []*node) ([]*node, int) {
+	path = append(path, n)
+	if n.isLeaf {
+		if i := n.indexOf(&entry{key: key, box: box}); i >= 0 {
+			return path, i
+		}
+
+		return nil, -1
+	}
+
+	for i := n.search(key); i < len(n.entries); i++ {
+		if i > 0 && n.entries[i-1].key > key {
+			break
+		}
+
+		p, j := tree.find(n.child(i), key, box, path)
+		if j >= 0 {
+			return p, j
+		}
+
+		if n.entries[i].key > key {
+			break
+		}
+	}
+
+	return nil, -1
+}
+
+// Insert will add the provided boxes to the tree.  A box equal to
+// one already in the tree replaces it.  Panics if a box does not
+// match the dimensions of the tree.
+func (tree *tree) Insert(boxes ...rtree.Box) {
+	for _, box := range boxes {
+		tree.check(box)
+	}
+
+	tree.lock.Lock()
+	defer tree.lock.Unlock()
+
+	tree.mutate(boxes, true)
+}
+
+// Delete will remove the provided boxes from the tree.  If no
+// matching box is found, this is a no-op.  Panics if a box does not
+// match the dimensions of the tree.
+func (tree *tree) Delete(boxes ...rtree.Box) {
+	for _, box := range boxes {
+		tree.check(box)
+	}
+
+	tree.lock.Lock()
+	defer tree.lock.Unlock()
+
+	tree.mutate(boxes, false)
+}
+
+// Search will return a list of boxes that intersect the provided box.
+func (tree *tree) Search(box rtree.Box) rtree.Boxes {
+	tree.check(box)
+	tree.lock.RLock()
+	defer tree.lock.RUnlock()
+
+	result := make(rtree.Boxes, 0, 10)
+	if len(tree.root.entries) == 0 {
+		return result
+	}
+
+	stack := []*node{tree.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, e := range n.entries {
+			if !intersect(box, e.box) {
+				continue
+			}
+
+			if n.isLeaf {
+				result = append(result, e.box)
+			} else {
+				stack = append(stack, e.box.(*node))
+			}
+		}
+	}
+
+	return result
+}
+
+// nearestEntry is an entry in the best-first search, either a node
+// or a box stored in the tree, along with its squared distance from
+// the query point.
+type nearestEntry struct {
+	box      rtree.Box
+	distance float64
+}
+
+func compareNearestEntries(a, b *nearestEntry) int {
+	switch {
+	case a.distance < b.distance:
+		return -1
+	case a.distance > b.distance:
+		return 1
+	}
+
+	return 0
+}
+
+// NearestNeighbors will return up to k boxes ordered by their distance
+// from the provided point, nearest first.  Distance is measured to the
+// closest point of each box so any box containing the point has a
+// distance of 0.
+func (tree *tree) NearestNeighbors(point []float64, k int) rtree.Boxes {
+	if len(point) != tree.dims {
+		panic(`point does not match the dimensions of the tree`)
+	}
+
+	tree.lock.RLock()
+	defer tree.lock.RUnlock()
+
+	if k <= 0 || len(tree.root.entries) == 0 {
+		return rtree.Boxes{}
+	}
+
+	result := make(rtree.Boxes, 0, k)
+	pq := queue.NewPriorityQueueOf(tree.ary, true, compareNearestEntries)
+	pq.Put(&nearestEntry{box: tree.root, distance: distance(point, tree.root)})
+
+	for !pq.Empty() && len(result) < k {
+		entries, err := pq.Get(1)
+		if err != nil {
+			break
+		}
+
+		if n, ok := entries[0].box.(*node); ok {
+			for _, e := range n.entries {
+				pq.Put(&nearestEntry{box: e.box, distance: distance(point, e.box)})
+			}
+		} else {
+			result = append(result, entries[0].box)
+		}
+	}
+
+	return result
+}
+
+// Len returns the number of items in the tree.
+func (tree *tree) Len() uint64 {
+	tree.lock.RLock()
+	defer tree.lock.RUnlock()
+
+	return tree.number
+}
+
+// Dispose will clean up any resources used by this tree.
+func (tree *tree) Dispose() {
+	tree.lock.Lock()
+	defer tree.lock.Unlock()
+
+	tree.root = newNode(true, tree.ary)
+	tree.number = 0
+}
+
+func newTree(dims int, ary uint64) *tree {
+	if dims < 1 || dims > 64 {
+		panic(`dimensions must be in the range [1, 64]`)
+	}
+
+	if ary < 3 {
+		ary = 3
+	}
+
+	bits := uint(64 / dims)
+	if bits > 32 {
+		bits = 32
+	}
+
+	return &tree{
+		root: newNode(true, int(ary)),
+		dims: dims,
+		bits: bits,
+		ary:  int(ary),
+		min:  int(ary) / 2,
+	}
+}
+
+// New will construct a new N-dimensional Hilbert R-Tree and return it.
+// Ary is the maximum number of entries in a node and must be at least
+// 3.
+func New(dims int, ary uint64) rtree.BoxTree {
+	return newTree(dims, ary)
+}
@@ -0,0 +1,376 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hilbertnd
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/rtree"
+)
+
+func constructRandomMockBoxes(num, dims int) rtree.Boxes {
+	boxes := make(rtree.Boxes, 0, num)
+	for i := 0; i < num; i++ {
+		min := make([]float64, dims)
+		max := make([]float64, dims)
+		for j := range min {
+			min[j] = rand.Float64()*2000 - 1000
+			max[j] = min[j] + rand.Float64()*5
+		}
+		boxes = append(boxes, newMockBox(min, max))
+	}
+
+	return boxes
+}
+
+func constructInfiniteBox(dims int) rtree.Box {
+	min := make([]float64, dims)
+	max := make([]float64, dims)
+	for i := range min {
+		min[i] = math.Inf(-1)
+		max[i] = math.Inf(1)
+	}
+
+	return newMockBox(min, max)
+}
+
+// checkNode verifies that keys are ordered, internal keys match the
+// largest key of their child and every bound contains its entries.
+func checkNode(t *testing.T, n *node) uint64 {
+	var count uint64
+	for i, e := range n.entries {
+		if i > 0 {
+			assert.True(t, n.entries[i-1].key <= e.key)
+		}
+		for d := range n.mbr.min {
+			assert.True(t, n.mbr.min[d] <= e.box.Min()[d])
+			assert.True(t, n.mbr.max[d] >= e.box.Max()[d])
+		}
+
+		if n.isLeaf {
+			count++
+			continue
+		}
+
+		child := e.box.(*node)
+		assert.NotEmpty(t, child.entries)
+		assert.Equal(t, child.maxKey(), e.key)
+		count += checkNode(t, child)
+	}
+
+	return count
+}
+
+// checkFill verifies that every node other than the root holds between
+// min and ary entries and that every leaf is at the same depth, which
+// it returns.
+func checkFill(t *testing.T, tree *tree, n *node, isRoot bool) int {
+	if !isRoot {
+		assert.True(t, len(n.entries) >= tree.min, "node holds %d entries", len(n.entries))
+	}
+	assert.True(t, len(n.entries) <= tree.ary, "node holds %d entries", len(n.entries))
+
+	if n.isLeaf {
+		return 1
+	}
+
+	depth := checkFill(t, tree, n.child(0), false)
+	for i := 1; i < len(n.entries); i++ {
+		assert.Equal(t, depth, checkFill(t, tree, n.child(i), false))
+	}
+
+	return depth + 1
+}
+
+func TestSimpleInsert(t *testing.T) {
+	tree := newTree(3, 3)
+	b := newMockPoint(1.5, -2.25, 3)
+	tree.Insert(b)
+
+	assert.Equal(t, uint64(1), tree.Len())
+	assert.Equal(t, rtree.Boxes{b}, tree.Search(b))
+	assert.Equal(t, rtree.Boxes{b}, tree.Search(constructInfiniteBox(3)))
+	assert.Len(t, tree.Search(newMockPoint(1.5, -2.25, 3.1)), 0)
+}
+
+func TestInsertDuplicate(t *testing.T) {
+	tree := newTree(2, 3)
+	b1 := newMockBox([]float64{0, 0}, []float64{1, 1})
+	b2 := newMockBox([]float64{0, 0}, []float64{1, 1})
+	tree.Insert(b1)
+	tree.Insert(b2)
+
+	assert.Equal(t, uint64(1), tree.Len())
+	assert.Equal(t, rtree.Boxes{b2}, tree.Search(constructInfiniteBox(2)))
+}
+
+func TestMultipleInsertsAndSearch(t *testing.T) {
+	for _, dims := range []int{1, 2, 3, 5} {
+		boxes := constructRandomMockBoxes(1000, dims)
+		tree := newTree(dims, 4)
+		tree.Insert(boxes...)
+
+		assert.Equal(t, uint64(len(boxes)), tree.Len())
+		assert.Equal(t, uint64(len(boxes)), checkNode(t, tree.root))
+		assert.Len(t, tree.Search(constructInfiniteBox(dims)), len(boxes))
+		for _, b := range boxes {
+			assert.Contains(t, tree.Search(b), b)
+		}
+	}
+}
+
+func TestSearchMatchesBruteForce(t *testing.T) {
+	boxes := constructRandomMockBoxes(2000, 3)
+	tree := newTree(3, 8)
+	tree.Insert(boxes...)
+
+	for i := 0; i < 50; i++ {
+		query := constructRandomMockBoxes(1, 3)[0].(*mockBox)
+		for d := range query.max {
+			query.max[d] += 200
+		}
+
+		expected := 0
+		for _, b := range boxes {
+			if intersect(query, b) {
+				expected++
+			}
+		}
+
+		result := tree.Search(query)
+		assert.Len(t, result, expected)
+		for _, b := range result {
+			assert.True(t, intersect(query, b))
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	boxes := constructRandomMockBoxes(1000, 3)
+	tree := newTree(3, 4)
+	tree.Insert(boxes...)
+
+	tree.Delete(boxes[:500]...)
+	assert.Equal(t, uint64(500), tree.Len())
+	assert.Equal(t, uint64(500), checkNode(t, tree.root))
+	for _, b := range boxes[:500] {
+		assert.NotContains(t, tree.Search(b), b)
+	}
+	for _, b := range boxes[500:] {
+		assert.Contains(t, tree.Search(b), b)
+	}
+
+	// deleting something not in the tree is a no-op
+	tree.Delete(boxes[0])
+	assert.Equal(t, uint64(500), tree.Len())
+
+	tree.Delete(boxes[500:]...)
+	assert.Equal(t, uint64(0), tree.Len())
+	assert.True(t, tree.root.isLeaf)
+	assert.Len(t, tree.Search(constructInfiniteBox(3)), 0)
+
+	tree.Insert(boxes...)
+	assert.Equal(t, uint64(1000), tree.Len())
+	assert.Equal(t, uint64(1000), checkNode(t, tree.root))
+}
+
+func TestDeleteKeepsNodesFilled(t *testing.T) {
+	for _, ary := range []uint64{3, 4, 8, 16} {
+		boxes := constructRandomMockBoxes(5000, 3)
+		tree := newTree(3, ary)
+		for _, b := range boxes {
+			tree.Insert(b)
+		}
+		checkFill(t, tree, tree.root, true)
+
+		// delete one at a time, then in batches, from a shuffled order
+		// so that every part of the tree is thinned out
+		order := rand.Perm(len(boxes))
+		for _, i := range order[:1000] {
+			tree.Delete(boxes[i])
+		}
+		assert.Equal(t, uint64(4000), tree.Len())
+		assert.Equal(t, uint64(4000), checkNode(t, tree.root))
+		checkFill(t, tree, tree.root, true)
+
+		for start := 1000; start < len(order)-100; start += 700 {
+			batch := make(rtree.Boxes, 0, 700)
+			for _, i := range order[start:min(start+700, len(order)-100)] {
+				batch = append(batch, boxes[i])
+			}
+			tree.Delete(batch...)
+			assert.Equal(t, checkNode(t, tree.root), tree.Len())
+			checkFill(t, tree, tree.root, true)
+		}
+
+		assert.Equal(t, uint64(100), tree.Len())
+		for _, i := range order[len(order)-100:] {
+			assert.Contains(t, tree.Search(boxes[i]), boxes[i])
+		}
+	}
+}
+
+func TestLargeBatches(t *testing.T) {
+	boxes := constructRandomMockBoxes(20000, 3)
+	tree := newTree(3, 8)
+	tree.Insert(boxes[:10000]...)
+	tree.Insert(boxes[10000:]...)
+
+	assert.Equal(t, uint64(20000), tree.Len())
+	assert.Equal(t, uint64(20000), checkNode(t, tree.root))
+	checkFill(t, tree, tree.root, true)
+
+	// a batch may repeat a box, the last one wins
+	tree.Insert(boxes[0], boxes[0], boxes[1])
+	assert.Equal(t, uint64(20000), tree.Len())
+
+	tree.Delete(boxes[5000:17000]...)
+	assert.Equal(t, uint64(8000), tree.Len())
+	assert.Equal(t, uint64(8000), checkNode(t, tree.root))
+	checkFill(t, tree, tree.root, true)
+	for _, b := range boxes[:5000] {
+		assert.Contains(t, tree.Search(b), b)
+	}
+	for _, b := range boxes[17000:] {
+		assert.Contains(t, tree.Search(b), b)
+	}
+
+	tree.Delete(boxes...)
+	assert.Equal(t, uint64(0), tree.Len())
+	assert.True(t, tree.root.isLeaf)
+}
+
+func TestDuplicateHilbertValues(t *testing.T) {
+	// boxes sharing a center share a Hilbert value and may span
+	// several leaves
+	tree := newTree(2, 3)
+	boxes := make(rtree.Boxes, 0, 20)
+	for i := 0; i < 20; i++ {
+		f := float64(i)
+		boxes = append(boxes, newMockBox([]float64{-f, -f}, []float64{f, f}))
+	}
+	tree.Insert(boxes...)
+	assert.Equal(t, uint64(20), tree.Len())
+
+	tree.Insert(newMockBox([]float64{-5, -5}, []float64{5, 5}))
+	assert.Equal(t, uint64(20), tree.Len())
+
+	for i := len(boxes) - 1; i >= 0; i-- {
+		tree.Delete(boxes[i])
+		assert.Equal(t, uint64(i), tree.Len())
+		checkNode(t, tree.root)
+		checkFill(t, tree, tree.root, true)
+	}
+}
+
+func TestNearestNeighbors(t *testing.T) {
+	boxes := constructRandomMockBoxes(2000, 3)
+	tree := newTree(3, 8)
+	tree.Insert(boxes...)
+
+	for i := 0; i < 50; i++ {
+		point := constructRandomMockBoxes(1, 3)[0].Min()
+		result := tree.NearestNeighbors(point, 10)
+		if !assert.Len(t, result, 10) {
+			continue
+		}
+
+		expected := make([]float64, 0, len(boxes))
+		for _, b := range boxes {
+			expected = append(expected, distance(point, b))
+		}
+		sort.Float64s(expected)
+
+		for j, b := range result {
+			assert.Equal(t, expected[j], distance(point, b))
+		}
+	}
+}
+
+func TestNearestNeighborsEmpty(t *testing.T) {
+	tree := newTree(2, 3)
+	assert.Len(t, tree.NearestNeighbors([]float64{0, 0}, 5), 0)
+
+	b := newMockPoint(1, 1)
+	tree.Insert(b)
+	assert.Len(t, tree.NearestNeighbors([]float64{0, 0}, 0), 0)
+	assert.Equal(t, rtree.Boxes{b}, tree.NearestNeighbors([]float64{0, 0}, 5))
+}
+
+func TestDimensionMismatch(t *testing.T) {
+	tree := newTree(2, 3)
+	assert.Panics(t, func() { tree.Insert(newMockPoint(1, 2, 3)) })
+	assert.Panics(t, func() { tree.Search(newMockPoint(1)) })
+	assert.Panics(t, func() { tree.NearestNeighbors([]float64{1}, 1) })
+	assert.Panics(t, func() { newTree(0, 3) })
+}
+
+func TestOrderedBits(t *testing.T) {
+	values := []float64{math.Inf(-1), -1e300, -2.5, -1, -1e-300, 0, 1e-300, 1, 2.5, 1e300, math.Inf(1)}
+	for i := 1; i < len(values); i++ {
+		assert.True(t, orderedBits(values[i-1]) < orderedBits(values[i]))
+	}
+}
+
+func BenchmarkInsert(b *testing.B) {
+	boxes := constructRandomMockBoxes(b.N, 3)
+	tree := newTree(3, 16)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.Insert(boxes[i])
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	boxes := constructRandomMockBoxes(10000, 3)
+	tree := newTree(3, 16)
+	tree.Insert(boxes...)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.Search(boxes[i%len(boxes)])
+	}
+}
+
+func BenchmarkNearestNeighbors(b *testing.B) {
+	boxes := constructRandomMockBoxes(10000, 3)
+	tree := newTree(3, 16)
+	tree.Insert(boxes...)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tree.NearestNeighbors(boxes[i%len(boxes)].Min(), 10)
+	}
+}
+
+func BenchmarkBulkInsert(b *testing.B) {
+	boxes := constructRandomMockBoxes(b.N, 3)
+	tree := newTree(3, 16)
+
+	b.ResetTimer()
+
+	tree.Insert(boxes...)
+}
@@ -42,3 +42,46 @@ type RTree interface {
 	// Insert will add the provided rectangles to the RTree.
 	Insert(...Rectangle)
 }
+
+// NearestNeighborer is implemented by RTrees that can also find the
+// rectangles nearest to a point, an optional capability checked for
+// with a type assertion.
+type NearestNeighborer interface {
+	RTree
+	// NearestNeighbors will return up to k rectangles ordered by
+	// their distance from the provided point, nearest first.
+	NearestNeighbors(x, y int32, k int) Rectangles
+}
+
+// Boxes is a typed list of Box.
+type Boxes []Box
+
+// Box describes an N-dimensional bound with float64 coordinates.  Min
+// and Max must return slices of the same length, one value for each
+// dimension, with every value of Min less than or equal to the matching
+// value of Max.
+type Box interface {
+	// Min describes the lowest coordinate of this box.
+	Min() []float64
+	// Max describes the highest coordinate of this box.
+	Max() []float64
+}
+
+// BoxTree defines the N-dimensional, float64 counterpart of RTree,
+// including nearest neighbour search.
+type BoxTree interface {
+	// Search will perform an intersection search of the given
+	// box and return any boxes that intersect.
+	Search(Box) Boxes
+	// NearestNeighbors will return up to k boxes ordered by their
+	// distance from the provided point, nearest first.
+	NearestNeighbors(point []float64, k int) Boxes
+	// Len returns in the number of items in the BoxTree.
+	Len() uint64
+	// Dispose will clean up any objects used by the BoxTree.
+	Dispose()
+	// Delete will remove the provided boxes from the BoxTree.
+	Delete(...Box)
+	// Insert will add the provided boxes to the BoxTree.
+	Insert(...Box)
+}
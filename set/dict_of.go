@@ -0,0 +1,138 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import "sync"
+
+// SetOf is a threadsafe set of items of type T implemented using the
+// builtin map type.  Unlike Set, a SetOf is not pooled as the pool cannot
+// be shared across type parameters.
+type SetOf[T comparable] struct {
+	items     map[T]struct{}
+	lock      sync.RWMutex
+	flattened []T
+}
+
+// Add will add the provided items to the set.
+func (set *SetOf[T]) Add(items ...T) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	set.flattened = nil
+	for _, item := range items {
+		set.items[item] = struct{}{}
+	}
+}
+
+// Remove will remove the given items from the set.
+func (set *SetOf[T]) Remove(items ...T) {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	set.flattened = nil
+	for _, item := range items {
+		delete(set.items, item)
+	}
+}
+
+// Exists returns a bool indicating if the given item exists in the set.
+func (set *SetOf[T]) Exists(item T) bool {
+	set.lock.RLock()
+
+	_, ok := set.items[item]
+
+	set.lock.RUnlock()
+
+	return ok
+}
+
+// Flatten will return a list of the items in the set.
+func (set *SetOf[T]) Flatten() []T {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	if set.flattened != nil {
+		return set.flattened
+	}
+
+	set.flattened = make([]T, 0, len(set.items))
+	for item := range set.items {
+		set.flattened = append(set.flattened, item)
+	}
+	return set.flattened
+}
+
+// Len returns the number of items in the set.
+func (set *SetOf[T]) Len() int64 {
+	set.lock.RLock()
+
+	size := int64(len(set.items))
+
+	set.lock.RUnlock()
+
+	return size
+}
+
+// Clear will remove all items from the set.
+func (set *SetOf[T]) Clear() {
+	set.lock.Lock()
+
+	set.items = map[T]struct{}{}
+	set.flattened = nil
+
+	set.lock.Unlock()
+}
+
+// All returns a bool indicating if all of the supplied items exist in the set.
+func (set *SetOf[T]) All(items ...T) bool {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	for _, item := range items {
+		if _, ok := set.items[item]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Dispose will remove all items from the set and release any references
+// held by the set.
+func (set *SetOf[T]) Dispose() {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	for k := range set.items {
+		delete(set.items, k)
+	}
+
+	set.flattened = nil
+}
+
+// NewOf is the constructor for sets of type T.  Takes a list of items to
+// initialize the set with.
+func NewOf[T comparable](items ...T) *SetOf[T] {
+	set := &SetOf[T]{
+		items: make(map[T]struct{}, len(items)),
+	}
+	for _, item := range items {
+		set.items[item] = struct{}{}
+	}
+
+	return set
+}
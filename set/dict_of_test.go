@@ -0,0 +1,64 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetOfAddRemove(t *testing.T) {
+	set := NewOf(1, 2)
+	set.Add(2, 3)
+
+	if set.Len() != 3 {
+		t.Errorf(`Expected len: %d, received: %d`, 3, set.Len())
+	}
+
+	set.Remove(1, 3)
+
+	if !reflect.DeepEqual([]int{2}, set.Flatten()) {
+		t.Errorf(`Incorrect result returned: %+v`, set.Flatten())
+	}
+}
+
+func TestSetOfExistsAndAll(t *testing.T) {
+	set := NewOf(`a`, `b`)
+
+	if !set.Exists(`a`) || set.Exists(`c`) {
+		t.Errorf(`Incorrect existence reported`)
+	}
+
+	if !set.All(`a`, `b`) || set.All(`a`, `c`) {
+		t.Errorf(`Incorrect all reported`)
+	}
+}
+
+func TestSetOfFlattenCache(t *testing.T) {
+	set := NewOf(`test`)
+	set.Flatten()
+
+	set.Add(`test2`)
+	if set.flattened != nil {
+		t.Errorf(`Flattened cache not cleared on add`)
+	}
+
+	set.Clear()
+	if set.Len() != 0 || len(set.Flatten()) != 0 {
+		t.Errorf(`Set not cleared`)
+	}
+}
@@ -49,14 +49,20 @@ func defaultHashFactory() hash.Hash32 {
 	return fnv.New32a()
 }
 
-// Ctrie is a concurrent, lock-free hash trie. By default, keys are hashed
-// using FNV-1a unless a HashFactory is provided to New.
-type Ctrie struct {
-	root        *iNode
+// CtrieOf is a concurrent, lock-free hash trie mapping keys to values of
+// type V. By default, keys are hashed using FNV-1a unless a HashFactory is
+// provided to NewOf.
+type CtrieOf[V any] struct {
+	root        *iNode[V]
 	readOnly    bool
 	hashFactory HashFactory
 }
 
+// Ctrie is a concurrent, lock-free hash trie with interface{} values. By
+// default, keys are hashed using FNV-1a unless a HashFactory is provided to
+// New.
+type Ctrie = CtrieOf[interface{}]
+
 // generation demarcates Ctrie snapshots. We use a heap-allocated reference
 // instead of an integer to avoid integer overflows. Struct must have a field
 // on it since two distinct zero-size variables may have the same address in
@@ -66,19 +72,19 @@ type generation struct{ _ int }
 // iNode is an indirection node. I-nodes remain present in the Ctrie even as
 // nodes above and below change. Thread-safety is achieved in part by
 // performing CAS operations on the I-node instead of the internal node array.
-type iNode struct {
-	main *mainNode
+type iNode[V any] struct {
+	main *mainNode[V]
 	gen  *generation
 
 	// rdcss is set during an RDCSS operation. The I-node is actually a wrapper
 	// around the descriptor in this case so that a single type is used during
 	// CAS operations on the root.
-	rdcss *rdcssDescriptor
+	rdcss *rdcssDescriptor[V]
 }
 
 // copyToGen returns a copy of this I-node copied to the given generation.
-func (i *iNode) copyToGen(gen *generation, ctrie *Ctrie) *iNode {
-	nin := &iNode{gen: gen}
+func (i *iNode[V]) copyToGen(gen *generation, ctrie *CtrieOf[V]) *iNode[V] {
+	nin := &iNode[V]{gen: gen}
 	main := gcasRead(i, ctrie)
 	atomic.StorePointer(
 		(*unsafe.Pointer)(unsafe.Pointer(&nin.main)), unsafe.Pointer(main))
@@ -87,23 +93,23 @@ func (i *iNode) copyToGen(gen *generation, ctrie *Ctrie) *iNode {
 
 // mainNode is either a cNode, tNode, lNode, or failed node which makes up an
 // I-node.
-type mainNode struct {
-	cNode  *cNode
-	tNode  *tNode
-	lNode  *lNode
-	failed *mainNode
+type mainNode[V any] struct {
+	cNode  *cNode[V]
+	tNode  *tNode[V]
+	lNode  *lNode[V]
+	failed *mainNode[V]
 
 	// prev is set as a failed main node when we attempt to CAS and the
 	// I-node's generation does not match the root generation. This signals
 	// that the GCAS failed and the I-node's main node must be set back to the
 	// previous value.
-	prev *mainNode
+	prev *mainNode[V]
 }
 
 // cNode is an internal main node containing a bitmap and the array with
 // references to branch nodes. A branch node is either another I-node or a
 // singleton S-node.
-type cNode struct {
+type cNode[V any] struct {
 	bmp   uint32
 	array []branch
 	gen   *generation
@@ -113,7 +119,7 @@ type cNode struct {
 // mainNode will consist of cNodes as long as the hashcode chunks of the two
 // keys are equal at the given level. If the level exceeds 2^w, an lNode is
 // created.
-func newMainNode(x *sNode, xhc uint32, y *sNode, yhc uint32, lev uint, gen *generation) *mainNode {
+func newMainNode[V any](x *sNode[V], xhc uint32, y *sNode[V], yhc uint32, lev uint, gen *generation) *mainNode[V] {
 	if lev < exp2 {
 		xidx := (xhc >> lev) & 0x1f
 		yidx := (yhc >> lev) & 0x1f
@@ -122,21 +128,21 @@ func newMainNode(x *sNode, xhc uint32, y *sNode, yhc uint32, lev uint, gen *gene
 		if xidx == yidx {
 			// Recurse when indexes are equal.
 			main := newMainNode(x, xhc, y, yhc, lev+w, gen)
-			iNode := &iNode{main: main, gen: gen}
-			return &mainNode{cNode: &cNode{bmp, []branch{iNode}, gen}}
+			iNode := &iNode[V]{main: main, gen: gen}
+			return &mainNode[V]{cNode: &cNode[V]{bmp, []branch{iNode}, gen}}
 		}
 		if xidx < yidx {
-			return &mainNode{cNode: &cNode{bmp, []branch{x, y}, gen}}
+			return &mainNode[V]{cNode: &cNode[V]{bmp, []branch{x, y}, gen}}
 		}
-		return &mainNode{cNode: &cNode{bmp, []branch{y, x}, gen}}
+		return &mainNode[V]{cNode: &cNode[V]{bmp, []branch{y, x}, gen}}
 	}
-	l := list.Empty.Add(x).Add(y)
-	return &mainNode{lNode: &lNode{l}}
+	l := list.EmptyOf[*sNode[V]]().Add(x).Add(y)
+	return &mainNode[V]{lNode: &lNode[V]{l}}
 }
 
 // inserted returns a copy of this cNode with the new entry at the given
 // position.
-func (c *cNode) inserted(pos, flag uint32, br branch, gen *generation) *cNode {
+func (c *cNode[V]) inserted(pos, flag uint32, br branch, gen *generation) *cNode[V] {
 	length := uint32(len(c.array))
 	bmp := c.bmp
 	array := make([]branch, length+1)
@@ -146,23 +152,23 @@ func (c *cNode) inserted(pos, flag uint32, br branch, gen *generation) *cNode {
 		array[i+1] = c.array[i]
 		x++
 	}
-	ncn := &cNode{bmp: bmp | flag, array: array, gen: gen}
+	ncn := &cNode[V]{bmp: bmp | flag, array: array, gen: gen}
 	return ncn
 }
 
 // updated returns a copy of this cNode with the entry at the given index
 // updated.
-func (c *cNode) updated(pos uint32, br branch, gen *generation) *cNode {
+func (c *cNode[V]) updated(pos uint32, br branch, gen *generation) *cNode[V] {
 	array := make([]branch, len(c.array))
 	copy(array, c.array)
 	array[pos] = br
-	ncn := &cNode{bmp: c.bmp, array: array, gen: gen}
+	ncn := &cNode[V]{bmp: c.bmp, array: array, gen: gen}
 	return ncn
 }
 
 // removed returns a copy of this cNode with the entry at the given index
 // removed.
-func (c *cNode) removed(pos, flag uint32, gen *generation) *cNode {
+func (c *cNode[V]) removed(pos, flag uint32, gen *generation) *cNode[V] {
 	length := uint32(len(c.array))
 	bmp := c.bmp
 	array := make([]branch, length-1)
@@ -173,109 +179,119 @@ func (c *cNode) removed(pos, flag uint32, gen *generation) *cNode {
 		array[i] = c.array[i+1]
 		x++
 	}
-	ncn := &cNode{bmp: bmp ^ flag, array: array, gen: gen}
+	ncn := &cNode[V]{bmp: bmp ^ flag, array: array, gen: gen}
 	return ncn
 }
 
 // renewed returns a copy of this cNode with the I-nodes below it copied to the
 // given generation.
-func (c *cNode) renewed(gen *generation, ctrie *Ctrie) *cNode {
+func (c *cNode[V]) renewed(gen *generation, ctrie *CtrieOf[V]) *cNode[V] {
 	array := make([]branch, len(c.array))
 	for i, br := range c.array {
 		switch t := br.(type) {
-		case *iNode:
+		case *iNode[V]:
 			array[i] = t.copyToGen(gen, ctrie)
 		default:
 			array[i] = br
 		}
 	}
-	return &cNode{bmp: c.bmp, array: array, gen: gen}
+	return &cNode[V]{bmp: c.bmp, array: array, gen: gen}
 }
 
 // tNode is tomb node which is a special node used to ensure proper ordering
 // during removals.
-type tNode struct {
-	*sNode
+type tNode[V any] struct {
+	*sNode[V]
 }
 
 // untombed returns the S-node contained by the T-node.
-func (t *tNode) untombed() *sNode {
-	return &sNode{&Entry{Key: t.Key, hash: t.hash, Value: t.Value}}
+func (t *tNode[V]) untombed() *sNode[V] {
+	return &sNode[V]{&EntryOf[V]{Key: t.Key, hash: t.hash, Value: t.Value}}
 }
 
 // lNode is a list node which is a leaf node used to handle hashcode
 // collisions by keeping such keys in a persistent list.
-type lNode struct {
-	list.PersistentList
+type lNode[V any] struct {
+	list.PersistentListOf[*sNode[V]]
 }
 
 // entry returns the first S-node contained in the L-node.
-func (l *lNode) entry() *sNode {
+func (l *lNode[V]) entry() *sNode[V] {
 	head, _ := l.Head()
-	return head.(*sNode)
+	return head
 }
 
 // lookup returns the value at the given entry in the L-node or returns false
 // if it's not contained.
-func (l *lNode) lookup(e *Entry) (interface{}, bool) {
-	found, ok := l.Find(func(sn interface{}) bool {
-		return bytes.Equal(e.Key, sn.(*sNode).Key)
+func (l *lNode[V]) lookup(e *EntryOf[V]) (V, bool) {
+	found, ok := l.Find(func(sn *sNode[V]) bool {
+		return bytes.Equal(e.Key, sn.Key)
 	})
 	if !ok {
-		return nil, false
+		var zero V
+		return zero, false
 	}
-	return found.(*sNode).Value, true
+	return found.Value, true
 }
 
 // inserted creates a new L-node with the added entry.
-func (l *lNode) inserted(entry *Entry) *lNode {
-	return &lNode{l.removed(entry).Add(&sNode{entry})}
+func (l *lNode[V]) inserted(entry *EntryOf[V]) *lNode[V] {
+	return &lNode[V]{l.removed(entry).Add(&sNode[V]{entry})}
 }
 
 // removed creates a new L-node with the entry removed.
-func (l *lNode) removed(e *Entry) *lNode {
-	idx := l.FindIndex(func(sn interface{}) bool {
-		return bytes.Equal(e.Key, sn.(*sNode).Key)
+func (l *lNode[V]) removed(e *EntryOf[V]) *lNode[V] {
+	idx := l.FindIndex(func(sn *sNode[V]) bool {
+		return bytes.Equal(e.Key, sn.Key)
 	})
 	if idx < 0 {
 		return l
 	}
 	nl, _ := l.Remove(uint(idx))
-	return &lNode{nl}
+	return &lNode[V]{nl}
 }
 
 // length returns the L-node list length.
-func (l *lNode) length() uint {
+func (l *lNode[V]) length() uint {
 	return l.Length()
 }
 
 // branch is either an iNode or sNode.
 type branch interface{}
 
-// Entry contains a Ctrie key-value pair.
-type Entry struct {
+// EntryOf contains a CtrieOf key-value pair.
+type EntryOf[V any] struct {
 	Key   []byte
-	Value interface{}
+	Value V
 	hash  uint32
 }
 
+// Entry contains a Ctrie key-value pair.
+type Entry = EntryOf[interface{}]
+
 // sNode is a singleton node which contains a single key and value.
-type sNode struct {
-	*Entry
+type sNode[V any] struct {
+	*EntryOf[V]
 }
 
 // New creates an empty Ctrie which uses the provided HashFactory for key
 // hashing. If nil is passed in, it will default to FNV-1a hashing.
 func New(hashFactory HashFactory) *Ctrie {
+	return NewOf[interface{}](hashFactory)
+}
+
+// NewOf creates an empty CtrieOf which uses the provided HashFactory for key
+// hashing. If nil is passed in, it will default to FNV-1a hashing.
+func NewOf[V any](hashFactory HashFactory) *CtrieOf[V] {
 	if hashFactory == nil {
 		hashFactory = defaultHashFactory
 	}
-	root := &iNode{main: &mainNode{cNode: &cNode{}}}
+	root := &iNode[V]{main: &mainNode[V]{cNode: &cNode[V]{}}}
 	return newCtrie(root, hashFactory, false)
 }
 
-func newCtrie(root *iNode, hashFactory HashFactory, readOnly bool) *Ctrie {
-	return &Ctrie{
+func newCtrie[V any](root *iNode[V], hashFactory HashFactory, readOnly bool) *CtrieOf[V] {
+	return &CtrieOf[V]{
 		root:        root,
 		hashFactory: hashFactory,
 		readOnly:    readOnly,
@@ -284,9 +300,9 @@ func newCtrie(root *iNode, hashFactory HashFactory, readOnly bool) *Ctrie {
 
 // Insert adds the key-value pair to the Ctrie, replacing the existing value if
 // the key already exists.
-func (c *Ctrie) Insert(key []byte, value interface{}) {
+func (c *CtrieOf[V]) Insert(key []byte, value V) {
 	c.assertReadWrite()
-	c.insert(&Entry{
+	c.insert(&EntryOf[V]{
 		Key:   key,
 		Value: value,
 		hash:  c.hash(key),
@@ -295,31 +311,31 @@ func (c *Ctrie) Insert(key []byte, value interface{}) {
 
 // Lookup returns the value for the associated key or returns false if the key
 // doesn't exist.
-func (c *Ctrie) Lookup(key []byte) (interface{}, bool) {
-	return c.lookup(&Entry{Key: key, hash: c.hash(key)})
+func (c *CtrieOf[V]) Lookup(key []byte) (V, bool) {
+	return c.lookup(&EntryOf[V]{Key: key, hash: c.hash(key)})
 }
 
 // Remove deletes the value for the associated key, returning true if it was
 // removed or false if the entry doesn't exist.
-func (c *Ctrie) Remove(key []byte) (interface{}, bool) {
+func (c *CtrieOf[V]) Remove(key []byte) (V, bool) {
 	c.assertReadWrite()
-	return c.remove(&Entry{Key: key, hash: c.hash(key)})
+	return c.remove(&EntryOf[V]{Key: key, hash: c.hash(key)})
 }
 
 // Snapshot returns a stable, point-in-time snapshot of the Ctrie. If the Ctrie
 // is read-only, the returned Ctrie will also be read-only.
-func (c *Ctrie) Snapshot() *Ctrie {
+func (c *CtrieOf[V]) Snapshot() *CtrieOf[V] {
 	return c.snapshot(c.readOnly)
 }
 
 // ReadOnlySnapshot returns a stable, point-in-time snapshot of the Ctrie which
 // is read-only. Write operations on a read-only snapshot will panic.
-func (c *Ctrie) ReadOnlySnapshot() *Ctrie {
+func (c *CtrieOf[V]) ReadOnlySnapshot() *CtrieOf[V] {
 	return c.snapshot(true)
 }
 
 // snapshot wraps up the CAS logic to make a snapshot or a read-only snapshot.
-func (c *Ctrie) snapshot(readOnly bool) *Ctrie {
+func (c *CtrieOf[V]) snapshot(readOnly bool) *CtrieOf[V] {
 	if readOnly && c.readOnly {
 		return c
 	}
@@ -340,12 +356,12 @@ func (c *Ctrie) snapshot(readOnly bool) *Ctrie {
 }
 
 // Clear removes all keys from the Ctrie.
-func (c *Ctrie) Clear() {
+func (c *CtrieOf[V]) Clear() {
 	for {
 		root := c.readRoot()
 		gen := &generation{}
-		newRoot := &iNode{
-			main: &mainNode{cNode: &cNode{array: make([]branch, 0), gen: gen}},
+		newRoot := &iNode[V]{
+			main: &mainNode[V]{cNode: &cNode[V]{array: make([]branch, 0), gen: gen}},
 			gen:  gen,
 		}
 		if c.rdcssRoot(root, gcasRead(root, c), newRoot) {
@@ -358,8 +374,8 @@ func (c *Ctrie) Clear() {
 // cancel channel is provided, closing it will terminate and close the iterator
 // channel. Note that if a cancel channel is not used and not every entry is
 // read from the iterator, a goroutine will leak.
-func (c *Ctrie) Iterator(cancel <-chan struct{}) <-chan *Entry {
-	ch := make(chan *Entry)
+func (c *CtrieOf[V]) Iterator(cancel <-chan struct{}) <-chan *EntryOf[V] {
+	ch := make(chan *EntryOf[V])
 	snapshot := c.ReadOnlySnapshot()
 	go func() {
 		snapshot.traverse(snapshot.readRoot(), ch, cancel)
@@ -369,7 +385,7 @@ func (c *Ctrie) Iterator(cancel <-chan struct{}) <-chan *Entry {
 }
 
 // Size returns the number of keys in the Ctrie.
-func (c *Ctrie) Size() uint {
+func (c *CtrieOf[V]) Size() uint {
 	// TODO: The size operation can be optimized further by caching the size
 	// information in main nodes of a read-only Ctrie – this reduces the
 	// amortized complexity of the size operation to O(1) because the size
@@ -384,37 +400,37 @@ func (c *Ctrie) Size() uint {
 
 var errCanceled = errors.New("canceled")
 
-func (c *Ctrie) traverse(i *iNode, ch chan<- *Entry, cancel <-chan struct{}) error {
+func (c *CtrieOf[V]) traverse(i *iNode[V], ch chan<- *EntryOf[V], cancel <-chan struct{}) error {
 	main := gcasRead(i, c)
 	switch {
 	case main.cNode != nil:
 		for _, br := range main.cNode.array {
 			switch b := br.(type) {
-			case *iNode:
+			case *iNode[V]:
 				if err := c.traverse(b, ch, cancel); err != nil {
 					return err
 				}
-			case *sNode:
+			case *sNode[V]:
 				select {
-				case ch <- b.Entry:
+				case ch <- b.EntryOf:
 				case <-cancel:
 					return errCanceled
 				}
 			}
 		}
 	case main.lNode != nil:
-		for _, e := range main.lNode.Map(func(sn interface{}) interface{} {
-			return sn.(*sNode).Entry
+		for _, sn := range main.lNode.Map(func(sn *sNode[V]) *sNode[V] {
+			return sn
 		}) {
 			select {
-			case ch <- e.(*Entry):
+			case ch <- sn.EntryOf:
 			case <-cancel:
 				return errCanceled
 			}
 		}
 	case main.tNode != nil:
 		select {
-		case ch <- main.tNode.Entry:
+		case ch <- main.tNode.EntryOf:
 		case <-cancel:
 			return errCanceled
 		}
@@ -422,20 +438,20 @@ func (c *Ctrie) traverse(i *iNode, ch chan<- *Entry, cancel <-chan struct{}) err
 	return nil
 }
 
-func (c *Ctrie) assertReadWrite() {
+func (c *CtrieOf[V]) assertReadWrite() {
 	if c.readOnly {
 		panic("Cannot modify read-only snapshot")
 	}
 }
 
-func (c *Ctrie) insert(entry *Entry) {
+func (c *CtrieOf[V]) insert(entry *EntryOf[V]) {
 	root := c.readRoot()
 	if !c.iinsert(root, entry, 0, nil, root.gen) {
 		c.insert(entry)
 	}
 }
 
-func (c *Ctrie) lookup(entry *Entry) (interface{}, bool) {
+func (c *CtrieOf[V]) lookup(entry *EntryOf[V]) (V, bool) {
 	root := c.readRoot()
 	result, exists, ok := c.ilookup(root, entry, 0, nil, root.gen)
 	for !ok {
@@ -444,7 +460,7 @@ func (c *Ctrie) lookup(entry *Entry) (interface{}, bool) {
 	return result, exists
 }
 
-func (c *Ctrie) remove(entry *Entry) (interface{}, bool) {
+func (c *CtrieOf[V]) remove(entry *EntryOf[V]) (V, bool) {
 	root := c.readRoot()
 	result, exists, ok := c.iremove(root, entry, 0, nil, root.gen)
 	for !ok {
@@ -453,7 +469,7 @@ func (c *Ctrie) remove(entry *Entry) (interface{}, bool) {
 	return result, exists
 }
 
-func (c *Ctrie) hash(k []byte) uint32 {
+func (c *CtrieOf[V]) hash(k []byte) uint32 {
 	hasher := c.hashFactory()
 	hasher.Write(k)
 	return hasher.Sum32()
@@ -461,7 +477,7 @@ func (c *Ctrie) hash(k []byte) uint32 {
 
 // iinsert attempts to insert the entry into the Ctrie. If false is returned,
 // the operation should be retried.
-func (c *Ctrie) iinsert(i *iNode, entry *Entry, lev uint, parent *iNode, startGen *generation) bool {
+func (c *CtrieOf[V]) iinsert(i *iNode[V], entry *EntryOf[V], lev uint, parent *iNode[V], startGen *generation) bool {
 	// Linearization point.
 	main := gcasRead(i, c)
 	switch {
@@ -476,25 +492,25 @@ func (c *Ctrie) iinsert(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 			if cn.gen != i.gen {
 				rn = cn.renewed(i.gen, c)
 			}
-			ncn := &mainNode{cNode: rn.inserted(pos, flag, &sNode{entry}, i.gen)}
+			ncn := &mainNode[V]{cNode: rn.inserted(pos, flag, &sNode[V]{entry}, i.gen)}
 			return gcas(i, main, ncn, c)
 		}
 		// If the relevant bit is present in the bitmap, then its corresponding
 		// branch is read from the array.
 		branch := cn.array[pos]
 		switch branch.(type) {
-		case *iNode:
+		case *iNode[V]:
 			// If the branch is an I-node, then iinsert is called recursively.
-			in := branch.(*iNode)
+			in := branch.(*iNode[V])
 			if startGen == in.gen {
 				return c.iinsert(in, entry, lev+w, i, startGen)
 			}
-			if gcas(i, main, &mainNode{cNode: cn.renewed(startGen, c)}, c) {
+			if gcas(i, main, &mainNode[V]{cNode: cn.renewed(startGen, c)}, c) {
 				return c.iinsert(i, entry, lev, parent, startGen)
 			}
 			return false
-		case *sNode:
-			sn := branch.(*sNode)
+		case *sNode[V]:
+			sn := branch.(*sNode[V])
 			if !bytes.Equal(sn.Key, entry.Key) {
 				// If the branch is an S-node and its key is not equal to the
 				// key being inserted, then the Ctrie has to be extended with
@@ -507,15 +523,15 @@ func (c *Ctrie) iinsert(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 				if cn.gen != i.gen {
 					rn = cn.renewed(i.gen, c)
 				}
-				nsn := &sNode{entry}
-				nin := &iNode{main: newMainNode(sn, sn.hash, nsn, nsn.hash, lev+w, i.gen), gen: i.gen}
-				ncn := &mainNode{cNode: rn.updated(pos, nin, i.gen)}
+				nsn := &sNode[V]{entry}
+				nin := &iNode[V]{main: newMainNode(sn, sn.hash, nsn, nsn.hash, lev+w, i.gen), gen: i.gen}
+				ncn := &mainNode[V]{cNode: rn.updated(pos, nin, i.gen)}
 				return gcas(i, main, ncn, c)
 			}
 			// If the key in the S-node is equal to the key being inserted,
 			// then the C-node is replaced with its updated version with a new
 			// S-node. The linearization point is a successful CAS.
-			ncn := &mainNode{cNode: cn.updated(pos, &sNode{entry}, i.gen)}
+			ncn := &mainNode[V]{cNode: cn.updated(pos, &sNode[V]{entry}, i.gen)}
 			return gcas(i, main, ncn, c)
 		default:
 			panic("Ctrie is in an invalid state")
@@ -524,7 +540,7 @@ func (c *Ctrie) iinsert(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 		clean(parent, lev-w, c)
 		return false
 	case main.lNode != nil:
-		nln := &mainNode{lNode: main.lNode.inserted(entry)}
+		nln := &mainNode[V]{lNode: main.lNode.inserted(entry)}
 		return gcas(i, main, nln, c)
 	default:
 		panic("Ctrie is in an invalid state")
@@ -535,7 +551,8 @@ func (c *Ctrie) iinsert(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 // values are the entry value and whether or not the entry was contained in the
 // Ctrie. The last bool indicates if the operation succeeded. False means it
 // should be retried.
-func (c *Ctrie) ilookup(i *iNode, entry *Entry, lev uint, parent *iNode, startGen *generation) (interface{}, bool, bool) {
+func (c *CtrieOf[V]) ilookup(i *iNode[V], entry *EntryOf[V], lev uint, parent *iNode[V], startGen *generation) (V, bool, bool) {
+	var zero V
 	// Linearization point.
 	main := gcasRead(i, c)
 	switch {
@@ -545,33 +562,33 @@ func (c *Ctrie) ilookup(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 		if cn.bmp&flag == 0 {
 			// If the bitmap does not contain the relevant bit, a key with the
 			// required hashcode prefix is not present in the trie.
-			return nil, false, true
+			return zero, false, true
 		}
 		// Otherwise, the relevant branch at index pos is read from the array.
 		branch := cn.array[pos]
 		switch branch.(type) {
-		case *iNode:
+		case *iNode[V]:
 			// If the branch is an I-node, the ilookup procedure is called
 			// recursively at the next level.
-			in := branch.(*iNode)
+			in := branch.(*iNode[V])
 			if c.readOnly || startGen == in.gen {
 				return c.ilookup(in, entry, lev+w, i, startGen)
 			}
-			if gcas(i, main, &mainNode{cNode: cn.renewed(startGen, c)}, c) {
+			if gcas(i, main, &mainNode[V]{cNode: cn.renewed(startGen, c)}, c) {
 				return c.ilookup(i, entry, lev, parent, startGen)
 			}
-			return nil, false, false
-		case *sNode:
+			return zero, false, false
+		case *sNode[V]:
 			// If the branch is an S-node, then the key within the S-node is
 			// compared with the key being searched – these two keys have the
 			// same hashcode prefixes, but they need not be equal. If they are
 			// equal, the corresponding value from the S-node is
 			// returned and a NOTFOUND value otherwise.
-			sn := branch.(*sNode)
+			sn := branch.(*sNode[V])
 			if bytes.Equal(sn.Key, entry.Key) {
 				return sn.Value, true, true
 			}
-			return nil, false, true
+			return zero, false, true
 		default:
 			panic("Ctrie is in an invalid state")
 		}
@@ -591,7 +608,8 @@ func (c *Ctrie) ilookup(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 // values are the entry value and whether or not the entry was contained in the
 // Ctrie. The last bool indicates if the operation succeeded. False means it
 // should be retried.
-func (c *Ctrie) iremove(i *iNode, entry *Entry, lev uint, parent *iNode, startGen *generation) (interface{}, bool, bool) {
+func (c *CtrieOf[V]) iremove(i *iNode[V], entry *EntryOf[V], lev uint, parent *iNode[V], startGen *generation) (V, bool, bool) {
+	var zero V
 	// Linearization point.
 	main := gcasRead(i, c)
 	switch {
@@ -601,29 +619,29 @@ func (c *Ctrie) iremove(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 		if cn.bmp&flag == 0 {
 			// If the bitmap does not contain the relevant bit, a key with the
 			// required hashcode prefix is not present in the trie.
-			return nil, false, true
+			return zero, false, true
 		}
 		// Otherwise, the relevant branch at index pos is read from the array.
 		branch := cn.array[pos]
 		switch branch.(type) {
-		case *iNode:
+		case *iNode[V]:
 			// If the branch is an I-node, the iremove procedure is called
 			// recursively at the next level.
-			in := branch.(*iNode)
+			in := branch.(*iNode[V])
 			if startGen == in.gen {
 				return c.iremove(in, entry, lev+w, i, startGen)
 			}
-			if gcas(i, main, &mainNode{cNode: cn.renewed(startGen, c)}, c) {
+			if gcas(i, main, &mainNode[V]{cNode: cn.renewed(startGen, c)}, c) {
 				return c.iremove(i, entry, lev, parent, startGen)
 			}
-			return nil, false, false
-		case *sNode:
+			return zero, false, false
+		case *sNode[V]:
 			// If the branch is an S-node, its key is compared against the key
 			// being removed.
-			sn := branch.(*sNode)
+			sn := branch.(*sNode[V])
 			if !bytes.Equal(sn.Key, entry.Key) {
 				// If the keys are not equal, the NOTFOUND value is returned.
-				return nil, false, true
+				return zero, false, true
 			}
 			//  If the keys are equal, a copy of the current node without the
 			//  S-node is created. The contraction of the copy is then created
@@ -642,15 +660,15 @@ func (c *Ctrie) iremove(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 				}
 				return sn.Value, true, true
 			}
-			return nil, false, false
+			return zero, false, false
 		default:
 			panic("Ctrie is in an invalid state")
 		}
 	case main.tNode != nil:
 		clean(parent, lev-w, c)
-		return nil, false, false
+		return zero, false, false
 	case main.lNode != nil:
-		nln := &mainNode{lNode: main.lNode.removed(entry)}
+		nln := &mainNode[V]{lNode: main.lNode.removed(entry)}
 		if nln.lNode.length() == 1 {
 			nln = entomb(nln.lNode.entry())
 		}
@@ -658,7 +676,7 @@ func (c *Ctrie) iremove(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 			val, ok := main.lNode.lookup(entry)
 			return val, ok, true
 		}
-		return nil, false, true
+		return zero, false, true
 	default:
 		panic("Ctrie is in an invalid state")
 	}
@@ -668,51 +686,51 @@ func (c *Ctrie) iremove(i *iNode, entry *Entry, lev uint, parent *iNode, startGe
 // with at least one branch. If a given C-Node has only a single S-node below
 // it and is not at the root level, a T-node which wraps the S-node is
 // returned.
-func toContracted(cn *cNode, lev uint) *mainNode {
+func toContracted[V any](cn *cNode[V], lev uint) *mainNode[V] {
 	if lev > 0 && len(cn.array) == 1 {
 		branch := cn.array[0]
 		switch branch.(type) {
-		case *sNode:
-			return entomb(branch.(*sNode))
+		case *sNode[V]:
+			return entomb(branch.(*sNode[V]))
 		default:
-			return &mainNode{cNode: cn}
+			return &mainNode[V]{cNode: cn}
 		}
 	}
-	return &mainNode{cNode: cn}
+	return &mainNode[V]{cNode: cn}
 }
 
 // toCompressed compacts the C-node as a performance optimization.
-func toCompressed(cn *cNode, lev uint) *mainNode {
+func toCompressed[V any](cn *cNode[V], lev uint) *mainNode[V] {
 	tmpArray := make([]branch, len(cn.array))
 	for i, sub := range cn.array {
 		switch sub.(type) {
-		case *iNode:
-			inode := sub.(*iNode)
+		case *iNode[V]:
+			inode := sub.(*iNode[V])
 			mainPtr := (*unsafe.Pointer)(unsafe.Pointer(&inode.main))
-			main := (*mainNode)(atomic.LoadPointer(mainPtr))
+			main := (*mainNode[V])(atomic.LoadPointer(mainPtr))
 			tmpArray[i] = resurrect(inode, main)
-		case *sNode:
+		case *sNode[V]:
 			tmpArray[i] = sub
 		default:
 			panic("Ctrie is in an invalid state")
 		}
 	}
 
-	return toContracted(&cNode{bmp: cn.bmp, array: tmpArray}, lev)
+	return toContracted(&cNode[V]{bmp: cn.bmp, array: tmpArray}, lev)
 }
 
-func entomb(m *sNode) *mainNode {
-	return &mainNode{tNode: &tNode{m}}
+func entomb[V any](m *sNode[V]) *mainNode[V] {
+	return &mainNode[V]{tNode: &tNode[V]{m}}
 }
 
-func resurrect(iNode *iNode, main *mainNode) branch {
+func resurrect[V any](iNode *iNode[V], main *mainNode[V]) branch {
 	if main.tNode != nil {
 		return main.tNode.untombed()
 	}
 	return iNode
 }
 
-func clean(i *iNode, lev uint, ctrie *Ctrie) bool {
+func clean[V any](i *iNode[V], lev uint, ctrie *CtrieOf[V]) bool {
 	main := gcasRead(i, ctrie)
 	if main.cNode != nil {
 		return gcas(i, main, toCompressed(main.cNode, lev), ctrie)
@@ -720,23 +738,23 @@ func clean(i *iNode, lev uint, ctrie *Ctrie) bool {
 	return true
 }
 
-func cleanReadOnly(tn *tNode, lev uint, p *iNode, ctrie *Ctrie, entry *Entry) (val interface{}, exists bool, ok bool) {
+func cleanReadOnly[V any](tn *tNode[V], lev uint, p *iNode[V], ctrie *CtrieOf[V], entry *EntryOf[V]) (val V, exists bool, ok bool) {
 	if !ctrie.readOnly {
 		clean(p, lev-5, ctrie)
-		return nil, false, false
+		return val, false, false
 	}
 	if tn.hash == entry.hash && bytes.Equal(tn.Key, entry.Key) {
 		return tn.Value, true, true
 	}
-	return nil, false, true
+	return val, false, true
 }
 
-func cleanParent(p, i *iNode, hc uint32, lev uint, ctrie *Ctrie, startGen *generation) {
+func cleanParent[V any](p, i *iNode[V], hc uint32, lev uint, ctrie *CtrieOf[V], startGen *generation) {
 	var (
 		mainPtr  = (*unsafe.Pointer)(unsafe.Pointer(&i.main))
-		main     = (*mainNode)(atomic.LoadPointer(mainPtr))
+		main     = (*mainNode[V])(atomic.LoadPointer(mainPtr))
 		pMainPtr = (*unsafe.Pointer)(unsafe.Pointer(&p.main))
-		pMain    = (*mainNode)(atomic.LoadPointer(pMainPtr))
+		pMain    = (*mainNode[V])(atomic.LoadPointer(pMainPtr))
 	)
 	if pMain.cNode != nil {
 		flag, pos := flagPos(hc, lev, pMain.cNode.bmp)
@@ -773,7 +791,7 @@ func bitCount(x uint32) uint32 {
 // failures that occur due to the snapshot being taken. This ensures that the
 // write occurs only if the Ctrie root generation has remained the same in
 // addition to the I-node having the expected value.
-func gcas(in *iNode, old, n *mainNode, ct *Ctrie) bool {
+func gcas[V any](in *iNode[V], old, n *mainNode[V], ct *CtrieOf[V]) bool {
 	prevPtr := (*unsafe.Pointer)(unsafe.Pointer(&n.prev))
 	atomic.StorePointer(prevPtr, unsafe.Pointer(old))
 	if atomic.CompareAndSwapPointer(
@@ -786,9 +804,9 @@ func gcas(in *iNode, old, n *mainNode, ct *Ctrie) bool {
 }
 
 // gcasRead performs a GCAS-linearizable read of the I-node's main node.
-func gcasRead(in *iNode, ctrie *Ctrie) *mainNode {
-	m := (*mainNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&in.main))))
-	prev := (*mainNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&m.prev))))
+func gcasRead[V any](in *iNode[V], ctrie *CtrieOf[V]) *mainNode[V] {
+	m := (*mainNode[V])(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&in.main))))
+	prev := (*mainNode[V])(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&m.prev))))
 	if prev == nil {
 		return m
 	}
@@ -796,12 +814,12 @@ func gcasRead(in *iNode, ctrie *Ctrie) *mainNode {
 }
 
 // gcasComplete commits the GCAS operation.
-func gcasComplete(i *iNode, m *mainNode, ctrie *Ctrie) *mainNode {
+func gcasComplete[V any](i *iNode[V], m *mainNode[V], ctrie *CtrieOf[V]) *mainNode[V] {
 	for {
 		if m == nil {
 			return nil
 		}
-		prev := (*mainNode)(atomic.LoadPointer(
+		prev := (*mainNode[V])(atomic.LoadPointer(
 			(*unsafe.Pointer)(unsafe.Pointer(&m.prev))))
 		root := ctrie.rdcssReadRoot(true)
 		if prev == nil {
@@ -815,7 +833,7 @@ func gcasComplete(i *iNode, m *mainNode, ctrie *Ctrie) *mainNode {
 				unsafe.Pointer(m), unsafe.Pointer(fn)) {
 				return fn
 			}
-			m = (*mainNode)(atomic.LoadPointer(
+			m = (*mainNode[V])(atomic.LoadPointer(
 				(*unsafe.Pointer)(unsafe.Pointer(&i.main))))
 			continue
 		}
@@ -834,8 +852,8 @@ func gcasComplete(i *iNode, m *mainNode, ctrie *Ctrie) *mainNode {
 		atomic.CompareAndSwapPointer(
 			(*unsafe.Pointer)(unsafe.Pointer(&m.prev)),
 			unsafe.Pointer(prev),
-			unsafe.Pointer(&mainNode{failed: prev}))
-		m = (*mainNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&i.main))))
+			unsafe.Pointer(&mainNode[V]{failed: prev}))
+		m = (*mainNode[V])(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&i.main))))
 		return gcasComplete(i, m, ctrie)
 	}
 }
@@ -843,24 +861,24 @@ func gcasComplete(i *iNode, m *mainNode, ctrie *Ctrie) *mainNode {
 // rdcssDescriptor is an intermediate struct which communicates the intent to
 // replace the value in an I-node and check that the root's generation has not
 // changed before committing to the new value.
-type rdcssDescriptor struct {
-	old       *iNode
-	expected  *mainNode
-	nv        *iNode
+type rdcssDescriptor[V any] struct {
+	old       *iNode[V]
+	expected  *mainNode[V]
+	nv        *iNode[V]
 	committed int32
 }
 
 // readRoot performs a linearizable read of the Ctrie root. This operation is
 // prioritized so that if another thread performs a GCAS on the root, a
 // deadlock does not occur.
-func (c *Ctrie) readRoot() *iNode {
+func (c *CtrieOf[V]) readRoot() *iNode[V] {
 	return c.rdcssReadRoot(false)
 }
 
 // rdcssReadRoot performs a RDCSS-linearizable read of the Ctrie root with the
 // given priority.
-func (c *Ctrie) rdcssReadRoot(abort bool) *iNode {
-	r := (*iNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&c.root))))
+func (c *CtrieOf[V]) rdcssReadRoot(abort bool) *iNode[V] {
+	r := (*iNode[V])(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&c.root))))
 	if r.rdcss != nil {
 		return c.rdcssComplete(abort)
 	}
@@ -870,9 +888,9 @@ func (c *Ctrie) rdcssReadRoot(abort bool) *iNode {
 // rdcssRoot performs a RDCSS on the Ctrie root. This is used to create a
 // snapshot of the Ctrie by copying the root I-node and setting it to a new
 // generation.
-func (c *Ctrie) rdcssRoot(old *iNode, expected *mainNode, nv *iNode) bool {
-	desc := &iNode{
-		rdcss: &rdcssDescriptor{
+func (c *CtrieOf[V]) rdcssRoot(old *iNode[V], expected *mainNode[V], nv *iNode[V]) bool {
+	desc := &iNode[V]{
+		rdcss: &rdcssDescriptor[V]{
 			old:      old,
 			expected: expected,
 			nv:       nv,
@@ -886,9 +904,9 @@ func (c *Ctrie) rdcssRoot(old *iNode, expected *mainNode, nv *iNode) bool {
 }
 
 // rdcssComplete commits the RDCSS operation.
-func (c *Ctrie) rdcssComplete(abort bool) *iNode {
+func (c *CtrieOf[V]) rdcssComplete(abort bool) *iNode[V] {
 	for {
-		r := (*iNode)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&c.root))))
+		r := (*iNode[V])(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&c.root))))
 		if r.rdcss == nil {
 			return r
 		}
@@ -924,7 +942,7 @@ func (c *Ctrie) rdcssComplete(abort bool) *iNode {
 }
 
 // casRoot performs a CAS on the Ctrie root.
-func (c *Ctrie) casRoot(ov, nv *iNode) bool {
+func (c *CtrieOf[V]) casRoot(ov, nv *iNode[V]) bool {
 	c.assertReadWrite()
 	return atomic.CompareAndSwapPointer(
 		(*unsafe.Pointer)(unsafe.Pointer(&c.root)), unsafe.Pointer(ov), unsafe.Pointer(nv))
@@ -474,6 +474,33 @@ func TestHashCollision(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestCtrieOf(t *testing.T) {
+	assert := assert.New(t)
+	ctrie := NewOf[int](mockHashFactory)
+
+	val, ok := ctrie.Lookup([]byte("foo"))
+	assert.False(ok)
+	assert.Equal(0, val)
+
+	for i := 0; i < 10; i++ {
+		ctrie.Insert([]byte(strconv.Itoa(i)), i)
+	}
+
+	snapshot := ctrie.ReadOnlySnapshot()
+	for i := 0; i < 10; i++ {
+		val, ok = ctrie.Remove([]byte(strconv.Itoa(i)))
+		assert.True(ok)
+		assert.Equal(i, val)
+	}
+	assert.Equal(uint(0), ctrie.Size())
+
+	sum := 0
+	for entry := range snapshot.Iterator(nil) {
+		sum += entry.Value
+	}
+	assert.Equal(45, sum)
+}
+
 func BenchmarkInsert(b *testing.B) {
 	ctrie := New(nil)
 	b.ResetTimer()